@@ -0,0 +1,50 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+// CoercionPolicy specifies how to convert a property value
+// to a numeric type different from its own.
+//
+// It is consulted by the function PropMapGetWithPolicy.
+type CoercionPolicy int8
+
+const (
+	// AllowLossy allows any conversion permitted by Go,
+	// even if the value loses precision (e.g., 3.5 to int results in 3).
+	//
+	// It is the behavior of the function PropMapGet.
+	AllowLossy CoercionPolicy = 1 + iota
+
+	// RejectLossy allows a conversion between numeric types
+	// only if the value is preserved exactly (e.g., 3.0 to int),
+	// and rejects a conversion between a numeric type and
+	// a non-numeric type (e.g., int to string).
+	RejectLossy
+
+	// StrictNumeric rejects any conversion between different numeric types,
+	// as well as between a numeric type and a non-numeric type.
+	StrictNumeric
+)
+
+//go:generate stringer -type=CoercionPolicy -output=coercion_policy_string.go
+
+// IsValid reports whether the coercion policy is known.
+func (i CoercionPolicy) IsValid() bool {
+	return i >= AllowLossy && i <= StrictNumeric
+}
@@ -19,8 +19,12 @@
 package gosln
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/donyori/gogo/errors"
 )
 
 // Date represents a date (an instant in time with day precision).
@@ -84,6 +88,19 @@ func (d Date) Month() time.Month {
 	return d.GoTime().Month()
 }
 
+// Quarter returns the calendar quarter of the year specified by the date,
+// in the range [1,4]:
+// January to March is 1, April to June is 2,
+// July to September is 3, and October to December is 4.
+//
+// It is derived from the method Month.
+// In particular, for the zero-value Date, Month returns December
+// (as its GoTime is December 31 of the year -1),
+// so Quarter returns 4.
+func (d Date) Quarter() int {
+	return (int(d.Month())-1)/3 + 1
+}
+
 // Day returns the day of the month specified by the date.
 func (d Date) Day() int {
 	return d.GoTime().Day()
@@ -95,6 +112,14 @@ func (d Date) YearDay() int {
 	return d.yearDay
 }
 
+// IsLeapYear reports whether the year of the date is a leap year
+// in the proleptic Gregorian calendar.
+//
+// See function IsLeapYear for details.
+func (d Date) IsLeapYear() bool {
+	return IsLeapYear(d.year)
+}
+
 // Weekday returns the day of the week specified by the date.
 func (d Date) Weekday() time.Weekday {
 	return d.GoTime().Weekday()
@@ -126,6 +151,15 @@ func (d Date) After(date Date) bool {
 		d.year == date.year && d.yearDay > date.yearDay
 }
 
+// Equal reports whether this date and the specified date are the same,
+// that is, they have the same year and year-day.
+//
+// It is equivalent to d == date, and is provided for generic code
+// that relies on the method Equal, like that of time.Time.
+func (d Date) Equal(date Date) bool {
+	return d == date
+}
+
 // Compare compares this date (denoted by x)
 // and the specified date (denoted by y).
 //
@@ -178,3 +212,194 @@ func (d Date) AddYearMonthDay(years, months, days int) Date {
 func (d Date) String() string {
 	return fmt.Sprintf("%d-%03d", d.year, d.yearDay)
 }
+
+// ParseDate parses a date in the form produced by the method String
+// of Date (e.g., "2023-071").
+//
+// It accepts exactly that form: no surrounding whitespace,
+// no padding on the year, and exactly three digits for the year-day.
+// In particular, "0-000" is parsed as the zero-value Date,
+// which is the result of the method String of the zero-value Date.
+//
+// ParseDate reports a *InvalidDateError if s is not in such a form
+// or the year-day is out of range for the year.
+// (To test whether err is *InvalidDateError, use function errors.As.)
+func ParseDate(s string) (date Date, err error) {
+	date, ok := parseDateString(s)
+	if !ok {
+		err = errors.AutoWrap(NewInvalidDateError(s))
+	}
+	return
+}
+
+// MustParseDate is like ParseDate, but panic if s is invalid.
+func MustParseDate(s string) Date {
+	date, err := ParseDate(s)
+	if err != nil {
+		panic(errors.AutoWrap(err))
+	}
+	return date
+}
+
+// ParseDateYMD parses an ISO 8601 calendar date
+// in the form "YYYY-MM-DD" (e.g., "2023-03-12").
+//
+// It accepts exactly that form: a 4-digit year,
+// a 2-digit month, and a 2-digit day, separated by hyphens,
+// with no time component or surrounding whitespace.
+// The month and day must be in range (e.g., "2023-02-29" is rejected).
+//
+// ParseDateYMD reports an error if s is not in such a form.
+// To parse the form produced by the method String of Date
+// (e.g., "2023-071"), use function ParseDate.
+func ParseDateYMD(s string) (date Date, err error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, errors.AutoNew("date " + strconv.Quote(s) +
+			` is invalid; want an ISO 8601 calendar date in the form "YYYY-MM-DD" (e.g., "2023-03-12")`)
+	}
+	return DateOfYearMonthDay(t.Date()), nil
+}
+
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes the date as a JSON string in the form produced by
+// the method String (e.g., "2023-071").
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON string in the form produced by the method String
+// (e.g., "2023-071").
+// In particular, null and "" are decoded as the zero-value Date.
+//
+// It reports an error if data is not a JSON string or null.
+// It reports a *InvalidDateError if the string is malformed
+// or the year-day is out of range for the year.
+// (To test whether the error is *InvalidDateError, use function errors.As.)
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONString(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	return errors.AutoWrap(d.unmarshalString(s))
+}
+
+// MarshalText implements the interface encoding.TextMarshaler.
+//
+// It encodes the date in the form produced by the method String
+// (e.g., 2023-071).
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the interface encoding.TextUnmarshaler.
+//
+// It accepts the form produced by the method String (e.g., 2023-071).
+// In particular, the empty text is decoded as the zero-value Date.
+//
+// It reports a *InvalidDateError if text is not in such a form
+// or the year-day is out of range for the year
+// (i.e., [1,365] for non-leap years, and [1,366] for leap years).
+// (To test whether the error is *InvalidDateError, use function errors.As.)
+func (d *Date) UnmarshalText(text []byte) error {
+	return errors.AutoWrap(d.unmarshalString(string(text)))
+}
+
+// unmarshalString decodes the date from s
+// in the form produced by the method String.
+//
+// The empty string is decoded as the zero-value Date.
+// It returns a *InvalidDateError if s is malformed.
+func (d *Date) unmarshalString(s string) error {
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	date, ok := parseDateString(s)
+	if !ok {
+		return NewInvalidDateError(s)
+	}
+	*d = date
+	return nil
+}
+
+// parseDateString parses a string in the form produced by Date.String.
+//
+// It reports whether s is such a string with a year-day
+// in the range of the year.
+// In particular, it accepts "0-000" as the zero-value Date.
+func parseDateString(s string) (d Date, ok bool) {
+	i := len(s) - 4
+	if i < 1 || s[i] != '-' {
+		return
+	}
+	yearStr, yearDayStr := s[:i], s[i+1:]
+	digits := yearStr
+	if digits[0] == '-' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > 1 && digits[0] == '0' ||
+		digits == "0" && len(yearStr) > 1 {
+		return // reject empty, leading zeros, and "-0"
+	}
+	for j := 0; j < len(digits); j++ {
+		if digits[j] < '0' || digits[j] > '9' {
+			return
+		}
+	}
+	for j := 0; j < len(yearDayStr); j++ {
+		if yearDayStr[j] < '0' || yearDayStr[j] > '9' {
+			return
+		}
+	}
+	year, err := strconv.Atoi(yearStr)
+	if err != nil {
+		return
+	}
+	yearDay, err := strconv.Atoi(yearDayStr)
+	if err != nil {
+		return
+	}
+	if year == 0 && yearDay == 0 {
+		return Date{}, true
+	} else if yearDay < 1 || yearDay > daysInYear(year) {
+		return
+	}
+	return Date{year: year, yearDay: yearDay}, true
+}
+
+// IsLeapYear reports whether the specified year is a leap year
+// in the proleptic Gregorian calendar.
+//
+// A year is a leap year if it is divisible by 4,
+// except for the years divisible by 100 but not by 400.
+// In particular, the year 0 (1 BCE) is a leap year.
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// daysInYear returns the number of days in the specified year
+// in the proleptic Gregorian calendar.
+func daysInYear(year int) int {
+	if IsLeapYear(year) {
+		return 366
+	}
+	return 365
+}
+
+// unmarshalJSONString decodes a JSON string or null from data.
+//
+// null is decoded as an empty string.
+// It reports an error if data is not a JSON string or null.
+func unmarshalJSONString(data []byte) (s string, err error) {
+	if string(data) == "null" {
+		return "", nil
+	} else if len(data) == 0 || data[0] != '"' {
+		return "", errors.AutoNew("want a JSON string or null; got " + string(data))
+	}
+	err = json.Unmarshal(data, &s)
+	return
+}
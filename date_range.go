@@ -0,0 +1,79 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+// DateRange is a range of dates from Start to End, inclusive on both ends.
+//
+// A DateRange whose End is before its Start is empty.
+type DateRange struct {
+	Start Date // The first date in the range.
+	End   Date // The last date in the range.
+}
+
+// IsEmpty reports whether the range contains no date,
+// that is, its End is before its Start.
+func (dr DateRange) IsEmpty() bool {
+	return dr.End.Before(dr.Start)
+}
+
+// Contains reports whether the date d is in the range,
+// inclusive on both ends.
+//
+// It returns false if the range is empty.
+func (dr DateRange) Contains(d Date) bool {
+	return !d.Before(dr.Start) && !d.After(dr.End)
+}
+
+// Overlaps reports whether this range and the specified range
+// have any date in common.
+//
+// It returns false if either range is empty.
+func (dr DateRange) Overlaps(other DateRange) bool {
+	return !dr.IsEmpty() && !other.IsEmpty() &&
+		!dr.Start.After(other.End) && !other.Start.After(dr.End)
+}
+
+// Days returns the number of days in the range, inclusive on both ends.
+//
+// It returns 0 if the range is empty.
+func (dr DateRange) Days() int {
+	if dr.IsEmpty() {
+		return 0
+	}
+	return dayNumber(dr.End) - dayNumber(dr.Start) + 1
+}
+
+// dayNumber returns the number of days from
+// December 31 of the year 0 (in the proleptic Gregorian calendar)
+// to the date d.
+func dayNumber(d Date) int {
+	y := d.year - 1
+	return y*365 + floorDiv(y, 4) - floorDiv(y, 100) + floorDiv(y, 400) + d.yearDay
+}
+
+// floorDiv returns the quotient a/b rounded toward negative infinity.
+//
+// The caller should guarantee that b is positive.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b < 0 {
+		q--
+	}
+	return q
+}
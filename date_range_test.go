@@ -0,0 +1,97 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestDateRange(t *testing.T) {
+	ymd := func(year int, month time.Month, day int) gosln.Date {
+		return gosln.DateOfYearMonthDay(year, month, day)
+	}
+	q1 := gosln.DateRange{Start: ymd(2023, time.January, 1), End: ymd(2023, time.March, 31)}
+	q2 := gosln.DateRange{Start: ymd(2023, time.April, 1), End: ymd(2023, time.June, 30)}
+	feb := gosln.DateRange{Start: ymd(2023, time.February, 1), End: ymd(2023, time.February, 28)}
+	day := gosln.DateRange{Start: ymd(2023, time.March, 31), End: ymd(2023, time.March, 31)}
+	empty := gosln.DateRange{Start: ymd(2023, time.March, 1), End: ymd(2023, time.February, 1)}
+	crossYear := gosln.DateRange{Start: ymd(-1, time.December, 31), End: ymd(1, time.January, 1)}
+	leap := gosln.DateRange{Start: ymd(2000, time.January, 1), End: ymd(2000, time.December, 31)}
+
+	testCases := []struct {
+		name string
+		dr   gosln.DateRange
+		days int
+		in   []gosln.Date
+		out  []gosln.Date
+	}{
+		{"q1", q1, 90, []gosln.Date{q1.Start, q1.End, ymd(2023, time.February, 14)},
+			[]gosln.Date{ymd(2022, time.December, 31), ymd(2023, time.April, 1)}},
+		{"day", day, 1, []gosln.Date{day.Start}, []gosln.Date{ymd(2023, time.March, 30)}},
+		{"empty", empty, 0, nil, []gosln.Date{empty.Start, empty.End, ymd(2023, time.February, 14)}},
+		{"crossYear", crossYear, 368, []gosln.Date{ymd(0, time.February, 29)}, nil},
+		{"leap", leap, 366, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.dr.Days(); got != tc.days {
+				t.Errorf("got Days %d; want %d", got, tc.days)
+			}
+			for _, d := range tc.in {
+				if !tc.dr.Contains(d) {
+					t.Errorf("Contains(%v) got false; want true", d)
+				}
+			}
+			for _, d := range tc.out {
+				if tc.dr.Contains(d) {
+					t.Errorf("Contains(%v) got true; want false", d)
+				}
+			}
+		})
+	}
+
+	overlapCases := []struct {
+		a, b gosln.DateRange
+		want bool
+	}{
+		{q1, q2, false},
+		{q1, feb, true},
+		{q1, day, true},
+		{day, q2, false},
+		{q1, q1, true},
+		{q1, empty, false},
+		{empty, empty, false},
+	}
+
+	for i, tc := range overlapCases {
+		t.Run(fmt.Sprintf("overlaps case %d", i), func(t *testing.T) {
+			if got := tc.a.Overlaps(tc.b); got != tc.want {
+				t.Errorf("a.Overlaps(b) got %t; want %t", got, tc.want)
+			}
+			if got := tc.b.Overlaps(tc.a); got != tc.want {
+				t.Errorf("b.Overlaps(a) got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@
 package gosln_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -113,3 +115,261 @@ func TestDateOfYearMonthDay(t *testing.T) {
 		})
 	}
 }
+
+func TestDate_MarshalJSONAndUnmarshalJSON(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	data, err := json.Marshal(struct{ D gosln.Date }{D: date})
+	if err != nil {
+		t.Fatal("marshal -", err)
+	} else if got, want := string(data), `{"D":"2023-071"}`; got != want {
+		t.Errorf("marshal - got %s; want %s", got, want)
+	}
+
+	testCases := []struct {
+		data    string
+		want    gosln.Date
+		wantErr bool
+	}{
+		{`"2023-071"`, date, false},
+		{`"-5-001"`, gosln.DateOfYearMonthDay(-5, time.January, 1), false},
+		{`"2024-366"`, gosln.DateOfYearMonthDay(2024, time.December, 31), false},
+		{`"0-000"`, gosln.Date{}, false},
+		{`null`, gosln.Date{}, false},
+		{`""`, gosln.Date{}, false},
+		{`"2023-366"`, gosln.Date{}, true},
+		{`"2023-000"`, gosln.Date{}, true},
+		{`"2023-71"`, gosln.Date{}, true},
+		{`" 2023-071"`, gosln.Date{}, true},
+		{`"2023-03-12"`, gosln.Date{}, true},
+		{`"abc"`, gosln.Date{}, true},
+		{`2023071`, gosln.Date{}, true},
+		{`{}`, gosln.Date{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("data=%s", tc.data), func(t *testing.T) {
+			got := gosln.DateOfYearMonthDay(2000, time.January, 1) // non-zero
+			err := json.Unmarshal([]byte(tc.data), &got)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got %v, nil error; want an error", got)
+				}
+			} else if err != nil {
+				t.Error("unmarshal -", err)
+			} else if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDate_MarshalTextAndUnmarshalText(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	text, err := date.MarshalText()
+	if err != nil {
+		t.Fatal("marshal -", err)
+	} else if string(text) != date.String() {
+		t.Errorf("marshal - got %s; want %s", text, date)
+	}
+
+	testCases := []struct {
+		text    string
+		want    gosln.Date
+		wantErr bool
+	}{
+		{"2023-071", date, false},
+		{"2024-366", gosln.DateOfYearMonthDay(2024, time.December, 31), false},
+		{"2000-366", gosln.DateOfYearMonthDay(2000, time.December, 31), false},
+		{"", gosln.Date{}, false},
+		{"2023-366", gosln.Date{}, true},
+		{"1900-366", gosln.Date{}, true},
+		{"2023-000", gosln.Date{}, true},
+		{"2023-0071", gosln.Date{}, true},
+		{"02023-071", gosln.Date{}, true},
+		{"2023-071 ", gosln.Date{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("text=%q", tc.text), func(t *testing.T) {
+			var got gosln.Date
+			err := got.UnmarshalText([]byte(tc.text))
+			if tc.wantErr {
+				var e *gosln.InvalidDateError
+				if !errors.As(err, &e) {
+					t.Errorf("got error %v; want *InvalidDateError", err)
+				} else if e.DateString() != tc.text {
+					t.Errorf("got DateString %q; want %q", e.DateString(), tc.text)
+				}
+			} else if err != nil {
+				t.Error("unmarshal -", err)
+			} else if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Date as a map key, encoded via encoding.TextMarshaler.
+	m := map[gosln.Date]int{date: 1}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal("marshal map -", err)
+	} else if got, want := string(data), `{"2023-071":1}`; got != want {
+		t.Errorf("marshal map - got %s; want %s", got, want)
+	}
+	var decoded map[gosln.Date]int
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal("unmarshal map -", err)
+	} else if len(decoded) != 1 || decoded[date] != 1 {
+		t.Errorf("unmarshal map - got %v; want %v", decoded, m)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	testCases := []struct {
+		s       string
+		want    gosln.Date
+		wantErr bool
+	}{
+		{"2023-071", gosln.DateOfYearMonthDay(2023, time.March, 12), false},
+		{"2024-366", gosln.DateOfYearMonthDay(2024, time.December, 31), false},
+		{"1-001", gosln.DateOfYearMonthDay(1, time.January, 1), false},
+		{"-44-075", gosln.DateOfYearMonthDay(-44, time.March, 15), false},
+		{"0-000", gosln.Date{}, false},
+		{"", gosln.Date{}, true},
+		{" 2023-071", gosln.Date{}, true},
+		{"2023-071\n", gosln.Date{}, true},
+		{"2023-71", gosln.Date{}, true},
+		{"2023-0071", gosln.Date{}, true},
+		{"+2023-071", gosln.Date{}, true},
+		{"-0-001", gosln.Date{}, true},
+		{"2023-366", gosln.Date{}, true},
+		{"2023-000", gosln.Date{}, true},
+		{"2023-03-12", gosln.Date{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("s=%q", tc.s), func(t *testing.T) {
+			got, err := gosln.ParseDate(tc.s)
+			if tc.wantErr {
+				var e *gosln.InvalidDateError
+				if !errors.As(err, &e) {
+					t.Errorf("got %v, error %v; want *InvalidDateError", got, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			} else if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+			if s := got.String(); s != tc.s {
+				t.Errorf("round trip - got %q; want %q", s, tc.s)
+			}
+		})
+	}
+}
+
+func TestMustParseDate(t *testing.T) {
+	want := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	if got := gosln.MustParseDate("2023-071"); got != want {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic on invalid date")
+		}
+	}()
+	gosln.MustParseDate("2023-366")
+}
+
+func TestParseDateYMD(t *testing.T) {
+	testCases := []struct {
+		s       string
+		want    gosln.Date
+		wantErr bool
+	}{
+		{"2023-03-12", gosln.DateOfYearMonthDay(2023, time.March, 12), false},
+		{"2024-02-29", gosln.DateOfYearMonthDay(2024, time.February, 29), false},
+		{"0001-01-01", gosln.DateOfYearMonthDay(1, time.January, 1), false},
+		{"2023-3-12", gosln.Date{}, true},
+		{"2023-03-1", gosln.Date{}, true},
+		{"23-03-12", gosln.Date{}, true},
+		{"2023-02-29", gosln.Date{}, true},
+		{"2023-13-01", gosln.Date{}, true},
+		{"2023-03-12T00:00:00Z", gosln.Date{}, true},
+		{" 2023-03-12", gosln.Date{}, true},
+		{"2023-071", gosln.Date{}, true},
+		{"", gosln.Date{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("s=%q", tc.s), func(t *testing.T) {
+			got, err := gosln.ParseDateYMD(tc.s)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got %v, nil error; want an error", got)
+				}
+			} else if err != nil {
+				t.Error(err)
+			} else if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsLeapYear(t *testing.T) {
+	testCases := []struct {
+		year int
+		want bool
+	}{
+		{2023, false},
+		{2024, true},
+		{1900, false},
+		{2000, true},
+		{2100, false},
+		{0, true},
+		{-1, false},
+		{-4, true},
+		{-100, false},
+		{-400, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("year=%d", tc.year), func(t *testing.T) {
+			if got := gosln.IsLeapYear(tc.year); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+			date := gosln.DateOfYearMonthDay(tc.year, time.March, 1)
+			if got := date.IsLeapYear(); got != tc.want {
+				t.Errorf("Date.IsLeapYear - got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDate_Quarter(t *testing.T) {
+	testCases := []struct {
+		date gosln.Date
+		want int
+	}{
+		{gosln.DateOfYearMonthDay(2023, time.January, 1), 1},
+		{gosln.DateOfYearMonthDay(2023, time.March, 31), 1},
+		{gosln.DateOfYearMonthDay(2023, time.April, 1), 2},
+		{gosln.DateOfYearMonthDay(2023, time.June, 30), 2},
+		{gosln.DateOfYearMonthDay(2023, time.July, 1), 3},
+		{gosln.DateOfYearMonthDay(2023, time.September, 30), 3},
+		{gosln.DateOfYearMonthDay(2023, time.October, 1), 4},
+		{gosln.DateOfYearMonthDay(2023, time.December, 31), 4},
+		{gosln.DateOfYearMonthDay(2024, time.December, 31), 4},
+		{gosln.Date{}, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run("date="+tc.date.String(), func(t *testing.T) {
+			if got := tc.date.Quarter(); got != tc.want {
+				t.Errorf("got %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
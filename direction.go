@@ -0,0 +1,36 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+// Direction represents the direction of semantic links
+// relative to a semantic node.
+type Direction int8
+
+const (
+	DirOutgoing Direction = 1 + iota // outgoing
+	DirIncoming                      // incoming
+	DirBoth                          // both
+)
+
+//go:generate stringer -type=Direction -output=direction_string.go -linecomment
+
+// IsValid reports whether the direction is known.
+func (i Direction) IsValid() bool {
+	return i >= DirOutgoing && i <= DirBoth
+}
@@ -101,11 +101,57 @@ func (e *InvalidIDError) ID() ID {
 // If e is nil, it returns "<nil *InvalidIDError>".
 func (e *InvalidIDError) Error() string {
 	if e == nil {
-		return "<nil *InvalidTypeError>"
+		return "<nil *InvalidIDError>"
 	}
 	return "ID " + strconv.Quote(e.id.String()) + " is invalid"
 }
 
+// Unwrap returns nil, as InvalidIDError does not wrap any other error.
+//
+// It is provided so that InvalidIDError can be handled
+// in the same way as the errors wrapping others, such as ItemError.
+func (e *InvalidIDError) Unwrap() error {
+	return nil
+}
+
+// InvalidDateError is an error indicating that
+// the string representation of a date is invalid.
+type InvalidDateError struct {
+	s string // The string representation of the date.
+}
+
+var _ error = (*InvalidDateError)(nil)
+
+// NewInvalidDateError creates a new InvalidDateError
+// with the specified string representation of the date.
+func NewInvalidDateError(s string) *InvalidDateError {
+	return &InvalidDateError{s: s}
+}
+
+// DateString returns the string representation of the date recorded in e.
+//
+// If e is nil, it returns "<nil>".
+func (e *InvalidDateError) DateString() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return e.s
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *InvalidDateError>".
+func (e *InvalidDateError) Error() string {
+	if e == nil {
+		return "<nil *InvalidDateError>"
+	}
+	return "date " + strconv.Quote(e.s) + " is invalid; " +
+		`want the form "<YEAR>-<YEAR-DAY>" (e.g., "2023-071"), ` +
+		"where <YEAR> is a decimal integer with no padding, " +
+		`and <YEAR-DAY> is a 3-digit decimal integer padding with "0" ` +
+		"in the range of the year"
+}
+
 // InvalidPropNameError is an error indicating that
 // the property name is invalid.
 type InvalidPropNameError struct {
@@ -147,6 +193,11 @@ func (e *InvalidPropNameError) Error() string {
 // the property type is invalid.
 type InvalidPropTypeError struct {
 	t PropType // The property type.
+
+	// name is the property type name that failed to parse,
+	// valid only if hasName is true.
+	name    string
+	hasName bool
 }
 
 var _ error = (*InvalidPropTypeError)(nil)
@@ -174,21 +225,38 @@ func (e *InvalidPropTypeError) Error() string {
 	if e == nil {
 		return "<nil *InvalidPropTypeError>"
 	}
+	if e.hasName {
+		return "property type name " + strconv.Quote(e.name) + " is unknown"
+	}
 	return "property type " + e.t.String() + " is invalid"
 }
 
 // InvalidPropValueError is an error indicating that
 // the property value is invalid.
 type InvalidPropValueError struct {
-	value any // The property value.
+	name  PropName // The property name, zero-value if unknown.
+	value any      // The property value.
 }
 
 var _ error = (*InvalidPropValueError)(nil)
 
 // NewInvalidPropValueError creates a new InvalidPropValueError
-// with the specified property value.
-func NewInvalidPropValueError(propValue any) *InvalidPropValueError {
-	return &InvalidPropValueError{value: propValue}
+// with the specified property name and value.
+//
+// propName is the name of the property with the invalid value.
+// If the name is unknown, pass the zero-value PropName.
+func NewInvalidPropValueError(propName PropName, propValue any) *InvalidPropValueError {
+	return &InvalidPropValueError{name: propName, value: propValue}
+}
+
+// PropName returns the property name recorded in e.
+//
+// If e is nil or the name is unknown, it returns the zero-value PropName.
+func (e *InvalidPropValueError) PropName() PropName {
+	if e == nil {
+		return PropName{}
+	}
+	return e.name
 }
 
 // PropValue returns the property value recorded in e.
@@ -209,7 +277,13 @@ func (e *InvalidPropValueError) Error() string {
 		return "<nil *InvalidPropValueError>"
 	}
 	var b strings.Builder
-	b.WriteString("property value (type: ")
+	b.WriteString("property value ")
+	if e.name.IsValid() {
+		b.WriteString("of ")
+		b.WriteString(strconv.Quote(e.name.String()))
+		b.WriteByte(' ')
+	}
+	b.WriteString("(type: ")
 	_, _ = fmt.Fprintf(&b, "%#v", e.value) // ignore error as it is always nil
 	b.WriteString(") is invalid; the type of valid property value must be one of ")
 	for i := PropType(1); i.IsValid(); i++ {
@@ -259,6 +333,19 @@ func (e *PropNotExistError) Error() string {
 	return name + " does not exist"
 }
 
+// ErrPropType is an error indicating that a property has a wrong type.
+//
+// Every *PropTypeError matches ErrPropType with errors.Is,
+// so the client can use errors.Is to test whether an error is
+// caused by a property type mismatch,
+// regardless of the property name, value, and expected type.
+// To obtain them, use errors.As with *PropTypeError instead.
+var ErrPropType = errors.AutoNewCustom(
+	"property has wrong type",
+	errors.PrependFullPkgName,
+	0,
+)
+
 // PropTypeError is an error indicating that the property type is wrong.
 //
 // It records the property name, value, and expected type.
@@ -335,6 +422,108 @@ func (e *PropTypeError) Error() string {
 	return b.String()
 }
 
+// Is reports whether target is ErrPropType.
+//
+// It makes errors.Is(err, ErrPropType) report true
+// for any *PropTypeError err.
+func (e *PropTypeError) Is(target error) bool {
+	return target == ErrPropType
+}
+
+// LossyConversionError is an error indicating that
+// converting the property value to the expected type would lose precision.
+//
+// It records the property name, value, and expected type.
+type LossyConversionError struct {
+	name     PropName     // The property name.
+	value    any          // The property value.
+	wantType reflect.Type // The expected type.
+}
+
+var _ error = (*LossyConversionError)(nil)
+
+// NewLossyConversionError creates a new LossyConversionError with
+// the specified property name, value, and expected type.
+func NewLossyConversionError(
+	propName PropName,
+	propValue any,
+	wantType reflect.Type,
+) *LossyConversionError {
+	return &LossyConversionError{
+		name:     propName,
+		value:    propValue,
+		wantType: wantType,
+	}
+}
+
+// PropName returns the property name recorded in e.
+//
+// If e is nil, it returns a zero-value PropName.
+func (e *LossyConversionError) PropName() PropName {
+	if e == nil {
+		return PropName{}
+	}
+	return e.name
+}
+
+// PropValue returns the property value recorded in e.
+//
+// If e is nil, it returns nil.
+func (e *LossyConversionError) PropValue() any {
+	if e == nil {
+		return nil
+	}
+	return e.value
+}
+
+// WantType returns the expected type recorded in e.
+//
+// If e is nil, it returns nil.
+func (e *LossyConversionError) WantType() reflect.Type {
+	if e == nil {
+		return nil
+	}
+	return e.wantType
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *LossyConversionError>".
+func (e *LossyConversionError) Error() string {
+	if e == nil {
+		return "<nil *LossyConversionError>"
+	}
+	var b strings.Builder
+	b.WriteString(e.name.String())
+	if b.Len() == 0 {
+		b.WriteString("property")
+	}
+	b.WriteString(" value ")
+	b.WriteString(FormatPropValue(e.value))
+	b.WriteString(" (type: ")
+	b.WriteString(reflect.TypeOf(e.value).String())
+	b.WriteString(") cannot be converted to ")
+	if e.wantType != nil {
+		b.WriteString(e.wantType.String())
+	} else {
+		b.WriteString("<nil>")
+	}
+	b.WriteString(" without loss of precision")
+	return b.String()
+}
+
+// ErrNodeNotExist is an error indicating that a node does not exist.
+//
+// Every *NodeNotExistError matches ErrNodeNotExist with errors.Is,
+// so the client can use errors.Is to test whether an error is
+// caused by a nonexistent node, regardless of its ID.
+// To obtain the node ID, use errors.As with *NodeNotExistError instead.
+var ErrNodeNotExist = errors.AutoNewCustom(
+	"node does not exist",
+	errors.PrependFullPkgName,
+	0,
+)
+
 // NodeNotExistError is an error indicating that
 // the node with the specified ID does not exist.
 type NodeNotExistError struct {
@@ -369,6 +558,26 @@ func (e *NodeNotExistError) Error() string {
 	return "node " + strconv.Quote(e.id.String()) + " does not exist"
 }
 
+// Is reports whether target is ErrNodeNotExist.
+//
+// It makes errors.Is(err, ErrNodeNotExist) report true
+// for any *NodeNotExistError err.
+func (e *NodeNotExistError) Is(target error) bool {
+	return target == ErrNodeNotExist
+}
+
+// ErrLinkNotExist is an error indicating that a link does not exist.
+//
+// Every *LinkNotExistError matches ErrLinkNotExist with errors.Is,
+// so the client can use errors.Is to test whether an error is
+// caused by a nonexistent link, regardless of its ID.
+// To obtain the link ID, use errors.As with *LinkNotExistError instead.
+var ErrLinkNotExist = errors.AutoNewCustom(
+	"link does not exist",
+	errors.PrependFullPkgName,
+	0,
+)
+
 // LinkNotExistError is an error indicating that
 // the link with the specified ID does not exist.
 type LinkNotExistError struct {
@@ -402,3 +611,340 @@ func (e *LinkNotExistError) Error() string {
 	}
 	return "link " + strconv.Quote(e.id.String()) + " does not exist"
 }
+
+// Is reports whether target is ErrLinkNotExist.
+//
+// It makes errors.Is(err, ErrLinkNotExist) report true
+// for any *LinkNotExistError err.
+func (e *LinkNotExistError) Is(target error) bool {
+	return target == ErrLinkNotExist
+}
+
+// UnexpectedTypeError is an error indicating that
+// the type of a node or link is not the expected one.
+type UnexpectedTypeError struct {
+	id   ID   // The ID of the node or link.
+	got  Type // The actual type.
+	want Type // The expected type.
+}
+
+var _ error = (*UnexpectedTypeError)(nil)
+
+// NewUnexpectedTypeError creates a new UnexpectedTypeError with
+// the specified node or link ID, actual type, and expected type.
+func NewUnexpectedTypeError(id ID, got, want Type) *UnexpectedTypeError {
+	return &UnexpectedTypeError{id: id, got: got, want: want}
+}
+
+// ID returns the node or link ID recorded in e.
+//
+// If e is nil, it returns a zero-value ID (invalid).
+func (e *UnexpectedTypeError) ID() ID {
+	if e == nil {
+		return ID{}
+	}
+	return e.id
+}
+
+// Got returns the actual type recorded in e.
+//
+// If e is nil, it returns a zero-value Type (invalid).
+func (e *UnexpectedTypeError) Got() Type {
+	if e == nil {
+		return Type{}
+	}
+	return e.got
+}
+
+// Want returns the expected type recorded in e.
+//
+// If e is nil, it returns a zero-value Type (invalid).
+func (e *UnexpectedTypeError) Want() Type {
+	if e == nil {
+		return Type{}
+	}
+	return e.want
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *UnexpectedTypeError>".
+func (e *UnexpectedTypeError) Error() string {
+	if e == nil {
+		return "<nil *UnexpectedTypeError>"
+	}
+	return "type of " + strconv.Quote(e.id.String()) + " is " +
+		strconv.Quote(e.got.String()) + "; want " + strconv.Quote(e.want.String())
+}
+
+// LinkConstraintError is an error indicating that
+// a link of the specified type cannot connect the specified nodes
+// due to the link constraint registered by
+// the method RegisterLinkConstraint of SLN.
+type LinkConstraintError struct {
+	linkType Type // The link type.
+	from     ID   // ID of the node from which the link starts.
+	to       ID   // ID of the node to which the link points.
+}
+
+var _ error = (*LinkConstraintError)(nil)
+
+// NewLinkConstraintError creates a new LinkConstraintError
+// with the specified link type and the IDs of the nodes
+// from which the link starts and to which the link points.
+func NewLinkConstraintError(linkType Type, from, to ID) *LinkConstraintError {
+	return &LinkConstraintError{linkType: linkType, from: from, to: to}
+}
+
+// LinkType returns the link type recorded in e.
+//
+// If e is nil, it returns a zero-value Type (invalid).
+func (e *LinkConstraintError) LinkType() Type {
+	if e == nil {
+		return Type{}
+	}
+	return e.linkType
+}
+
+// From returns the ID of the node from which the link starts,
+// recorded in e.
+//
+// If e is nil, it returns a zero-value ID (invalid).
+func (e *LinkConstraintError) From() ID {
+	if e == nil {
+		return ID{}
+	}
+	return e.from
+}
+
+// To returns the ID of the node to which the link points,
+// recorded in e.
+//
+// If e is nil, it returns a zero-value ID (invalid).
+func (e *LinkConstraintError) To() ID {
+	if e == nil {
+		return ID{}
+	}
+	return e.to
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *LinkConstraintError>".
+func (e *LinkConstraintError) Error() string {
+	if e == nil {
+		return "<nil *LinkConstraintError>"
+	}
+	return "link of type " + strconv.Quote(e.linkType.String()) +
+		" cannot start from " + strconv.Quote(e.from.String()) +
+		" and point to " + strconv.Quote(e.to.String())
+}
+
+// TypeNamespaceError is an error indicating that
+// some types are used for both nodes and links,
+// violating the rule that node types and link types
+// are drawn from disjoint namespaces.
+type TypeNamespaceError struct {
+	types []Type // The types used for both nodes and links.
+}
+
+var _ error = (*TypeNamespaceError)(nil)
+
+// NewTypeNamespaceError creates a new TypeNamespaceError
+// with the specified types used for both nodes and links.
+func NewTypeNamespaceError(types ...Type) *TypeNamespaceError {
+	return &TypeNamespaceError{types: append([]Type(nil), types...)}
+}
+
+// Types returns a copy of the types recorded in e.
+//
+// If e is nil, it returns nil.
+func (e *TypeNamespaceError) Types() []Type {
+	if e == nil {
+		return nil
+	}
+	return append([]Type(nil), e.types...)
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *TypeNamespaceError>".
+func (e *TypeNamespaceError) Error() string {
+	if e == nil {
+		return "<nil *TypeNamespaceError>"
+	}
+	var b strings.Builder
+	if len(e.types) == 1 {
+		b.WriteString("type ")
+	} else {
+		b.WriteString("types ")
+	}
+	for i, t := range e.types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.Quote(t.String()))
+	}
+	b.WriteString(" used for both nodes and links")
+	return b.String()
+}
+
+// ItemError is an error indicating that
+// an item in a batch operation failed.
+//
+// It records the index of the item in the batch and the error of the item.
+type ItemError struct {
+	index int   // The index of the item.
+	err   error // The error of the item.
+}
+
+var _ error = (*ItemError)(nil)
+
+// NewItemError creates a new ItemError
+// with the specified item index and error.
+func NewItemError(index int, err error) *ItemError {
+	return &ItemError{index: index, err: err}
+}
+
+// Index returns the item index recorded in e.
+//
+// If e is nil, it returns -1.
+func (e *ItemError) Index() int {
+	if e == nil {
+		return -1
+	}
+	return e.index
+}
+
+// Error returns the error message.
+//
+// If e is nil, it returns "<nil *ItemError>".
+func (e *ItemError) Error() string {
+	if e == nil {
+		return "<nil *ItemError>"
+	}
+	msg := "<nil>"
+	if e.err != nil {
+		msg = e.err.Error()
+	}
+	return "item " + strconv.Itoa(e.index) + ": " + msg
+}
+
+// Unwrap returns the error of the item recorded in e.
+//
+// If e is nil, it returns nil.
+func (e *ItemError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.err
+}
+
+// MultiError is an error collecting the errors of
+// the failed items in a batch operation.
+//
+// Each error is recorded as a *ItemError with the index of the item.
+// The client can use errors.Is and errors.As on a *MultiError
+// to match the errors of the items (e.g., *NodeNotExistError),
+// and use errors.As to obtain the first *ItemError.
+// To access all errors, use the method Range.
+type MultiError struct {
+	errs []*ItemError // The errors of the items, in the order added.
+}
+
+var _ error = (*MultiError)(nil)
+
+// NewMultiError creates a new MultiError with no errors.
+//
+// Add the errors of the items by the method Add,
+// and use the method ToError to obtain the result of the batch operation.
+func NewMultiError() *MultiError {
+	return new(MultiError)
+}
+
+// Add records err as the error of the item with the specified index.
+//
+// If err is nil, Add does nothing.
+func (e *MultiError) Add(index int, err error) {
+	if err != nil {
+		e.errs = append(e.errs, NewItemError(index, err))
+	}
+}
+
+// Len returns the number of errors recorded in e.
+//
+// If e is nil, it returns 0.
+func (e *MultiError) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.errs)
+}
+
+// Range calls handler with the item index and error
+// of each error recorded in e, in the order in which they were added,
+// until handler returns false.
+//
+// If e is nil, Range does nothing.
+func (e *MultiError) Range(handler func(index int, err error) (cont bool)) {
+	if e == nil {
+		return
+	}
+	for _, ie := range e.errs {
+		if !handler(ie.index, ie.err) {
+			return
+		}
+	}
+}
+
+// ToError returns e if e records any error.
+// Otherwise (including the case where e is nil), it returns nil.
+//
+// The batch operations should return the result of ToError
+// rather than e itself, so that the client can check success by err == nil.
+func (e *MultiError) ToError() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns the error message,
+// including the number of errors and the message of each error.
+//
+// If e is nil, it returns "<nil *MultiError>".
+func (e *MultiError) Error() string {
+	if e == nil {
+		return "<nil *MultiError>"
+	}
+	switch len(e.errs) {
+	case 0:
+		return "no error"
+	case 1:
+		return "1 item failed: " + e.errs[0].Error()
+	}
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(e.errs)))
+	b.WriteString(" items failed: ")
+	for i, ie := range e.errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(ie.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors recorded in e, as *ItemError.
+//
+// If e is nil or records no errors, it returns nil.
+func (e *MultiError) Unwrap() []error {
+	if e.Len() == 0 {
+		return nil
+	}
+	errs := make([]error, len(e.errs))
+	for i := range e.errs {
+		errs[i] = e.errs[i]
+	}
+	return errs
+}
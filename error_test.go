@@ -0,0 +1,207 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/donyori/gosln"
+)
+
+func TestMultiError(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	id := gosln.NewID(person, gosln.DateOfYearMonthDay(2023, 1, 2), 3)
+	notExist := gosln.NewNodeNotExistError(id)
+	errOther := errors.New("other error")
+
+	me := gosln.NewMultiError()
+	if err := me.ToError(); err != nil {
+		t.Errorf("no errors - got ToError %v; want nil", err)
+	}
+	me.Add(0, nil)
+	me.Add(2, notExist)
+	me.Add(5, errOther)
+	if n := me.Len(); n != 2 {
+		t.Errorf("got Len %d; want 2", n)
+	}
+
+	err := me.ToError()
+	if err == nil {
+		t.Fatal("got ToError nil; want non-nil")
+	}
+	var nne *gosln.NodeNotExistError
+	if !errors.As(err, &nne) || nne.NodeID() != id {
+		t.Errorf("errors.As *NodeNotExistError - got %v", nne)
+	}
+	if !errors.Is(err, errOther) {
+		t.Error("errors.Is other error - got false")
+	}
+	var ie *gosln.ItemError
+	if !errors.As(err, &ie) || ie.Index() != 2 || !errors.Is(ie, notExist) {
+		t.Errorf("errors.As *ItemError - got %v", ie)
+	}
+
+	var indices []int
+	me.Range(func(index int, err error) (cont bool) {
+		indices = append(indices, index)
+		return true
+	})
+	if len(indices) != 2 || indices[0] != 2 || indices[1] != 5 {
+		t.Errorf("got indices %v; want [2 5]", indices)
+	}
+	indices = indices[:0]
+	me.Range(func(index int, err error) (cont bool) {
+		indices = append(indices, index)
+		return false
+	})
+	if len(indices) != 1 {
+		t.Errorf("got %d calls after returning false; want 1", len(indices))
+	}
+
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "2 items failed: ") ||
+		!strings.Contains(msg, "item 2: "+notExist.Error()) ||
+		!strings.Contains(msg, "item 5: other error") {
+		t.Errorf("got message %q", msg)
+	}
+
+	var nilME *gosln.MultiError
+	if nilME.Len() != 0 || nilME.ToError() != nil || nilME.Unwrap() != nil {
+		t.Error("nil *MultiError - got non-zero Len, non-nil ToError, or non-nil Unwrap")
+	}
+}
+
+func TestErrors_NilReceiver(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{(*gosln.InvalidTypeError)(nil), "<nil *InvalidTypeError>"},
+		{(*gosln.InvalidIDError)(nil), "<nil *InvalidIDError>"},
+		{(*gosln.InvalidDateError)(nil), "<nil *InvalidDateError>"},
+		{(*gosln.InvalidPropNameError)(nil), "<nil *InvalidPropNameError>"},
+		{(*gosln.InvalidPropTypeError)(nil), "<nil *InvalidPropTypeError>"},
+		{(*gosln.InvalidPropValueError)(nil), "<nil *InvalidPropValueError>"},
+		{(*gosln.PropNotExistError)(nil), "<nil *PropNotExistError>"},
+		{(*gosln.PropTypeError)(nil), "<nil *PropTypeError>"},
+		{(*gosln.LossyConversionError)(nil), "<nil *LossyConversionError>"},
+		{(*gosln.NodeNotExistError)(nil), "<nil *NodeNotExistError>"},
+		{(*gosln.LinkNotExistError)(nil), "<nil *LinkNotExistError>"},
+		{(*gosln.UnexpectedTypeError)(nil), "<nil *UnexpectedTypeError>"},
+		{(*gosln.LinkConstraintError)(nil), "<nil *LinkConstraintError>"},
+		{(*gosln.TypeNamespaceError)(nil), "<nil *TypeNamespaceError>"},
+		{(*gosln.ItemError)(nil), "<nil *ItemError>"},
+		{(*gosln.MultiError)(nil), "<nil *MultiError>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T", tc.err), func(t *testing.T) {
+			if got := tc.err.Error(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+			if u, ok := tc.err.(interface{ Unwrap() error }); ok {
+				if got := u.Unwrap(); got != nil {
+					t.Errorf("got Unwrap %v; want nil", got)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidIDError_Unwrap(t *testing.T) {
+	err := gosln.NewInvalidIDError(gosln.ID{})
+	if u := err.Unwrap(); u != nil {
+		t.Errorf("got %v; want nil", u)
+	}
+	if u := errors.Unwrap(err); u != nil {
+		t.Errorf("errors.Unwrap - got %v; want nil", u)
+	}
+}
+
+func TestNotExistErrors_Is(t *testing.T) {
+	person, knows := gosln.MustNewType("Person"), gosln.MustNewType("Knows")
+	date := gosln.DateOfYearMonthDay(2023, 1, 2)
+	nodeErr := fmt.Errorf("wrapped: %w", gosln.NewNodeNotExistError(gosln.NewID(person, date, 1)))
+	linkErr := fmt.Errorf("wrapped: %w", gosln.NewLinkNotExistError(gosln.NewID(knows, date, 2)))
+	var nilNodeErr *gosln.NodeNotExistError
+	var nilLinkErr *gosln.LinkNotExistError
+
+	testCases := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"node-ErrNodeNotExist", nodeErr, gosln.ErrNodeNotExist, true},
+		{"node-ErrLinkNotExist", nodeErr, gosln.ErrLinkNotExist, false},
+		{"link-ErrLinkNotExist", linkErr, gosln.ErrLinkNotExist, true},
+		{"link-ErrNodeNotExist", linkErr, gosln.ErrNodeNotExist, false},
+		{"nil node-ErrNodeNotExist", nilNodeErr, gosln.ErrNodeNotExist, true},
+		{"nil link-ErrLinkNotExist", nilLinkErr, gosln.ErrLinkNotExist, true},
+		{"node-ErrSLNClosed", nodeErr, gosln.ErrSLNClosed, false},
+		{"other-ErrNodeNotExist", errors.New("node does not exist"), gosln.ErrNodeNotExist, false},
+		{"multi-ErrLinkNotExist", func() error {
+			me := gosln.NewMultiError()
+			me.Add(3, linkErr)
+			return me
+		}(), gosln.ErrLinkNotExist, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errors.Is(tc.err, tc.target); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+
+	var target *gosln.NodeNotExistError
+	if !errors.As(nodeErr, &target) || target.NodeID().Type() != person {
+		t.Errorf("errors.As - got %v", target)
+	}
+}
+
+func TestPropTypeError_Is(t *testing.T) {
+	name := gosln.MustNewPropName("age")
+	pm := gosln.NewPropMap(1)
+	pm.Set(name, "18")
+	_, err := gosln.PropMapGet[int](pm, name)
+	if !errors.Is(err, gosln.ErrPropType) {
+		t.Errorf("PropMapGet - got %v; want to match ErrPropType", err)
+	}
+	var target *gosln.PropTypeError
+	if !errors.As(err, &target) || target.PropName() != name {
+		t.Errorf("errors.As - got %v", target)
+	}
+
+	_, err = gosln.PropMapGet[int](pm, gosln.MustNewPropName("absent"))
+	if errors.Is(err, gosln.ErrPropType) {
+		t.Errorf("absent - got %v; want not to match ErrPropType", err)
+	}
+	var nilErr *gosln.PropTypeError
+	if !errors.Is(nilErr, gosln.ErrPropType) {
+		t.Error("nil *PropTypeError - got not to match ErrPropType")
+	}
+	if errors.Is(gosln.NewPropTypeError(name, "18", nil), gosln.ErrNodeNotExist) {
+		t.Error("got to match ErrNodeNotExist")
+	}
+}
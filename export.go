@@ -0,0 +1,412 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"github.com/donyori/gogo/errors"
+)
+
+// ExportFormat represents the format of the data exported
+// by function ExportSubgraph.
+type ExportFormat int8
+
+const (
+	// ExportJSONL is the JSON Lines format.
+	//
+	// Each line is a JSON object representing a node or a link.
+	// The nodes come first, followed by the links.
+	// A node is represented as
+	//
+	//	{"kind": "node", "id": <ID>, "type": <type>, "props": {<name>: <value>, ...}}
+	//
+	// and a link is represented as
+	//
+	//	{"kind": "link", "id": <ID>, "type": <type>, "from": <ID>, "to": <ID>, "props": {<name>: <value>, ...}}
+	//
+	// where the property values are rendered via function FormatPropValue.
+	ExportJSONL ExportFormat = 1 + iota // jsonl
+
+	// ExportGraphML is the GraphML format (http://graphml.graphdrawing.org/).
+	//
+	// The node and link types are recorded as the data with the key "type".
+	// The properties on the nodes are recorded as the data
+	// with the keys "node.<name>", and those on the links are recorded
+	// as the data with the keys "link.<name>",
+	// where the property values are rendered via function FormatPropValue.
+	ExportGraphML // graphml
+)
+
+//go:generate stringer -type=ExportFormat -output=export_format_string.go -linecomment
+
+// IsValid reports whether the export format is known.
+func (i ExportFormat) IsValid() bool {
+	return i >= ExportJSONL && i <= ExportGraphML
+}
+
+// ExportSubgraph exports the subgraph of sln to w in the specified format.
+//
+// The subgraph consists of the nodes that satisfy nodeCond
+// and the links that satisfy linkCond
+// and whose both endpoints are in the subgraph.
+// The nodes and links are exported in ascending order of their IDs.
+//
+// All properties on the exported nodes and links are included,
+// with the property types obtained from the method InferSchema of sln.
+// For the properties observed with more than one type
+// (see the field Mixed of TypeSchema),
+// the best-effort types are used, which may cause a *PropTypeError.
+// (To test whether the error is *PropTypeError, use function errors.As.)
+//
+// To export a consistent view of an SLN under concurrent writes,
+// export a snapshot of it (see the method Snapshot of SLN).
+//
+// ExportSubgraph reports an error if sln or w is nil,
+// or format is invalid.
+func ExportSubgraph(
+	ctx context.Context,
+	sln ReadOnlySLN,
+	nodeCond NodeMatchCond,
+	linkCond LinkMatchCond,
+	w io.Writer,
+	format ExportFormat,
+) error {
+	if sln == nil {
+		return errors.AutoNew("SLN is nil")
+	} else if w == nil {
+		return errors.AutoNew("writer is nil")
+	} else if !format.IsValid() {
+		return errors.AutoNew("export format " + format.String() + " is invalid")
+	}
+	schema, err := sln.InferSchema(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	nodes, err := exportGetNodes(ctx, sln, schema, nodeCond)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	links, err := exportGetLinks(ctx, sln, schema, linkCond, nodes)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	if format == ExportGraphML {
+		err = exportGraphML(w, nodes, links)
+	} else {
+		err = exportJSONL(w, nodes, links)
+	}
+	return errors.AutoWrap(err)
+}
+
+// exportGetNodes returns the nodes in sln that satisfy cond,
+// sorted in ascending order of their IDs.
+//
+// It retrieves the nodes of each type in schema separately,
+// with the property types recorded in schema.
+func exportGetNodes(
+	ctx context.Context,
+	sln ReadOnlySLN,
+	schema *Schema,
+	cond NodeMatchCond,
+) (nodes []*Node, err error) {
+	for t, ts := range schema.Nodes {
+		typeCond := nodeMatchCondOfType(cond, t)
+		if typeCond != nil && len(typeCond) == 0 {
+			continue
+		}
+		var propTypes PropTypeMap
+		if ts != nil {
+			propTypes = ts.PropTypes
+		}
+		var typeNodes []*Node
+		typeNodes, err = sln.GetAllNodes(ctx, propTypes, typeCond)
+		if err != nil {
+			return
+		}
+		nodes = append(nodes, typeNodes...)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID.String() < nodes[j].ID.String()
+	})
+	return
+}
+
+// exportGetLinks returns the links in sln that satisfy cond
+// and whose both endpoints are in nodes,
+// sorted in ascending order of their IDs.
+//
+// It retrieves the links of each type in schema separately,
+// with the property types recorded in schema.
+func exportGetLinks(
+	ctx context.Context,
+	sln ReadOnlySLN,
+	schema *Schema,
+	cond LinkMatchCond,
+	nodes []*Node,
+) (links []*Link, err error) {
+	nodeIDs := NewIDSetCap(len(schema.Nodes))
+	for _, node := range nodes {
+		nodeIDs.Add(node.ID)
+	}
+	for t, ts := range schema.Links {
+		typeCond := linkMatchCondOfType(cond, t)
+		if typeCond != nil && len(typeCond) == 0 {
+			continue
+		}
+		var propTypes PropTypeMap
+		if ts != nil {
+			propTypes = ts.PropTypes
+		}
+		var typeLinks []*Link
+		typeLinks, err = sln.GetAllLinks(ctx, propTypes, typeCond)
+		if err != nil {
+			return
+		}
+		for _, link := range typeLinks {
+			if link != nil && link.From != nil && link.To != nil &&
+				nodeIDs.ContainsItem(link.From.ID) &&
+				nodeIDs.ContainsItem(link.To.ID) {
+				links = append(links, link)
+			}
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].ID.String() < links[j].ID.String()
+	})
+	return
+}
+
+// nodeMatchCondOfType returns a NodeMatchCond that matches
+// the nodes of type t satisfying cond.
+//
+// It returns a non-nil but empty NodeMatchCond
+// if no node of type t can satisfy cond.
+func nodeMatchCondOfType(cond NodeMatchCond, t Type) NodeMatchCond {
+	if cond == nil {
+		nmc := NewNodeMatchClause()
+		nmc.SetType(t)
+		return NodeMatchCond{nmc}
+	}
+	typeCond := make(NodeMatchCond, 0, len(cond))
+	for _, nmc := range cond {
+		if nmc == nil || nmc.GetType().IsValid() && nmc.GetType() != t {
+			continue
+		}
+		c := NewNodeMatchClause()
+		c.SetID(nmc.GetID())
+		c.SetType(t)
+		c.SetPropMatchClause(nmc.GetPropMatchClause())
+		c.SetIncludeDeleted(nmc.GetIncludeDeleted())
+		typeCond = append(typeCond, c)
+	}
+	return typeCond
+}
+
+// linkMatchCondOfType returns a LinkMatchCond that matches
+// the links of type t satisfying cond.
+//
+// It returns a non-nil but empty LinkMatchCond
+// if no link of type t can satisfy cond.
+func linkMatchCondOfType(cond LinkMatchCond, t Type) LinkMatchCond {
+	if cond == nil {
+		lmc := NewLinkMatchClause()
+		lmc.SetType(t)
+		return LinkMatchCond{lmc}
+	}
+	typeCond := make(LinkMatchCond, 0, len(cond))
+	for _, lmc := range cond {
+		if lmc == nil || lmc.GetType().IsValid() && lmc.GetType() != t {
+			continue
+		}
+		c := NewLinkMatchClause()
+		c.SetID(lmc.GetID())
+		c.SetType(t)
+		c.SetPropMatchClause(lmc.GetPropMatchClause())
+		c.SetFromNodeMatchClause(lmc.GetFromNodeMatchClause())
+		c.SetToNodeMatchClause(lmc.GetToNodeMatchClause())
+		typeCond = append(typeCond, c)
+	}
+	return typeCond
+}
+
+// exportJSONL writes the nodes and links to w in the JSON Lines format.
+func exportJSONL(w io.Writer, nodes []*Node, links []*Link) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, node := range nodes {
+		m := NodeToMap(node, false)
+		m["kind"] = "node"
+		err := enc.Encode(m)
+		if err != nil {
+			return err
+		}
+	}
+	for _, link := range links {
+		m := map[string]any{
+			"kind":  "link",
+			"id":    link.ID.String(),
+			"type":  link.Type.String(),
+			"from":  link.From.ID.String(),
+			"to":    link.To.ID.String(),
+			"props": formatPropMap(link.Props),
+		}
+		err := enc.Encode(m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// graphMLNamespace is the XML namespace of GraphML.
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+// graphML and the following types describe the XML structure of GraphML.
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"graphml"`
+		Xmlns   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID       string `xml:"id,attr"`
+		For      string `xml:"for,attr"`
+		AttrName string `xml:"attr.name,attr"`
+		AttrType string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		ID     string        `xml:"id,attr"`
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// exportGraphML writes the nodes and links to w in the GraphML format.
+func exportGraphML(w io.Writer, nodes []*Node, links []*Link) error {
+	g := graphML{
+		Xmlns: graphMLNamespace,
+		Keys: []graphMLKey{
+			{ID: "type", For: "all", AttrName: "type", AttrType: "string"},
+		},
+		Graph: graphMLGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, len(nodes)),
+			Edges:       make([]graphMLEdge, len(links)),
+		},
+	}
+	nodeKeys, linkKeys := NewPropNameSet(0), NewPropNameSet(0)
+	for i, node := range nodes {
+		g.Graph.Nodes[i] = graphMLNode{
+			ID:   node.ID.String(),
+			Data: graphMLDataOf(node.NL, "node.", nodeKeys),
+		}
+	}
+	for i, link := range links {
+		g.Graph.Edges[i] = graphMLEdge{
+			ID:     link.ID.String(),
+			Source: link.From.ID.String(),
+			Target: link.To.ID.String(),
+			Data:   graphMLDataOf(link.NL, "link.", linkKeys),
+		}
+	}
+	g.Keys = appendGraphMLKeys(g.Keys, "node", nodeKeys)
+	g.Keys = appendGraphMLKeys(g.Keys, "edge", linkKeys)
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(g)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// graphMLDataOf returns the GraphML data of the type and
+// properties of nl, sorted by the property names.
+//
+// The keys of the property data are prefixed with keyPrefix.
+// The property names are added to names.
+func graphMLDataOf(nl NL, keyPrefix string, names PropNameSet) []graphMLData {
+	data := []graphMLData{{Key: "type", Value: nl.Type.String()}}
+	if nl.Props == nil {
+		return data
+	}
+	for _, name := range sortedPropMapNames[any](nl.Props, nil) {
+		value, _ := nl.Props.Get(name)
+		data = append(data, graphMLData{
+			Key:   keyPrefix + name.String(),
+			Value: FormatPropValue(value),
+		})
+		names.Add(name)
+	}
+	return data
+}
+
+// appendGraphMLKeys appends the GraphML keys for
+// the property names in names to keys, sorted by the property names,
+// and returns the result.
+//
+// forWhat is the value of the attribute "for" of the keys,
+// either "node" or "edge".
+func appendGraphMLKeys(keys []graphMLKey, forWhat string, names PropNameSet) []graphMLKey {
+	prefix := "node."
+	if forWhat == "edge" {
+		prefix = "link."
+	}
+	for _, name := range sortedPropNameSetNames(names, nil) {
+		keys = append(keys, graphMLKey{
+			ID:       prefix + name.String(),
+			For:      forWhat,
+			AttrName: name.String(),
+			AttrType: "string",
+		})
+	}
+	return keys
+}
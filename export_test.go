@@ -0,0 +1,197 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/donyori/gosln"
+)
+
+// exportTestSLN is a fake SLN for testing function ExportSubgraph.
+//
+// It only implements the methods InferSchema, GetAllNodes, and GetAllLinks.
+type exportTestSLN struct {
+	gosln.SLN
+	nodes []*gosln.Node
+	links []*gosln.Link
+}
+
+func (sln *exportTestSLN) InferSchema(ctx context.Context) (*gosln.Schema, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := gosln.NewSchema()
+	for _, node := range sln.nodes {
+		s.AddNode(node)
+	}
+	for _, link := range sln.links {
+		s.AddLink(link)
+	}
+	return s, nil
+}
+
+func (sln *exportTestSLN) GetAllNodes(ctx context.Context, _ gosln.PropTypeMap, cond gosln.NodeMatchCond) (
+	nodes []*gosln.Node, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	for _, node := range sln.nodes {
+		if cond.Match(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return
+}
+
+func (sln *exportTestSLN) GetAllLinks(ctx context.Context, _ gosln.PropTypeMap, cond gosln.LinkMatchCond) (
+	links []*gosln.Link, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	for _, link := range sln.links {
+		if cond.Match(link) {
+			links = append(links, link)
+		}
+	}
+	return
+}
+
+func TestExportSubgraph(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	city := gosln.MustNewType("City")
+	knows := gosln.MustNewType("Knows")
+	blocks := gosln.MustNewType("Blocks")
+	livesIn := gosln.MustNewType("LivesIn")
+	name := gosln.MustNewPropName("name")
+	date := gosln.NowDate()
+	var serial int64
+	newNL := func(t gosln.Type, nameValue string) gosln.NL {
+		serial++
+		props := gosln.NewPropMap(1)
+		if nameValue != "" {
+			props.Set(name, nameValue)
+		}
+		return gosln.NL{ID: gosln.NewID(t, date, serial), Type: t, Props: props}
+	}
+	alice := &gosln.Node{NL: newNL(person, "Alice")}
+	bob := &gosln.Node{NL: newNL(person, "Bob <&>")}
+	carol := &gosln.Node{NL: newNL(person, "Carol"), Deleted: true}
+	paris := &gosln.Node{NL: newNL(city, "Paris")}
+	aliceKnowsBob := &gosln.Link{NL: newNL(knows, ""), From: alice, To: bob}
+	aliceBlocksBob := &gosln.Link{NL: newNL(blocks, ""), From: alice, To: bob}
+	bobKnowsCarol := &gosln.Link{NL: newNL(knows, ""), From: bob, To: carol}
+	aliceLivesInParis := &gosln.Link{NL: newNL(livesIn, ""), From: alice, To: paris}
+	sln := &exportTestSLN{
+		nodes: []*gosln.Node{alice, bob, carol, paris},
+		links: []*gosln.Link{aliceKnowsBob, aliceBlocksBob, bobKnowsCarol, aliceLivesInParis},
+	}
+	nmc := gosln.NewNodeMatchClause()
+	nmc.SetType(person)
+	lmc := gosln.NewLinkMatchClause()
+	lmc.SetType(knows)
+	nodeCond, linkCond := gosln.NodeMatchCond{nmc}, gosln.LinkMatchCond{lmc}
+	wantIDs := []string{alice.ID.String(), bob.ID.String(), aliceKnowsBob.ID.String()}
+	unwantedIDs := []string{
+		carol.ID.String(),
+		paris.ID.String(),
+		aliceBlocksBob.ID.String(),
+		bobKnowsCarol.ID.String(),
+		aliceLivesInParis.ID.String(),
+	}
+
+	t.Run("jsonl", func(t *testing.T) {
+		var b strings.Builder
+		err := gosln.ExportSubgraph(context.Background(), sln, nodeCond, linkCond, &b, gosln.ExportJSONL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var gotIDs []string
+		scanner := bufio.NewScanner(strings.NewReader(b.String()))
+		for scanner.Scan() {
+			var m map[string]any
+			err = json.Unmarshal(scanner.Bytes(), &m)
+			if err != nil {
+				t.Fatalf("line %q - %v", scanner.Text(), err)
+			}
+			gotIDs = append(gotIDs, m["id"].(string))
+			if m["id"] == bob.ID.String() {
+				if got := m["props"].(map[string]any)["name"]; got != "Bob <&>" {
+					t.Errorf("got Bob's name %v; want %q", got, "Bob <&>")
+				}
+			}
+		}
+		if len(gotIDs) != len(wantIDs) {
+			t.Fatalf("got IDs %v; want %v", gotIDs, wantIDs)
+		}
+		for i := range gotIDs {
+			if gotIDs[i] != wantIDs[i] {
+				t.Errorf("got IDs %v; want %v", gotIDs, wantIDs)
+				break
+			}
+		}
+	})
+
+	t.Run("graphml", func(t *testing.T) {
+		var b strings.Builder
+		err := gosln.ExportSubgraph(context.Background(), sln, nodeCond, linkCond, &b, gosln.ExportGraphML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"graph>node"`
+			Edges []struct {
+				ID     string `xml:"id,attr"`
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"graph>edge"`
+		}
+		err = xml.Unmarshal([]byte(b.String()), &g)
+		if err != nil {
+			t.Fatal("unmarshal -", err)
+		}
+		if len(g.Nodes) != 2 || g.Nodes[0].ID != wantIDs[0] || g.Nodes[1].ID != wantIDs[1] {
+			t.Errorf("got nodes %v; want %v", g.Nodes, wantIDs[:2])
+		}
+		if len(g.Edges) != 1 || g.Edges[0].ID != wantIDs[2] ||
+			g.Edges[0].Source != alice.ID.String() || g.Edges[0].Target != bob.ID.String() {
+			t.Errorf("got edges %v; want [%s]", g.Edges, wantIDs[2])
+		}
+		for _, id := range unwantedIDs {
+			if strings.Contains(b.String(), id) {
+				t.Errorf("excluded %s is present", id)
+			}
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		var b strings.Builder
+		err := gosln.ExportSubgraph(context.Background(), sln, nil, nil, &b, 0)
+		if err == nil {
+			t.Error("got nil error")
+		}
+	})
+}
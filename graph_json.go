@@ -0,0 +1,317 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+)
+
+// graphDoc and the following types describe the JSON document
+// produced by function MarshalGraph.
+type (
+	graphDoc struct {
+		Nodes []graphNodeDoc `json:"nodes"`
+		Links []graphLinkDoc `json:"links"`
+	}
+
+	graphNodeDoc struct {
+		ID      ID                      `json:"id"`
+		Type    Type                    `json:"type"`
+		Deleted bool                    `json:"deleted,omitempty"`
+		Props   map[string]typedPropDoc `json:"props"`
+	}
+
+	graphLinkDoc struct {
+		ID    ID                      `json:"id"`
+		Type  Type                    `json:"type"`
+		From  ID                      `json:"from"`
+		To    ID                      `json:"to"`
+		Props map[string]typedPropDoc `json:"props"`
+	}
+
+	// typedPropDoc is a property value tagged with its PropType.
+	typedPropDoc struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+)
+
+// MarshalGraph encodes the specified nodes and links
+// as a self-contained JSON document.
+//
+// The document is a JSON object of the form
+//
+//	{"nodes": [<node>, ...], "links": [<link>, ...]}
+//
+// where a node is represented as
+//
+//	{"id": <ID>, "type": <type>, "deleted": true, "props": {<name>: <prop>, ...}}
+//
+// (the field "deleted" is omitted if the node has not been soft-removed),
+// a link is represented as
+//
+//	{"id": <ID>, "type": <type>, "from": <ID>, "to": <ID>, "props": {<name>: <prop>, ...}}
+//
+// and a property is tagged with its type as
+//
+//	{"type": <PropType name>, "value": <value>}
+//
+// where the PropType name is the result of the method String of PropType.
+// The values are encoded by encoding/json, except that
+// the complex numbers and the non-finite floating-point numbers
+// (NaN and ±Inf) are encoded as JSON strings
+// in the form produced by function FormatPropValue.
+// A []float64 containing any non-finite number is encoded as
+// a JSON array of such strings.
+//
+// The nodes and links are encoded in the order in which they are given,
+// and the properties are sorted by name,
+// so the document is stable for the same input.
+//
+// The links refer to their endpoints by ID.
+// MarshalGraph reports an error if any link has a nil From or To node,
+// or its endpoint is not in nodes.
+// The nil nodes and links are skipped.
+func MarshalGraph(nodes []*Node, links []*Link) ([]byte, error) {
+	doc := graphDoc{
+		Nodes: make([]graphNodeDoc, 0, len(nodes)),
+		Links: make([]graphLinkDoc, 0, len(links)),
+	}
+	nodeIDs := NewIDSet()
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		props, err := encodeTypedProps(node.Props)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		doc.Nodes = append(doc.Nodes, graphNodeDoc{
+			ID:      node.ID,
+			Type:    node.Type,
+			Deleted: node.Deleted,
+			Props:   props,
+		})
+		nodeIDs.Add(node.ID)
+	}
+	for _, link := range links {
+		if link == nil {
+			continue
+		} else if link.From == nil || link.To == nil {
+			return nil, errors.AutoNew("link " + link.ID.String() + " has a nil endpoint")
+		} else if !nodeIDs.ContainsItem(link.From.ID) || !nodeIDs.ContainsItem(link.To.ID) {
+			return nil, errors.AutoNew("an endpoint of link " + link.ID.String() + " is not in the nodes")
+		}
+		props, err := encodeTypedProps(link.Props)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		doc.Links = append(doc.Links, graphLinkDoc{
+			ID:    link.ID,
+			Type:  link.Type,
+			From:  link.From.ID,
+			To:    link.To.ID,
+			Props: props,
+		})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	return data, nil
+}
+
+// UnmarshalGraph decodes the JSON document produced by function MarshalGraph
+// and returns the nodes and links in it.
+//
+// The fields From and To of the links point to the returned nodes.
+// The field SLN of the returned nodes and links is nil.
+//
+// UnmarshalGraph reports an error if the document is malformed,
+// any node ID is duplicate, or any link refers to a node
+// not in the document.
+// It reports a *InvalidPropNameError if any property name is invalid,
+// and a *InvalidPropTypeError if any property type name is unknown.
+// (To test the type of the error, use function errors.As.)
+func UnmarshalGraph(data []byte) (nodes []*Node, links []*Link, err error) {
+	var doc graphDoc
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, nil, errors.AutoWrap(err)
+	}
+	nodes = make([]*Node, len(doc.Nodes))
+	nodeMap := make(map[ID]*Node, len(doc.Nodes))
+	for i := range doc.Nodes {
+		nd := &doc.Nodes[i]
+		if !nd.ID.IsValid() || !nd.Type.IsValid() {
+			return nil, nil, errors.AutoNew("node " + strconv.Itoa(i) + " has no valid ID or type")
+		} else if nodeMap[nd.ID] != nil {
+			return nil, nil, errors.AutoNew("node ID " + nd.ID.String() + " is duplicate")
+		}
+		node := &Node{NL: NL{ID: nd.ID, Type: nd.Type}, Deleted: nd.Deleted}
+		node.Props, err = decodeTypedProps(nd.Props)
+		if err != nil {
+			return nil, nil, errors.AutoWrap(err)
+		}
+		nodes[i], nodeMap[nd.ID] = node, node
+	}
+	links = make([]*Link, len(doc.Links))
+	for i := range doc.Links {
+		ld := &doc.Links[i]
+		if !ld.ID.IsValid() || !ld.Type.IsValid() {
+			return nil, nil, errors.AutoNew("link " + strconv.Itoa(i) + " has no valid ID or type")
+		}
+		from, to := nodeMap[ld.From], nodeMap[ld.To]
+		if from == nil || to == nil {
+			return nil, nil, errors.AutoNew("an endpoint of link " + ld.ID.String() + " is not in the document")
+		}
+		link := &Link{NL: NL{ID: ld.ID, Type: ld.Type}, From: from, To: to}
+		link.Props, err = decodeTypedProps(ld.Props)
+		if err != nil {
+			return nil, nil, errors.AutoWrap(err)
+		}
+		links[i] = link
+	}
+	return nodes, links, nil
+}
+
+// encodeTypedProps encodes the properties in pm as typed property documents.
+//
+// If pm is nil, it returns an empty map.
+func encodeTypedProps(pm PropMap) (m map[string]typedPropDoc, err error) {
+	m = make(map[string]typedPropDoc)
+	if pm == nil {
+		return
+	}
+	pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		var doc typedPropDoc
+		doc, err = encodeTypedProp(x.Key, x.Value)
+		if err != nil {
+			return false
+		}
+		m[x.Key.String()] = doc
+		return true
+	})
+	return
+}
+
+// encodeTypedProp encodes the value v of the property with the specified name
+// as a typed property document.
+func encodeTypedProp(name PropName, v any) (doc typedPropDoc, err error) {
+	pt := PropTypeOf(v)
+	if !pt.IsValid() {
+		return doc, NewInvalidPropValueError(name, v)
+	}
+	doc.Type = pt.String()
+	var value any = v
+	switch {
+	case pt.IsComplex():
+		value = FormatPropValue(v)
+	case pt.IsFloat():
+		f := reflect.ValueOf(v).Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			value = FormatPropValue(v)
+		}
+	case pt == PTFloat64Slice:
+		fs := v.([]float64)
+		for _, f := range fs {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				ss := make([]string, len(fs))
+				for i := range fs {
+					ss[i] = FormatPropValue(fs[i])
+				}
+				value = ss
+				break
+			}
+		}
+	}
+	doc.Value, err = json.Marshal(value)
+	return
+}
+
+// decodeTypedProps decodes the typed property documents in m
+// into a new PropMap.
+func decodeTypedProps(m map[string]typedPropDoc) (pm PropMap, err error) {
+	pm = NewPropMap(len(m))
+	for name, doc := range m {
+		propName, err := NewPropName(name)
+		if err != nil {
+			return nil, err
+		}
+		v, err := decodeTypedProp(doc)
+		if err != nil {
+			return nil, err
+		}
+		pm.Set(propName, v)
+	}
+	return pm, nil
+}
+
+// decodeTypedProp decodes the typed property document
+// into a property value.
+func decodeTypedProp(doc typedPropDoc) (v any, err error) {
+	pt, err := ParsePropType(doc.Type)
+	if err != nil {
+		return nil, err
+	}
+	goType := pt.GoType()
+	if pt.IsFloat() || pt.IsComplex() {
+		var s string
+		if json.Unmarshal(doc.Value, &s) == nil {
+			bitSize := goType.Bits()
+			if pt.IsComplex() {
+				var c complex128
+				c, err = strconv.ParseComplex(s, bitSize)
+				v = reflect.ValueOf(c).Convert(goType).Interface()
+			} else {
+				var f float64
+				f, err = strconv.ParseFloat(s, bitSize)
+				v = reflect.ValueOf(f).Convert(goType).Interface()
+			}
+			if err != nil {
+				return nil, err
+			}
+			return v, nil
+		}
+	} else if pt == PTFloat64Slice {
+		var ss []string
+		if json.Unmarshal(doc.Value, &ss) == nil && ss != nil {
+			fs := make([]float64, len(ss))
+			for i := range ss {
+				fs[i], err = strconv.ParseFloat(ss[i], 64)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return fs, nil
+		}
+	}
+	ptr := reflect.New(goType)
+	err = json.Unmarshal(doc.Value, ptr.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
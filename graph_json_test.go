@@ -0,0 +1,138 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestMarshalAndUnmarshalGraph(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	values := map[string]any{
+		"b":    true,
+		"i8":   int8(-8),
+		"u64":  uint64(math.MaxUint64),
+		"f32":  float32(1.5),
+		"nan":  math.NaN(),
+		"inf":  math.Inf(-1),
+		"c64":  complex64(1 + 2i),
+		"data": []byte{0, 1, 2},
+		"s":    `say "hi"`,
+		"t":    time.Date(2023, time.March, 12, 1, 2, 3, 4, time.UTC),
+		"d":    date,
+		"tags": []string{"a", "b c"},
+		"ints": []int{1, -2},
+		"fs":   []float64{0.5, math.Inf(1)},
+	}
+	props, err := gosln.PropMapFromGoMap(values)
+	if err != nil {
+		t.Fatal("create property map -", err)
+	}
+	a := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 1), Type: person, Props: props}}
+	b := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 2), Type: person}, Deleted: true}
+	link := &gosln.Link{NL: gosln.NL{ID: gosln.NewID(knows, date, 3), Type: knows}, From: a, To: b}
+
+	data, err := gosln.MarshalGraph([]*gosln.Node{a, nil, b}, []*gosln.Link{link})
+	if err != nil {
+		t.Fatal("marshal -", err)
+	}
+	data2, err := gosln.MarshalGraph([]*gosln.Node{a, b}, []*gosln.Link{link})
+	if err != nil {
+		t.Fatal("marshal again -", err)
+	} else if string(data2) != string(data) {
+		t.Errorf("unstable document:\n%s\n%s", data, data2)
+	}
+
+	nodes, links, err := gosln.UnmarshalGraph(data)
+	if err != nil {
+		t.Fatal("unmarshal -", err)
+	}
+	if len(nodes) != 2 || len(links) != 1 {
+		t.Fatalf("got %d nodes and %d links; want 2 and 1", len(nodes), len(links))
+	}
+	if nodes[0].ID != a.ID || nodes[1].ID != b.ID || nodes[0].Deleted || !nodes[1].Deleted {
+		t.Errorf("got nodes %v (deleted: %t), %v (deleted: %t)",
+			nodes[0].ID, nodes[0].Deleted, nodes[1].ID, nodes[1].Deleted)
+	}
+	if links[0].ID != link.ID || links[0].From != nodes[0] || links[0].To != nodes[1] {
+		t.Error("link does not refer to the decoded nodes")
+	}
+	// NaN is not equal to itself, so check it separately.
+	nan := gosln.MustNewPropName("nan")
+	if f, err := gosln.PropMapGet[float64](nodes[0].Props, nan); err != nil || !math.IsNaN(f) {
+		t.Errorf("got nan %v, %v; want NaN, <nil>", f, err)
+	}
+	nodes[0].Props.Remove(nan)
+	props.Remove(nan)
+	if !gosln.EqualPropMaps(nodes[0].Props, props) {
+		t.Errorf("got properties %v; want %v",
+			gosln.PropMapToGoMap(nodes[0].Props), gosln.PropMapToGoMap(props))
+	}
+	if nodes[1].Props == nil || nodes[1].Props.Len() != 0 {
+		t.Error("got non-empty or nil properties on node without properties")
+	}
+}
+
+func TestMarshalAndUnmarshalGraph_Error(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	a := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 1), Type: person}}
+	b := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 2), Type: person}}
+	link := &gosln.Link{NL: gosln.NL{ID: gosln.NewID(person, date, 3), Type: person}, From: a, To: b}
+	if _, err := gosln.MarshalGraph([]*gosln.Node{a}, []*gosln.Link{link}); err == nil {
+		t.Error("marshal link with endpoint not in nodes - got nil error")
+	}
+
+	id := gosln.NewID(person, date, 1).String()
+	testCases := []struct {
+		name   string
+		data   string
+		target any
+	}{
+		{"malformed", `{"nodes": [`, nil},
+		{"duplicate", `{"nodes": [{"id": "` + id + `", "type": "Person"}, {"id": "` + id + `", "type": "Person"}]}`, nil},
+		{"dangling link", `{"links": [{"id": "` + id + `", "type": "Person", "from": "` + id + `", "to": "` + id + `"}]}`, nil},
+		{"prop name", `{"nodes": [{"id": "` + id + `", "type": "Person", "props": {"1x": {"type": "int", "value": 1}}}]}`,
+			new(*gosln.InvalidPropNameError)},
+		{"prop type", `{"nodes": [{"id": "` + id + `", "type": "Person", "props": {"x": {"type": "int128", "value": 1}}}]}`,
+			new(*gosln.InvalidPropTypeError)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := gosln.UnmarshalGraph([]byte(tc.data))
+			if err == nil {
+				t.Fatal("got nil error")
+			}
+			if tc.target != nil && !errors.As(err, tc.target) {
+				t.Errorf("got %v; want %T", err, tc.target)
+			}
+			if strings.TrimSpace(err.Error()) == "" {
+				t.Error("got empty error message")
+			}
+		})
+	}
+}
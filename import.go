@@ -0,0 +1,338 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/donyori/gogo/errors"
+)
+
+// DefaultImportBatchSize is the default number of nodes or links
+// imported in a batch by function Import.
+const DefaultImportBatchSize = 100
+
+// DefaultImportMaxBatchSize is the default upper bound of the batch size
+// in the adaptive mode of function Import.
+const DefaultImportMaxBatchSize = 10000
+
+// ImportSource is a source of semantic nodes and links to be imported.
+//
+// It streams the nodes first, and then the links.
+type ImportSource interface {
+	// Total returns the total number of nodes and links in the source.
+	//
+	// It returns -1 if the total number is unknown.
+	Total() int
+
+	// NextNode returns the next node in the source.
+	//
+	// The field ID of the node is used to identify the node
+	// in the links from the source.
+	// The field SLN of the node is ignored.
+	//
+	// NextNode reports io.EOF if there are no more nodes.
+	NextNode() (node *Node, err error)
+
+	// NextLink returns the next link in the source.
+	//
+	// The fields From and To of the link must be non-nil.
+	// Their IDs are either the IDs of the nodes from the source
+	// or the IDs of the nodes already in the destination SLN.
+	// The field SLN of the link is ignored.
+	//
+	// NextLink reports io.EOF if there are no more links.
+	NextLink() (link *Link, err error)
+}
+
+// sliceImportSource is an implementation of interface ImportSource
+// based on slices of nodes and links.
+type sliceImportSource struct {
+	nodes []*Node
+	links []*Link
+}
+
+// NewImportSource creates a new ImportSource
+// that streams the specified nodes and links.
+//
+// The nil nodes and links are skipped.
+func NewImportSource(nodes []*Node, links []*Link) ImportSource {
+	return &sliceImportSource{nodes: nodes, links: links}
+}
+
+func (s *sliceImportSource) Total() int {
+	return len(s.nodes) + len(s.links)
+}
+
+func (s *sliceImportSource) NextNode() (node *Node, err error) {
+	for len(s.nodes) > 0 {
+		node, s.nodes = s.nodes[0], s.nodes[1:]
+		if node != nil {
+			return
+		}
+	}
+	return nil, io.EOF
+}
+
+func (s *sliceImportSource) NextLink() (link *Link, err error) {
+	for len(s.links) > 0 {
+		link, s.links = s.links[0], s.links[1:]
+		if link != nil {
+			return
+		}
+	}
+	return nil, io.EOF
+}
+
+// ImportOptions are options for function Import.
+type ImportOptions struct {
+	// BatchSize is the number of nodes or links imported in a batch.
+	//
+	// Import checks the context and reports the progress
+	// after each batch.
+	//
+	// If BatchSize is non-positive, DefaultImportBatchSize is used.
+	//
+	// In the adaptive mode (see TargetBatchDuration),
+	// BatchSize is the initial batch size.
+	BatchSize int
+
+	// TargetBatchDuration enables the adaptive mode if it is positive.
+	//
+	// In the adaptive mode, Import measures the duration of each batch
+	// and grows or shrinks the size of the next batch so that
+	// the duration of each batch approaches TargetBatchDuration.
+	// The batch size changes by a factor of at most 2 per batch
+	// and is bounded by MinBatchSize and MaxBatchSize.
+	// Therefore, on a backend with low latency (e.g., an in-memory one),
+	// the batch size grows to MaxBatchSize.
+	TargetBatchDuration time.Duration
+
+	// MinBatchSize is the lower bound of the batch size in the adaptive mode.
+	//
+	// If MinBatchSize is non-positive, 1 is used.
+	MinBatchSize int
+
+	// MaxBatchSize is the upper bound of the batch size in the adaptive mode.
+	//
+	// If MaxBatchSize is non-positive, DefaultImportMaxBatchSize is used.
+	// If MaxBatchSize is less than MinBatchSize, MinBatchSize is used.
+	MaxBatchSize int
+
+	// Progress is a callback function to report the progress.
+	//
+	// It is called after each batch,
+	// where done is the number of nodes and links imported so far,
+	// and total is the return value of the method Total of the source.
+	//
+	// If Progress is nil, the progress is not reported.
+	Progress func(done, total int)
+}
+
+// ImportResult is the result of function Import.
+type ImportResult struct {
+	// NumNode is the number of nodes imported.
+	NumNode int
+
+	// NumLink is the number of links imported.
+	NumLink int
+
+	// IDMap maps the IDs of the nodes from the source
+	// to the IDs of the corresponding nodes created in the SLN.
+	IDMap map[ID]ID
+}
+
+// Import creates the nodes and links from src in sln
+// and returns the result and any error encountered.
+//
+// Import creates the nodes and links in batches,
+// whose size is specified by opts.BatchSize,
+// or tuned adaptively if opts.TargetBatchDuration is positive.
+// It checks ctx before each batch.
+// If ctx is canceled or its deadline is exceeded,
+// Import stops and returns the partial result
+// together with the error of ctx.
+// (To test the error, use function errors.Is.)
+//
+// If any error occurs, Import stops and returns the partial result,
+// which records the nodes and links that have been created in sln.
+func Import(ctx context.Context, sln SLN, src ImportSource, opts ImportOptions) (
+	result ImportResult, err error) {
+	if sln == nil {
+		return result, errors.AutoNew("SLN is nil")
+	} else if src == nil {
+		return result, errors.AutoNew("import source is nil")
+	}
+	bs := newImportBatchSizer(opts)
+	total := src.Total()
+	result.IDMap = make(map[ID]ID)
+	imp := &importer{
+		ctx:    ctx,
+		sln:    sln,
+		src:    src,
+		result: &result,
+	}
+	for _, next := range []func() error{imp.importNode, imp.importLink} {
+		for err == nil {
+			if err = ctx.Err(); err != nil {
+				return result, errors.AutoWrap(err)
+			}
+			batchSize, start := bs.size, time.Now()
+			var n int
+			for n < batchSize {
+				err = next()
+				if err != nil {
+					break
+				}
+				n++
+			}
+			if n == batchSize {
+				bs.adjust(n, time.Since(start))
+			}
+			if n > 0 && opts.Progress != nil {
+				opts.Progress(result.NumNode+result.NumLink, total)
+			}
+		}
+		if !errors.Is(err, io.EOF) {
+			return result, errors.AutoWrap(err)
+		}
+		err = nil
+	}
+	return
+}
+
+// importBatchSizer determines the batch size for function Import.
+type importBatchSizer struct {
+	size     int           // The current batch size.
+	target   time.Duration // The target duration of a batch, non-positive for the fixed batch size.
+	min, max int           // The bounds of the batch size in the adaptive mode.
+}
+
+// newImportBatchSizer creates a new importBatchSizer
+// according to the specified options.
+func newImportBatchSizer(opts ImportOptions) *importBatchSizer {
+	bs := &importBatchSizer{
+		size:   opts.BatchSize,
+		target: opts.TargetBatchDuration,
+		min:    opts.MinBatchSize,
+		max:    opts.MaxBatchSize,
+	}
+	if bs.size <= 0 {
+		bs.size = DefaultImportBatchSize
+	}
+	if bs.target <= 0 {
+		return bs
+	}
+	if bs.min <= 0 {
+		bs.min = 1
+	}
+	if bs.max <= 0 {
+		bs.max = DefaultImportMaxBatchSize
+	}
+	if bs.max < bs.min {
+		bs.max = bs.min
+	}
+	bs.clamp()
+	return bs
+}
+
+// adjust updates the batch size according to
+// the duration d of the last batch of n items in the adaptive mode.
+//
+// It does nothing if the adaptive mode is disabled.
+func (bs *importBatchSizer) adjust(n int, d time.Duration) {
+	if bs.target <= 0 || n <= 0 {
+		return
+	}
+	if d <= 0 || d*2 <= bs.target {
+		bs.size = n * 2
+	} else if d >= bs.target*2 {
+		bs.size = n / 2
+	} else {
+		bs.size = int(float64(n) * float64(bs.target) / float64(d))
+	}
+	bs.clamp()
+}
+
+// clamp restricts the batch size to the bounds.
+func (bs *importBatchSizer) clamp() {
+	if bs.size < bs.min {
+		bs.size = bs.min
+	} else if bs.size > bs.max {
+		bs.size = bs.max
+	}
+}
+
+// importer holds the state of function Import.
+type importer struct {
+	ctx    context.Context
+	sln    SLN
+	src    ImportSource
+	result *ImportResult
+}
+
+// importNode imports the next node from the source.
+//
+// It reports io.EOF if there are no more nodes.
+func (imp *importer) importNode() error {
+	node, err := imp.src.NextNode()
+	if err != nil {
+		return err
+	} else if node == nil {
+		return errors.AutoNew("node is nil")
+	}
+	created, err := imp.sln.CreateNode(imp.ctx, node.Type, node.Props)
+	if err != nil {
+		return err
+	}
+	if node.ID.IsValid() {
+		imp.result.IDMap[node.ID] = created.ID
+	}
+	imp.result.NumNode++
+	return nil
+}
+
+// importLink imports the next link from the source.
+//
+// It reports io.EOF if there are no more links.
+func (imp *importer) importLink() error {
+	link, err := imp.src.NextLink()
+	if err != nil {
+		return err
+	} else if link == nil {
+		return errors.AutoNew("link is nil")
+	} else if link.From == nil || link.To == nil {
+		return errors.AutoNew("link endpoint is nil")
+	}
+	from, to := link.From.ID, link.To.ID
+	if id, ok := imp.result.IDMap[from]; ok {
+		from = id
+	}
+	if id, ok := imp.result.IDMap[to]; ok {
+		to = id
+	}
+	_, err = imp.sln.CreateLink(imp.ctx, link.Type, from, to, link.Props)
+	if err != nil {
+		return err
+	}
+	imp.result.NumLink++
+	return nil
+}
@@ -0,0 +1,69 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImportBatchSizer_Adjust(t *testing.T) {
+	const MinBatchSize, MaxBatchSize = 2, 64
+	const Latency, Target = 2 * time.Millisecond, 40 * time.Millisecond
+	const WantBatchSize = int(Target / Latency)
+	testCases := []struct {
+		name    string
+		latency time.Duration // The duration of importing an item.
+		want    []int         // The batch sizes after each adjustment.
+	}{
+		{"converge", Latency, []int{4, 8, 16, WantBatchSize, WantBatchSize}},
+		{"no latency", 0, []int{4, 8, 16, 32, MaxBatchSize, MaxBatchSize}},
+		{"slow", Target * 4, []int{MinBatchSize, MinBatchSize}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bs := newImportBatchSizer(ImportOptions{
+				BatchSize:           MinBatchSize,
+				TargetBatchDuration: Target,
+				MinBatchSize:        MinBatchSize,
+				MaxBatchSize:        MaxBatchSize,
+			})
+			got := make([]int, len(tc.want))
+			for i := range got {
+				bs.adjust(bs.size, time.Duration(bs.size)*tc.latency)
+				got[i] = bs.size
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("got batch sizes %v; want %v", got, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestImportBatchSizer_Adjust_Fixed(t *testing.T) {
+	const BatchSize = 10
+	bs := newImportBatchSizer(ImportOptions{BatchSize: BatchSize})
+	bs.adjust(BatchSize, time.Hour)
+	if bs.size != BatchSize {
+		t.Errorf("got batch size %d; want %d", bs.size, BatchSize)
+	}
+}
@@ -0,0 +1,237 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+// importTestSLN is a fake SLN for testing function Import.
+//
+// It only implements the methods CreateNode and CreateLink.
+type importTestSLN struct {
+	gosln.SLN
+	nodes map[gosln.ID]*gosln.Node
+	links []*gosln.Link
+}
+
+func newImportTestSLN() *importTestSLN {
+	return &importTestSLN{nodes: make(map[gosln.ID]*gosln.Node)}
+}
+
+func (sln *importTestSLN) CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	node = &gosln.Node{NL: gosln.NL{
+		SLN:   sln,
+		ID:    gosln.NewID(t, gosln.NowDate(), int64(len(sln.nodes))),
+		Type:  t,
+		Props: props,
+	}}
+	sln.nodes[node.ID] = node
+	return
+}
+
+func (sln *importTestSLN) CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	fromNode, toNode := sln.nodes[from], sln.nodes[to]
+	if fromNode == nil {
+		return nil, gosln.NewNodeNotExistError(from)
+	} else if toNode == nil {
+		return nil, gosln.NewNodeNotExistError(to)
+	}
+	link = &gosln.Link{
+		NL: gosln.NL{
+			SLN:   sln,
+			ID:    gosln.NewID(t, gosln.NowDate(), int64(len(sln.links))),
+			Type:  t,
+			Props: props,
+		},
+		From: fromNode,
+		To:   toNode,
+	}
+	sln.links = append(sln.links, link)
+	return
+}
+
+// newImportTestSource returns an ImportSource with
+// numNode nodes and numNode-1 links connecting them in a chain.
+func newImportTestSource(numNode int) gosln.ImportSource {
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	date := gosln.NowDate()
+	nodes := make([]*gosln.Node, numNode)
+	for i := range nodes {
+		nodes[i] = &gosln.Node{NL: gosln.NL{
+			ID:   gosln.NewID(person, date, int64(1000+i)),
+			Type: person,
+		}}
+	}
+	links := make([]*gosln.Link, numNode-1)
+	for i := range links {
+		links[i] = &gosln.Link{
+			NL:   gosln.NL{Type: knows},
+			From: nodes[i],
+			To:   nodes[i+1],
+		}
+	}
+	return gosln.NewImportSource(nodes, links)
+}
+
+func TestImport(t *testing.T) {
+	const NumNode, BatchSize = 10, 4
+	sln := newImportTestSLN()
+	var dones []int
+	result, err := gosln.Import(
+		context.Background(),
+		sln,
+		newImportTestSource(NumNode),
+		gosln.ImportOptions{
+			BatchSize: BatchSize,
+			Progress: func(done, total int) {
+				if total != 2*NumNode-1 {
+					t.Errorf("got total %d; want %d", total, 2*NumNode-1)
+				}
+				dones = append(dones, done)
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal("import -", err)
+	}
+	if result.NumNode != NumNode || result.NumLink != NumNode-1 {
+		t.Errorf("got NumNode %d, NumLink %d; want %d, %d",
+			result.NumNode, result.NumLink, NumNode, NumNode-1)
+	}
+	if len(sln.nodes) != NumNode || len(sln.links) != NumNode-1 {
+		t.Errorf("got %d nodes, %d links in SLN; want %d, %d",
+			len(sln.nodes), len(sln.links), NumNode, NumNode-1)
+	}
+	if len(result.IDMap) != NumNode {
+		t.Errorf("got %d entries in IDMap; want %d", len(result.IDMap), NumNode)
+	}
+	for srcID, id := range result.IDMap {
+		if sln.nodes[id] == nil {
+			t.Errorf("node %v is mapped to %v, which is not in SLN", srcID, id)
+		}
+	}
+	// Batches: nodes 4, 8, 10; links 14, 18, 19.
+	wantDones := []int{4, 8, 10, 14, 18, 19}
+	if len(dones) != len(wantDones) {
+		t.Fatalf("got progress %v; want %v", dones, wantDones)
+	}
+	for i := range dones {
+		if dones[i] != wantDones[i] {
+			t.Fatalf("got progress %v; want %v", dones, wantDones)
+		}
+	}
+}
+
+func TestImport_Cancel(t *testing.T) {
+	const NumNode, BatchSize = 10, 4
+	sln := newImportTestSLN()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var numCall int
+	result, err := gosln.Import(
+		ctx,
+		sln,
+		newImportTestSource(NumNode),
+		gosln.ImportOptions{
+			BatchSize: BatchSize,
+			Progress: func(done, total int) {
+				numCall++
+				cancel()
+			},
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v; want %v", err, context.Canceled)
+	}
+	if numCall != 1 {
+		t.Errorf("got %d progress calls; want 1", numCall)
+	}
+	if result.NumNode != BatchSize || result.NumLink != 0 {
+		t.Errorf("got NumNode %d, NumLink %d; want %d, 0",
+			result.NumNode, result.NumLink, BatchSize)
+	}
+	if len(sln.nodes) != result.NumNode || len(sln.links) != result.NumLink {
+		t.Errorf("got %d nodes, %d links in SLN; want %d, %d",
+			len(sln.nodes), len(sln.links), result.NumNode, result.NumLink)
+	}
+	if len(result.IDMap) != result.NumNode {
+		t.Errorf("got %d entries in IDMap; want %d", len(result.IDMap), result.NumNode)
+	}
+}
+
+func TestImport_Adaptive(t *testing.T) {
+	const NumNode, MinBatchSize, MaxBatchSize = 300, 2, 64
+	// The target is so long that every batch finishes in half of it,
+	// so the batch size doubles each time until it reaches MaxBatchSize.
+	// The convergence toward the target is tested by
+	// TestImportBatchSizer_Adjust in import_internal_test.go.
+	const Target = time.Hour
+	sln := newImportTestSLN()
+	var sizes []int
+	var lastDone int
+	_, err := gosln.Import(
+		context.Background(),
+		sln,
+		newImportTestSource(NumNode),
+		gosln.ImportOptions{
+			BatchSize:           MinBatchSize,
+			TargetBatchDuration: Target,
+			MinBatchSize:        MinBatchSize,
+			MaxBatchSize:        MaxBatchSize,
+			Progress: func(done, total int) {
+				sizes = append(sizes, done-lastDone)
+				lastDone = done
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal("import -", err)
+	}
+	want := []int{2, 4, 8, 16, 32, MaxBatchSize, MaxBatchSize}
+	if len(sizes) < len(want) {
+		t.Fatalf("got batch sizes %v; want to start with %v", sizes, want)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Errorf("got batch sizes %v; want to start with %v", sizes, want)
+			break
+		}
+	}
+	for _, size := range sizes {
+		if size > MaxBatchSize {
+			t.Errorf("got batch sizes %v; want at most %d", sizes, MaxBatchSize)
+			break
+		}
+	}
+}
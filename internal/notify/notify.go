@@ -0,0 +1,155 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package notify
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/donyori/gosln"
+)
+
+// Event is a notification to an observer.
+type Event func(obs gosln.Observer)
+
+// observerEntry is a registered observer.
+type observerEntry struct {
+	obs          gosln.Observer
+	unregistered atomic.Bool
+}
+
+// Notifier dispatches the events to the registered observers
+// on a dedicated goroutine, in the order in which they are pushed.
+type Notifier struct {
+	mu        sync.Mutex
+	cond      sync.Cond
+	observers []*observerEntry
+	queue     []Event
+	running   bool          // Whether the dispatching goroutine is running.
+	closed    bool          // Whether the notifier is closed.
+	done      chan struct{} // Closed when the dispatching goroutine exits.
+
+	// numObservers is the number of registered observers,
+	// used to skip creating events if there is no observer.
+	numObservers atomic.Int32
+}
+
+// New creates a new Notifier.
+func New() *Notifier {
+	n := new(Notifier)
+	n.cond.L = &n.mu
+	return n
+}
+
+// Register registers obs and returns a function to unregister it.
+func (n *Notifier) Register(obs gosln.Observer) (unregister func()) {
+	if obs == nil {
+		return func() {}
+	}
+	entry := &observerEntry{obs: obs}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.observers = append(n.observers, entry)
+	n.numObservers.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			entry.unregistered.Store(true)
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			for i, e := range n.observers {
+				if e == entry {
+					n.observers = append(n.observers[:i:i], n.observers[i+1:]...)
+					break
+				}
+			}
+			n.numObservers.Add(-1)
+		})
+	}
+}
+
+// Observed reports whether any observer is registered.
+func (n *Notifier) Observed() bool {
+	return n.numObservers.Load() > 0
+}
+
+// Push appends the events to the queue and
+// starts the dispatching goroutine if necessary.
+//
+// It does nothing if n is closed or there is no observer.
+func (n *Notifier) Push(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed || len(n.observers) == 0 {
+		return
+	}
+	n.queue = append(n.queue, events...)
+	if !n.running {
+		n.running = true
+		n.done = make(chan struct{})
+		go n.dispatch(n.done)
+	}
+	n.cond.Signal()
+}
+
+// Close marks n as closed and waits for
+// the queued events to be dispatched.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	n.closed = true
+	done := n.done
+	n.cond.Signal()
+	n.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// dispatch delivers the queued events to the observers
+// until n is closed and the queue is empty.
+//
+// It closes done before returning.
+func (n *Notifier) dispatch(done chan<- struct{}) {
+	defer close(done)
+	for {
+		n.mu.Lock()
+		for len(n.queue) == 0 && !n.closed {
+			n.cond.Wait()
+		}
+		if len(n.queue) == 0 {
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+		queue := n.queue
+		n.queue = nil
+		observers := append([]*observerEntry(nil), n.observers...)
+		n.mu.Unlock()
+		for _, ev := range queue {
+			for _, entry := range observers {
+				if !entry.unregistered.Load() {
+					ev(entry.obs)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package propconv
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/donyori/gogo/container/mapping"
+
+	"github.com/donyori/gosln"
+)
+
+// Convert returns a new PropMap containing the properties in props
+// that are in propTypes, converted to their specified types.
+//
+// A property is converted only if the conversion is
+// between numeric types, between byte strings,
+// between time.Time and gosln.Date,
+// or between slices of numbers (element by element).
+// In particular, an empty slice of scalars can convert to
+// any slice of scalars.
+// Otherwise, Convert reports a *gosln.PropTypeError.
+//
+// The []byte values and the slices of scalars are copied.
+func Convert(props gosln.PropMap, propTypes gosln.PropTypeMap) (
+	result gosln.PropMap, err error) {
+	if propTypes == nil || props == nil {
+		return gosln.NewPropMap(0), nil
+	}
+	result = gosln.NewPropMap(propTypes.Len())
+	propTypes.Range(func(x mapping.Entry[gosln.PropName, gosln.PropType]) (cont bool) {
+		v, present := props.Get(x.Key)
+		if !present {
+			return true
+		}
+		v, err = ConvertValue(x.Key, v, x.Value)
+		if err != nil {
+			return false
+		}
+		result.Set(x.Key, v)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ConvertValue converts the value of the property with the specified name
+// to the property type pt.
+//
+// It is the implementation of function Convert.
+func ConvertValue(name gosln.PropName, value any, pt gosln.PropType) (
+	converted any, err error) {
+	vPT := gosln.PropTypeOf(value)
+	want := pt.GoType()
+	switch {
+	case vPT == pt:
+		if b, ok := value.([]byte); ok {
+			return append([]byte(nil), b...), nil
+		} else if vPT.IsSlice() {
+			v := reflect.ValueOf(value)
+			if v.IsNil() {
+				return value, nil
+			}
+			c := reflect.MakeSlice(want, v.Len(), v.Len())
+			reflect.Copy(c, v)
+			return c.Interface(), nil
+		}
+		return value, nil
+	case vPT.IsSlice() && pt.IsSlice():
+		v := reflect.ValueOf(value)
+		if v.Len() == 0 {
+			if v.IsNil() {
+				return reflect.Zero(want).Interface(), nil
+			}
+			return reflect.MakeSlice(want, 0, 0).Interface(), nil
+		} else if vPT.ElemType().IsNumeric() && pt.ElemType().IsNumeric() {
+			c := reflect.MakeSlice(want, v.Len(), v.Len())
+			elemType := want.Elem()
+			for i := 0; i < v.Len(); i++ {
+				c.Index(i).Set(v.Index(i).Convert(elemType))
+			}
+			return c.Interface(), nil
+		}
+	case vPT.IsNumeric() && pt.IsNumeric(),
+		vPT.IsByteString() && pt.IsByteString():
+		if vPT.IsConvertibleTo(pt) {
+			return reflect.ValueOf(value).Convert(want).Interface(), nil
+		}
+	case vPT == gosln.PTTime && pt == gosln.PTDate:
+		return gosln.DateOf(value.(time.Time)), nil
+	case vPT == gosln.PTDate && pt == gosln.PTTime:
+		return value.(gosln.Date).GoTime(), nil
+	}
+	return nil, gosln.NewPropTypeError(name, value, want)
+}
+
+// DistinctKey returns a comparable key of the property value v,
+// such that two values of the same type have the same key
+// if and only if they are equal as compared by
+// the method Match of gosln.PropMatchClause.
+//
+// In particular, a slice containing NaN is not equal to any slice,
+// including itself, so its key is distinct from any other key.
+func DistinctKey(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		// Distinguish from string values.
+		return struct{ b string }{b: string(x)}
+	case time.Time:
+		// Compare time.Time values by the instant, as time.Time.Equal does.
+		return struct{ sec, nsec int64 }{sec: x.Unix(), nsec: int64(x.Nanosecond())}
+	case []float64:
+		c := make([]float64, len(x))
+		for i, f := range x {
+			if f != f {
+				// NaN. Use a new pointer, which equals no other key.
+				return new(struct{ nan float64 })
+			} else if f == 0 {
+				// Represent the negative zero as the positive zero,
+				// as they are equal.
+				f = 0
+			}
+			c[i] = f
+		}
+		v = c
+	}
+	if pt := gosln.PropTypeOf(v); pt.IsSlice() {
+		// Slices are not comparable. Use their unambiguous representations
+		// (the string elements are quoted), distinguished by the type.
+		return struct {
+			t gosln.PropType
+			s string
+		}{t: pt, s: gosln.FormatPropValue(v)}
+	}
+	return v
+}
+
+// RealNumberToFloat64 converts the real number held by v to a float64.
+func RealNumberToFloat64(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
@@ -18,7 +18,17 @@
 
 package gosln
 
-import "github.com/donyori/gogo/container/mapping"
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/donyori/gogo/constraints"
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+)
 
 // PropMatchClause is a conjunction of conditions to
 // match properties on a semantic node or link.
@@ -26,13 +36,56 @@ import "github.com/donyori/gogo/container/mapping"
 // A set of properties satisfies the PropMatchClause
 // if it satisfies all the conditions in this PropMatchClause.
 //
-// PropMatchClause consists of three components:
+// PropMatchClause consists of the following components:
 //   - Equal: a PropMap holding the properties that must be equal to the target properties.
 //   - Present: a PropNameSet holding the names of the properties that must exist.
 //   - Absent: a PropNameSet holding the names of the properties that must not exist.
+//   - NotEqual: a PropMap holding the properties that must exist
+//     and be different from the target properties.
+//   - In: a map from property names to lists of values,
+//     one of which the target properties must be equal to.
+//   - GreaterThan, GreaterEqual, LessThan, LessEqual: PropMaps holding
+//     the thresholds that the target properties must be
+//     greater than, greater than or equal to, less than,
+//     and less than or equal to, respectively.
+//   - HasPrefix, Contains, EqualFold: maps from property names to strings
+//     that the target properties must begin with, contain,
+//     and be equal to under Unicode case-folding, respectively.
+//   - Matches: a map from property names to regular expressions that
+//     the target properties must match.
+//   - PropType: a PropTypeMap holding the property types
+//     that the target properties must be of, regardless of their values.
 //
-// These components are mutually exclusive:
+// The components Equal, Present, and Absent are mutually exclusive:
 // when a property is put into one component, it is removed from the others.
+// The component NotEqual is mutually exclusive with Equal and Absent,
+// as a property cannot be both equal and not equal to a value,
+// and a property that must not exist cannot have a different value.
+// Putting a property into NotEqual removes it from Equal and Absent,
+// and putting it into Equal or Absent removes it from NotEqual.
+// NotEqual is compatible with Present, as NotEqual implies the existence.
+// The component In is mutually exclusive with Absent,
+// but not with the other components.
+// The components GreaterThan, GreaterEqual, LessThan, and LessEqual
+// are mutually exclusive with Absent,
+// but not with each other, so that a range can be specified
+// (e.g., GreaterEqual and LessThan on the same property).
+// Similarly, HasPrefix, Contains, EqualFold, and Matches are
+// mutually exclusive with Absent, but not with each other or other components.
+// So is PropType.
+//
+// The ordered comparison components only apply to
+// the real numbers (integers and floating-point numbers),
+// time.Time, and gosln.Date.
+// A real number can be compared with any real number,
+// while time.Time and gosln.Date can only be compared with
+// the values of the same type.
+// A property whose value cannot be compared with the threshold
+// (including NaN) fails the condition.
+//
+// The string matching components HasPrefix, Contains, EqualFold, and Matches
+// only apply to the byte strings (string and []byte).
+// A property whose value is not a byte string fails the condition.
 type PropMatchClause interface {
 	// Equal returns a PropMap with properties
 	// that must be equal to the target properties.
@@ -52,15 +105,137 @@ type PropMatchClause interface {
 	// The PropNameSet is always non-nil, but may be empty.
 	Absent() PropNameSet
 
+	// NotEqual returns a PropMap with properties
+	// that must exist and be different from the target properties.
+	//
+	// The PropMap is always non-nil, but may be empty.
+	NotEqual() PropMap
+
+	// In returns a map from property names to lists of values,
+	// one of which the target properties must be equal to.
+	// A property whose list is empty fails the condition.
+	//
+	// The values are compared in the same way as the component Equal.
+	//
+	// The map is always non-nil, but may be empty.
+	// It panics with a *InvalidPropNameError
+	// if an invalid property name is about to be put into it,
+	// and panics with a *InvalidPropValueError
+	// if a list with a value that does not conform to PropValue
+	// is about to be put into it.
+	// The lists should not be modified after being put into the map.
+	In() mapping.Map[PropName, []any]
+
+	// GreaterThan returns a PropMap with thresholds
+	// that the target properties must be greater than.
+	//
+	// The PropMap is always non-nil, but may be empty.
+	GreaterThan() PropMap
+
+	// GreaterEqual returns a PropMap with thresholds
+	// that the target properties must be greater than or equal to.
+	//
+	// The PropMap is always non-nil, but may be empty.
+	GreaterEqual() PropMap
+
+	// LessThan returns a PropMap with thresholds
+	// that the target properties must be less than.
+	//
+	// The PropMap is always non-nil, but may be empty.
+	LessThan() PropMap
+
+	// LessEqual returns a PropMap with thresholds
+	// that the target properties must be less than or equal to.
+	//
+	// The PropMap is always non-nil, but may be empty.
+	LessEqual() PropMap
+
+	// HasPrefix returns a map from property names to
+	// prefixes that the target properties must begin with.
+	//
+	// The map is always non-nil, but may be empty.
+	// It panics with a *InvalidPropNameError
+	// if an invalid property name is about to be put into it.
+	HasPrefix() mapping.Map[PropName, string]
+
+	// Contains returns a map from property names to
+	// substrings that the target properties must contain.
+	//
+	// The map is always non-nil, but may be empty.
+	// It panics with a *InvalidPropNameError
+	// if an invalid property name is about to be put into it.
+	Contains() mapping.Map[PropName, string]
+
+	// EqualFold returns a map from property names to strings
+	// that the target properties must be equal to
+	// under simple Unicode case-folding (as strings.EqualFold),
+	// that is, case-insensitive equality.
+	//
+	// As the values of the map are of type string,
+	// non-string values are rejected at compile time.
+	//
+	// The map is always non-nil, but may be empty.
+	// It panics with a *InvalidPropNameError
+	// if an invalid property name is about to be put into it.
+	EqualFold() mapping.Map[PropName, string]
+
+	// Matches returns a map from property names to
+	// regular expressions that the target properties must match.
+	//
+	// The map is always non-nil, but may be empty.
+	// It panics with a *InvalidPropNameError
+	// if an invalid property name is about to be put into it,
+	// and panics if a nil regular expression is about to be put into it.
+	Matches() mapping.Map[PropName, *regexp.Regexp]
+
+	// PropType returns a PropTypeMap with property types
+	// that the target properties must be of.
+	//
+	// A property satisfies the condition if it exists and
+	// the result of function PropTypeOf on its value
+	// is the specified property type, regardless of the value.
+	// This is stricter than the component Present.
+	//
+	// The PropTypeMap is always non-nil, but may be empty.
+	PropType() PropTypeMap
+
+	// Clone returns a deep copy of this PropMatchClause.
+	//
+	// The copy is independent of this PropMatchClause:
+	// modifying one of them does not affect the other.
+	// In particular, the []byte values and the slices of scalars are copied.
+	// The regular expressions are shared,
+	// as they are safe for concurrent use.
+	Clone() PropMatchClause
+
+	// String returns a readable and deterministic representation of
+	// this PropMatchClause, for debugging and logging.
+	//
+	// The conditions are sorted so that the result is stable across runs.
+	String() string
+
 	// Match reports whether props satisfy this PropMatchClause.
 	Match(props PropMap) bool
 }
 
 // propMatchClauseImpl is an implementation of interface PropMatchClause.
 type propMatchClauseImpl struct {
-	equal   *mutExclPropMap     // Properties that must be equal to the target properties.
+	equal   *mutExclMap[any]    // Properties that must be equal to the target properties.
 	present *mutExclPropNameSet // Names of the properties that must exist.
 	absent  *mutExclPropNameSet // Names of the properties that must not exist.
+	ne      *mutExclMap[any]    // Properties that must exist and be different from the target properties.
+	in      *mutExclMap[[]any]  // Lists of values, one of which the target properties must be equal to.
+	gt      *mutExclMap[any]    // Thresholds that the target properties must be greater than.
+	ge      *mutExclMap[any]    // Thresholds that the target properties must be greater than or equal to.
+	lt      *mutExclMap[any]    // Thresholds that the target properties must be less than.
+	le      *mutExclMap[any]    // Thresholds that the target properties must be less than or equal to.
+
+	prefix   *mutExclMap[string]         // Prefixes that the target properties must begin with.
+	contains *mutExclMap[string]         // Substrings that the target properties must contain.
+	fold     *mutExclMap[string]         // Strings that the target properties must be equal to under case-folding.
+	matches  *mutExclMap[*regexp.Regexp] // Regular expressions that the target properties must match.
+
+	ptype *mutExclMap[PropType] // Property types that the target properties must be of.
 }
 
 // NewPropMatchClause creates a new PropMatchClause.
@@ -71,13 +246,39 @@ type propMatchClauseImpl struct {
 // If eqCap is negative, it is ignored, as are presentCap and absentCap.
 func NewPropMatchClause(eqCap, presentCap, absentCap int) PropMatchClause {
 	pmc := &propMatchClauseImpl{
-		equal:   new(mutExclPropMap),
+		equal:   new(mutExclMap[any]),
 		present: new(mutExclPropNameSet),
 		absent:  new(mutExclPropNameSet),
+		ne:      new(mutExclMap[any]),
+		in:      new(mutExclMap[[]any]),
+		gt:      new(mutExclMap[any]),
+		ge:      new(mutExclMap[any]),
+		lt:      new(mutExclMap[any]),
+		le:      new(mutExclMap[any]),
+
+		prefix:   new(mutExclMap[string]),
+		contains: new(mutExclMap[string]),
+		fold:     new(mutExclMap[string]),
+		matches:  new(mutExclMap[*regexp.Regexp]),
+
+		ptype: new(mutExclMap[PropType]),
 	}
-	pmc.equal.init(eqCap, pmc.present, pmc.absent)
+	pmc.equal.init(NewPropMap(eqCap), pmc.present, pmc.absent, pmc.ne)
 	pmc.present.init(presentCap, pmc.equal, pmc.absent)
-	pmc.absent.init(absentCap, pmc.equal, pmc.present)
+	pmc.absent.init(absentCap, pmc.equal, pmc.present, pmc.ne, pmc.in,
+		pmc.gt, pmc.ge, pmc.lt, pmc.le,
+		pmc.prefix, pmc.contains, pmc.fold, pmc.matches, pmc.ptype)
+	pmc.ne.init(NewPropMap(0), pmc.equal, pmc.absent)
+	pmc.in.init(newPropValuesMap(), pmc.absent)
+	pmc.gt.init(NewPropMap(0), pmc.absent)
+	pmc.ge.init(NewPropMap(0), pmc.absent)
+	pmc.lt.init(NewPropMap(0), pmc.absent)
+	pmc.le.init(NewPropMap(0), pmc.absent)
+	pmc.prefix.init(newPropStringMap(), pmc.absent)
+	pmc.contains.init(newPropStringMap(), pmc.absent)
+	pmc.fold.init(newPropStringMap(), pmc.absent)
+	pmc.matches.init(newPropRegexpMap(), pmc.absent)
+	pmc.ptype.init(NewPropTypeMap(0), pmc.absent)
 	return pmc
 }
 
@@ -93,15 +294,99 @@ func (pmc *propMatchClauseImpl) Absent() PropNameSet {
 	return pmc.absent
 }
 
+func (pmc *propMatchClauseImpl) NotEqual() PropMap {
+	return pmc.ne
+}
+
+func (pmc *propMatchClauseImpl) In() mapping.Map[PropName, []any] {
+	return pmc.in
+}
+
+func (pmc *propMatchClauseImpl) GreaterThan() PropMap {
+	return pmc.gt
+}
+
+func (pmc *propMatchClauseImpl) GreaterEqual() PropMap {
+	return pmc.ge
+}
+
+func (pmc *propMatchClauseImpl) LessThan() PropMap {
+	return pmc.lt
+}
+
+func (pmc *propMatchClauseImpl) LessEqual() PropMap {
+	return pmc.le
+}
+
+func (pmc *propMatchClauseImpl) HasPrefix() mapping.Map[PropName, string] {
+	return pmc.prefix
+}
+
+func (pmc *propMatchClauseImpl) Contains() mapping.Map[PropName, string] {
+	return pmc.contains
+}
+
+func (pmc *propMatchClauseImpl) EqualFold() mapping.Map[PropName, string] {
+	return pmc.fold
+}
+
+func (pmc *propMatchClauseImpl) Matches() mapping.Map[PropName, *regexp.Regexp] {
+	return pmc.matches
+}
+
+func (pmc *propMatchClauseImpl) PropType() PropTypeMap {
+	return pmc.ptype
+}
+
+func (pmc *propMatchClauseImpl) Clone() PropMatchClause {
+	c := NewPropMatchClause(
+		pmc.equal.Len(),
+		pmc.present.Len(),
+		pmc.absent.Len(),
+	).(*propMatchClauseImpl)
+	// As the components of pmc are already mutually exclusive,
+	// copy them to the underlying collections of c directly.
+	for _, x := range [...]struct{ dst, src *mutExclMap[any] }{
+		{c.equal, pmc.equal},
+		{c.ne, pmc.ne},
+		{c.gt, pmc.gt},
+		{c.ge, pmc.ge},
+		{c.lt, pmc.lt},
+		{c.le, pmc.le},
+	} {
+		copyMapEntries(x.dst.m, x.src.m, copyPropValue)
+	}
+	c.present.s.Union(pmc.present.s)
+	c.absent.s.Union(pmc.absent.s)
+	copyMapEntries(c.in.m, pmc.in.m, func(v []any) []any {
+		values := make([]any, len(v))
+		for i := range v {
+			values[i] = copyPropValue(v[i])
+		}
+		return values
+	})
+	copyMapEntries(c.prefix.m, pmc.prefix.m, nil)
+	copyMapEntries(c.contains.m, pmc.contains.m, nil)
+	copyMapEntries(c.fold.m, pmc.fold.m, nil)
+	copyMapEntries(c.matches.m, pmc.matches.m, nil)
+	copyMapEntries(c.ptype.m, pmc.ptype.m, nil)
+	return c
+}
+
 func (pmc *propMatchClauseImpl) Match(props PropMap) bool {
 	if props == nil {
-		return pmc.equal.Len() == 0 && pmc.present.Len() == 0
+		return pmc.equal.Len() == 0 && pmc.present.Len() == 0 &&
+			pmc.ne.Len() == 0 && pmc.in.Len() == 0 && pmc.gt.Len() == 0 && pmc.ge.Len() == 0 &&
+			pmc.lt.Len() == 0 && pmc.le.Len() == 0 &&
+			pmc.prefix.Len() == 0 && pmc.contains.Len() == 0 &&
+			pmc.fold.Len() == 0 && pmc.matches.Len() == 0 &&
+			pmc.ptype.Len() == 0
 	}
-	var ok bool
+	ok := true
 	pmc.equal.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
 		var value any
 		value, ok = props.Get(x.Key)
-		ok = ok && value == x.Value
+		ok = ok && equalPropValues(x.Value, value)
 		return ok
 	})
 	if !ok {
@@ -115,10 +400,291 @@ func (pmc *propMatchClauseImpl) Match(props PropMap) bool {
 		return false
 	}
 	pmc.absent.Range(func(x PropName) (cont bool) {
-		_, ok = props.Get(x)
-		return !ok
+		_, present := props.Get(x)
+		ok = !present
+		return ok
+	})
+	if !ok {
+		return false
+	}
+	pmc.ne.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		var value any
+		value, ok = props.Get(x.Key)
+		ok = ok && !equalPropValues(x.Value, value)
+		return ok
 	})
-	return !ok
+	if !ok {
+		return false
+	}
+	pmc.in.Range(func(x mapping.Entry[PropName, []any]) (cont bool) {
+		value, present := props.Get(x.Key)
+		ok = false
+		if present {
+			for _, v := range x.Value {
+				if equalPropValues(v, value) {
+					ok = true
+					break
+				}
+			}
+		}
+		return ok
+	})
+	if !ok {
+		return false
+	}
+	pmc.ptype.Range(func(x mapping.Entry[PropName, PropType]) (cont bool) {
+		var value any
+		value, ok = props.Get(x.Key)
+		ok = ok && PropTypeOf(value) == x.Value
+		return ok
+	})
+	if !ok {
+		return false
+	}
+	return matchOrderedProps(props, pmc.gt, func(r int) bool {
+		return r > 0
+	}) && matchOrderedProps(props, pmc.ge, func(r int) bool {
+		return r >= 0
+	}) && matchOrderedProps(props, pmc.lt, func(r int) bool {
+		return r < 0
+	}) && matchOrderedProps(props, pmc.le, func(r int) bool {
+		return r <= 0
+	}) && matchByteStringProps[string](props, pmc.prefix, strings.HasPrefix) &&
+		matchByteStringProps[string](props, pmc.contains, strings.Contains) &&
+		matchByteStringProps[string](props, pmc.fold, strings.EqualFold) &&
+		matchByteStringProps[*regexp.Regexp](props, pmc.matches,
+			func(s string, re *regexp.Regexp) bool {
+				return re.MatchString(s)
+			})
+}
+
+// matchByteStringProps reports whether, for each pattern in patterns,
+// the corresponding property exists in props,
+// its value is a byte string (string or []byte),
+// and the value (as a string) and the pattern satisfy test.
+//
+// The caller should guarantee that props is not nil.
+func matchByteStringProps[P any](
+	props PropMap,
+	patterns mapping.Map[PropName, P],
+	test func(s string, pattern P) bool,
+) bool {
+	ok := true
+	patterns.Range(func(x mapping.Entry[PropName, P]) (cont bool) {
+		value, _ := props.Get(x.Key)
+		switch v := value.(type) {
+		case string:
+			ok = test(v, x.Value)
+		case []byte:
+			ok = test(string(v), x.Value)
+		default:
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+// newPropStringMap creates a new map from property names to strings
+// for the string matching components of PropMatchClause.
+//
+// It panics with a *InvalidPropNameError
+// if an invalid property name is about to be put into the map.
+func newPropStringMap() mapping.Map[PropName, string] {
+	return newValidMap(
+		0,
+		func(key PropName) bool {
+			return key.IsValid()
+		},
+		func(key PropName) error {
+			return NewInvalidPropNameError(key.String())
+		},
+		func(value string) bool {
+			return true
+		},
+		nil,
+	)
+}
+
+// newPropValuesMap creates a new map from property names to
+// lists of property values for the component In of PropMatchClause.
+//
+// It panics with a *InvalidPropNameError
+// if an invalid property name is about to be put into the map,
+// and panics with a *InvalidPropValueError
+// if a list with a value that does not conform to PropValue
+// is about to be put into the map.
+func newPropValuesMap() mapping.Map[PropName, []any] {
+	return newValidMap(
+		0,
+		func(key PropName) bool {
+			return key.IsValid()
+		},
+		func(key PropName) error {
+			return NewInvalidPropNameError(key.String())
+		},
+		func(value []any) bool {
+			for _, v := range value {
+				if !PropTypeOf(v).IsValid() {
+					return false
+				}
+			}
+			return true
+		},
+		func(key PropName, value []any) error {
+			for _, v := range value {
+				if !PropTypeOf(v).IsValid() {
+					return NewInvalidPropValueError(key, v)
+				}
+			}
+			return nil // unreachable
+		},
+	)
+}
+
+// newPropRegexpMap creates a new map from property names to
+// regular expressions for the component Matches of PropMatchClause.
+//
+// It panics with a *InvalidPropNameError
+// if an invalid property name is about to be put into the map,
+// and panics if a nil regular expression is about to be put into the map.
+func newPropRegexpMap() mapping.Map[PropName, *regexp.Regexp] {
+	return newValidMap(
+		0,
+		func(key PropName) bool {
+			return key.IsValid()
+		},
+		func(key PropName) error {
+			return NewInvalidPropNameError(key.String())
+		},
+		func(value *regexp.Regexp) bool {
+			return value != nil
+		},
+		func(PropName, *regexp.Regexp) error {
+			return errors.New("regular expression is nil")
+		},
+	)
+}
+
+// copyMapEntries puts the entries in src into dst,
+// with the values processed by copyFn.
+//
+// If copyFn is nil, the values are put as is.
+func copyMapEntries[V any](
+	dst, src mapping.Map[PropName, V],
+	copyFn func(v V) V,
+) {
+	src.Range(func(x mapping.Entry[PropName, V]) (cont bool) {
+		if copyFn != nil {
+			dst.Set(x.Key, copyFn(x.Value))
+		} else {
+			dst.Set(x.Key, x.Value)
+		}
+		return true
+	})
+}
+
+// matchOrderedProps reports whether, for each threshold in thresholds,
+// the corresponding property exists in props and
+// the result of comparing the property value with the threshold
+// (as returned by function compareOrderedPropValues) satisfies test.
+//
+// The caller should guarantee that props is not nil.
+func matchOrderedProps(
+	props PropMap,
+	thresholds PropMap,
+	test func(r int) bool,
+) bool {
+	ok := true
+	thresholds.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		value, present := props.Get(x.Key)
+		var r int
+		if present {
+			r, ok = compareOrderedPropValues(value, x.Value)
+		}
+		ok = present && ok && test(r)
+		return ok
+	})
+	return ok
+}
+
+// compareOrderedPropValues compares the property values a and b.
+//
+// It returns -1 if a < b, 0 if a == b, and +1 if a > b, with ok true.
+// If a and b are not comparable, it returns 0 and false.
+//
+// Two real numbers (integers and floating-point numbers) are comparable
+// unless either is NaN.
+// Two time.Time values are comparable, as are two gosln.Date values.
+// Values of any other combination of types are not comparable.
+func compareOrderedPropValues(a, b any) (r int, ok bool) {
+	aType, bType := PropTypeOf(a), PropTypeOf(b)
+	switch {
+	case aType.IsRealNumber() && bType.IsRealNumber():
+		return compareRealNumbers(reflect.ValueOf(a), reflect.ValueOf(b))
+	case aType == PTTime && bType == PTTime:
+		return a.(time.Time).Compare(b.(time.Time)), true
+	case aType == PTDate && bType == PTDate:
+		return a.(Date).Compare(b.(Date)), true
+	}
+	return 0, false
+}
+
+// compareRealNumbers compares the real numbers a and b
+// without precision loss between integers.
+//
+// It returns -1 if a < b, 0 if a == b, and +1 if a > b, with ok true.
+// If either a or b is NaN, it returns 0 and false.
+//
+// The caller should guarantee that a and b are
+// integers or floating-point numbers.
+func compareRealNumbers(a, b reflect.Value) (r int, ok bool) {
+	aKind, bKind := a.Kind(), b.Kind()
+	switch {
+	case isIntKind(aKind) && isIntKind(bKind):
+		return compareOrdered(a.Int(), b.Int()), true
+	case isUintKind(aKind) && isUintKind(bKind):
+		return compareOrdered(a.Uint(), b.Uint()), true
+	case isIntKind(aKind) && isUintKind(bKind):
+		if a.Int() < 0 {
+			return -1, true
+		}
+		return compareOrdered(uint64(a.Int()), b.Uint()), true
+	case isUintKind(aKind) && isIntKind(bKind):
+		if b.Int() < 0 {
+			return 1, true
+		}
+		return compareOrdered(a.Uint(), uint64(b.Int())), true
+	}
+	x, y := realNumberToFloat64(a), realNumberToFloat64(b)
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return 0, false
+	}
+	return compareOrdered(x, y), true
+}
+
+// realNumberToFloat64 converts the integer or floating-point number v
+// to float64.
+func realNumberToFloat64(v reflect.Value) float64 {
+	switch k := v.Kind(); {
+	case isIntKind(k):
+		return float64(v.Int())
+	case isUintKind(k):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// compareOrdered returns -1 if a < b, 0 if a == b, and +1 if a > b.
+//
+// The caller should guarantee that neither a nor b is NaN.
+func compareOrdered[T constraints.Real](a, b T) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
 }
 
 // PropMatchCond is a disjunction of the clauses of type PropMatchClause
@@ -134,6 +700,24 @@ func (pmc *propMatchClauseImpl) Match(props PropMap) bool {
 // A non-nil but empty PropMatchCond matches nothing.
 type PropMatchCond []PropMatchClause
 
+// Clone returns a deep copy of cond.
+//
+// Each non-nil PropMatchClause is copied by its method Clone,
+// and the nil ones remain nil.
+// If cond is nil, Clone returns nil.
+func (cond PropMatchCond) Clone() PropMatchCond {
+	if cond == nil {
+		return nil
+	}
+	c := make(PropMatchCond, len(cond))
+	for i, pmc := range cond {
+		if pmc != nil {
+			c[i] = pmc.Clone()
+		}
+	}
+	return c
+}
+
 // Match reports whether props satisfy this PropMatchCond.
 func (cond PropMatchCond) Match(props PropMap) bool {
 	if cond == nil {
@@ -242,9 +826,26 @@ func (nlmc *nlMatchClauseImpl) SetPropMatchClause(pmc PropMatchClause) {
 //
 // NodeMatchClause can specify the node ID, node type,
 // and properties on the node.
+//
+// By default, NodeMatchClause does not match soft-removed nodes.
+// To include them, use its method SetIncludeDeleted.
 type NodeMatchClause interface {
 	NLMatchClause
 
+	// GetIncludeDeleted reports whether the soft-removed nodes
+	// can satisfy this NodeMatchClause.
+	GetIncludeDeleted() bool
+
+	// SetIncludeDeleted specifies whether the soft-removed nodes
+	// can satisfy this NodeMatchClause.
+	SetIncludeDeleted(include bool)
+
+	// String returns a readable and deterministic representation of
+	// this NodeMatchClause, for debugging and logging.
+	//
+	// The conditions are sorted so that the result is stable across runs.
+	String() string
+
 	// Match reports whether the semantic node satisfies this NodeMatchClause.
 	Match(node *Node) bool
 }
@@ -252,6 +853,7 @@ type NodeMatchClause interface {
 // nodeMatchClauseImpl is an implementation of interface NodeMatchClause.
 type nodeMatchClauseImpl struct {
 	nlMatchClauseImpl
+	includeDeleted bool // Whether to match soft-removed nodes.
 }
 
 // NewNodeMatchClause creates a new NodeMatchClause.
@@ -261,12 +863,21 @@ func NewNodeMatchClause() NodeMatchClause {
 
 func (nmc *nodeMatchClauseImpl) SetIDAndClearOtherConds(id ID) {
 	nmc.SetID(id)
-	nmc.t, nmc.pmc = Type{}, nil
+	nmc.t, nmc.pmc, nmc.includeDeleted = Type{}, nil, false
+}
+
+func (nmc *nodeMatchClauseImpl) GetIncludeDeleted() bool {
+	return nmc.includeDeleted
+}
+
+func (nmc *nodeMatchClauseImpl) SetIncludeDeleted(include bool) {
+	nmc.includeDeleted = include
 }
 
 func (nmc *nodeMatchClauseImpl) Match(node *Node) bool {
 	switch {
 	case node == nil:
+	case node.Deleted && !nmc.includeDeleted:
 	case nmc.id.IsValid() && node.ID != nmc.id:
 	case nmc.t.IsValid() && node.Type != nmc.t:
 	case nmc.pmc != nil && !nmc.pmc.Match(node.Props):
@@ -284,14 +895,15 @@ func (nmc *nodeMatchClauseImpl) Match(node *Node) bool {
 // A semantic node satisfies the NodeMatchCond
 // if it satisfies any of these clauses.
 //
-// In particular, a nil NodeMatchCond matches any semantic node (including nil).
+// In particular, a nil NodeMatchCond matches any semantic node
+// that has not been soft-removed (including nil).
 // A non-nil but empty NodeMatchCond matches nothing.
 type NodeMatchCond []NodeMatchClause
 
 // Match reports whether the semantic node satisfies this NodeMatchCond.
 func (cond NodeMatchCond) Match(node *Node) bool {
 	if cond == nil {
-		return true
+		return node == nil || !node.Deleted
 	}
 	for _, nmc := range cond {
 		if nmc != nil && nmc.Match(node) {
@@ -335,6 +947,12 @@ type LinkMatchClause interface {
 	// If nmc is nil, it considers no limit on the node.
 	SetToNodeMatchClause(nmc NodeMatchClause)
 
+	// String returns a readable and deterministic representation of
+	// this LinkMatchClause, for debugging and logging.
+	//
+	// The conditions are sorted so that the result is stable across runs.
+	String() string
+
 	// Match reports whether the semantic link satisfies this LinkMatchClause.
 	Match(link *Link) bool
 }
@@ -0,0 +1,416 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+)
+
+// CanonicalKey returns a deterministic string representation of cond,
+// which is suitable for use as a key to cache query results.
+//
+// Semantically equal conditions produce identical keys,
+// even if they are built in different orders.
+// In particular, the clauses are sorted and deduplicated,
+// the property names in each clause are sorted,
+// regular expressions are compared by their source text,
+// nil clauses are ignored,
+// and an empty PropMatchClause is treated as no limit on the properties.
+//
+// A nil NodeMatchCond and a non-nil but empty NodeMatchCond
+// produce different keys, as they have different semantics.
+//
+// It reports an error if any property value in cond is invalid.
+func (cond NodeMatchCond) CanonicalKey() (string, error) {
+	if cond == nil {
+		return "N*", nil
+	}
+	keys := make([]string, 0, len(cond))
+	for _, nmc := range cond {
+		if nmc == nil {
+			continue
+		}
+		var b strings.Builder
+		err := writeNodeMatchClauseKey(&b, nmc)
+		if err != nil {
+			return "", errors.AutoWrap(err)
+		}
+		keys = append(keys, b.String())
+	}
+	return joinClauseKeys("N", keys), nil
+}
+
+// CanonicalKey returns a deterministic string representation of cond,
+// which is suitable for use as a key to cache query results.
+//
+// Semantically equal conditions produce identical keys,
+// even if they are built in different orders.
+// In particular, the clauses are sorted and deduplicated,
+// the property names in each clause are sorted,
+// regular expressions are compared by their source text,
+// nil clauses are ignored,
+// and an empty PropMatchClause is treated as no limit on the properties.
+//
+// A nil LinkMatchCond and a non-nil but empty LinkMatchCond
+// produce different keys, as they have different semantics.
+//
+// It reports an error if any property value in cond is invalid.
+func (cond LinkMatchCond) CanonicalKey() (string, error) {
+	if cond == nil {
+		return "L*", nil
+	}
+	keys := make([]string, 0, len(cond))
+	for _, lmc := range cond {
+		if lmc == nil {
+			continue
+		}
+		var b strings.Builder
+		err := writeLinkMatchClauseKey(&b, lmc)
+		if err != nil {
+			return "", errors.AutoWrap(err)
+		}
+		keys = append(keys, b.String())
+	}
+	return joinClauseKeys("L", keys), nil
+}
+
+// joinClauseKeys sorts and deduplicates the clause keys,
+// and then joins them with the specified prefix.
+func joinClauseKeys(prefix string, keys []string) string {
+	sort.Strings(keys)
+	n := 0
+	for i := range keys {
+		if i == 0 || keys[i] != keys[n-1] {
+			keys[n] = keys[i]
+			n++
+		}
+	}
+	return prefix + "[" + strings.Join(keys[:n], ",") + "]"
+}
+
+// writeNodeMatchClauseKey writes the canonical key of nmc to b.
+//
+// The caller should guarantee that nmc is not nil.
+func writeNodeMatchClauseKey(b *strings.Builder, nmc NodeMatchClause) error {
+	b.WriteByte('{')
+	err := writeNLMatchClauseKey(b, nmc)
+	if err != nil {
+		return err
+	}
+	if nmc.GetIncludeDeleted() {
+		b.WriteString("deleted;")
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// writeLinkMatchClauseKey writes the canonical key of lmc to b.
+//
+// The caller should guarantee that lmc is not nil.
+func writeLinkMatchClauseKey(b *strings.Builder, lmc LinkMatchClause) error {
+	b.WriteByte('{')
+	err := writeNLMatchClauseKey(b, lmc)
+	if err != nil {
+		return err
+	}
+	for _, end := range [2]struct {
+		name string
+		nmc  NodeMatchClause
+	}{
+		{"from", lmc.GetFromNodeMatchClause()},
+		{"to", lmc.GetToNodeMatchClause()},
+	} {
+		if end.nmc == nil {
+			continue
+		}
+		b.WriteString(end.name)
+		b.WriteByte('=')
+		err = writeNodeMatchClauseKey(b, end.nmc)
+		if err != nil {
+			return err
+		}
+		b.WriteByte(';')
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+// writeNLMatchClauseKey writes the canonical key of
+// the ID, type, and property conditions in nlmc to b.
+//
+// The caller should guarantee that nlmc is not nil.
+func writeNLMatchClauseKey(b *strings.Builder, nlmc NLMatchClause) error {
+	if id := nlmc.GetID(); id.IsValid() {
+		b.WriteString("id=")
+		b.WriteString(strconv.Quote(id.String()))
+		b.WriteByte(';')
+	}
+	if t := nlmc.GetType(); t.IsValid() {
+		b.WriteString("type=")
+		b.WriteString(t.String())
+		b.WriteByte(';')
+	}
+	pmc := nlmc.GetPropMatchClause()
+	if pmc == nil {
+		return nil
+	}
+	var names []PropName
+	for _, c := range [...]struct {
+		name string
+		pm   PropMap
+	}{
+		{"eq", pmc.Equal()},
+		{"ne", pmc.NotEqual()},
+		{"gt", pmc.GreaterThan()},
+		{"ge", pmc.GreaterEqual()},
+		{"lt", pmc.LessThan()},
+		{"le", pmc.LessEqual()},
+	} {
+		if c.pm.Len() == 0 {
+			continue
+		}
+		names = sortedPropMapNames[any](c.pm, names[:0])
+		b.WriteString(c.name)
+		b.WriteString("={")
+		for _, name := range names {
+			value, _ := c.pm.Get(name)
+			b.WriteString(name.String())
+			b.WriteByte(':')
+			err := writeCanonicalPropValue(b, value)
+			if err != nil {
+				return err
+			}
+			b.WriteByte(';')
+		}
+		b.WriteString("};")
+	}
+	for _, c := range [...]struct {
+		name string
+		m    mapping.Map[PropName, string]
+	}{
+		{"prefix", pmc.HasPrefix()},
+		{"contains", pmc.Contains()},
+		{"fold", pmc.EqualFold()},
+	} {
+		if c.m.Len() == 0 {
+			continue
+		}
+		names = sortedPropMapNames[string](c.m, names[:0])
+		b.WriteString(c.name)
+		b.WriteString("={")
+		for _, name := range names {
+			s, _ := c.m.Get(name)
+			b.WriteString(name.String())
+			b.WriteByte(':')
+			b.WriteString(strconv.Quote(s))
+			b.WriteByte(';')
+		}
+		b.WriteString("};")
+	}
+	if in := pmc.In(); in.Len() > 0 {
+		names = sortedPropMapNames[[]any](in, names[:0])
+		b.WriteString("in={")
+		for _, name := range names {
+			values, _ := in.Get(name)
+			b.WriteString(name.String())
+			b.WriteString(":[")
+			err := writeCanonicalPropValueList(b, values)
+			if err != nil {
+				return err
+			}
+			b.WriteString("];")
+		}
+		b.WriteString("};")
+	}
+	if matches := pmc.Matches(); matches.Len() > 0 {
+		names = sortedPropMapNames[*regexp.Regexp](matches, names[:0])
+		b.WriteString("regexp={")
+		for _, name := range names {
+			re, _ := matches.Get(name)
+			b.WriteString(name.String())
+			b.WriteByte(':')
+			b.WriteString(strconv.Quote(re.String()))
+			b.WriteByte(';')
+		}
+		b.WriteString("};")
+	}
+	if ptype := pmc.PropType(); ptype.Len() > 0 {
+		names = sortedPropMapNames[PropType](ptype, names[:0])
+		b.WriteString("ptype={")
+		for _, name := range names {
+			pt, _ := ptype.Get(name)
+			b.WriteString(name.String())
+			b.WriteByte(':')
+			b.WriteString(pt.String())
+			b.WriteByte(';')
+		}
+		b.WriteString("};")
+	}
+	for _, c := range [2]struct {
+		name string
+		pns  PropNameSet
+	}{
+		{"present", pmc.Present()},
+		{"absent", pmc.Absent()},
+	} {
+		if c.pns.Len() == 0 {
+			continue
+		}
+		names = sortedPropNameSetNames(c.pns, names[:0])
+		b.WriteString(c.name)
+		b.WriteString("={")
+		for _, name := range names {
+			b.WriteString(name.String())
+			b.WriteByte(';')
+		}
+		b.WriteString("};")
+	}
+	return nil
+}
+
+// sortedPropMapNames appends the property names in pm to names,
+// sorts them in ascending order of their string values,
+// and returns the result.
+func sortedPropMapNames[V any](
+	pm mapping.Map[PropName, V],
+	names []PropName,
+) []PropName {
+	pm.Range(func(x mapping.Entry[PropName, V]) (cont bool) {
+		names = append(names, x.Key)
+		return true
+	})
+	sortPropNames(names)
+	return names
+}
+
+// sortedPropNameSetNames appends the property names in pns to names,
+// sorts them in ascending order of their string values,
+// and returns the result.
+func sortedPropNameSetNames(pns PropNameSet, names []PropName) []PropName {
+	pns.Range(func(x PropName) (cont bool) {
+		names = append(names, x)
+		return true
+	})
+	sortPropNames(names)
+	return names
+}
+
+// sortPropNames sorts names in ascending order of their string values.
+func sortPropNames(names []PropName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Compare(names[j]) < 0
+	})
+}
+
+// writeCanonicalPropValue writes the property type and
+// an exact string representation of the property value v to b.
+//
+// The values equal as compared by the method Match of PropMatchClause
+// have the same representation:
+// the time.Time values are represented in UTC,
+// and the negative zeros of floating-point and complex numbers
+// are represented as positive zeros.
+//
+// It reports an error if v is not a valid property value.
+func writeCanonicalPropValue(b *strings.Builder, v any) error {
+	pt := PropTypeOf(v)
+	if !pt.IsValid() {
+		return NewInvalidPropValueError(PropName{}, v)
+	}
+	v = normalizeZeroAndTime(v)
+	b.WriteString(pt.String())
+	b.WriteByte('(')
+	if s, ok := v.(string); ok {
+		b.WriteString(strconv.Quote(s))
+	} else {
+		b.WriteString(FormatPropValue(v))
+	}
+	b.WriteByte(')')
+	return nil
+}
+
+// normalizeZeroAndTime returns v with its negative zeros
+// (of floating-point and complex numbers) replaced by positive zeros,
+// or v in UTC if v is a time.Time.
+//
+// The slice v is copied if any element is replaced.
+func normalizeZeroAndTime(v any) any {
+	switch x := v.(type) {
+	case time.Time:
+		return x.UTC()
+	case float32:
+		if x == 0 {
+			return float32(0)
+		}
+	case float64:
+		if x == 0 {
+			return float64(0)
+		}
+	case complex64:
+		return complex(normalizeZeroAndTime(real(x)).(float32), normalizeZeroAndTime(imag(x)).(float32))
+	case complex128:
+		return complex(normalizeZeroAndTime(real(x)).(float64), normalizeZeroAndTime(imag(x)).(float64))
+	case []float64:
+		for i := range x {
+			if x[i] == 0 && math.Signbit(x[i]) {
+				c := make([]float64, len(x))
+				for j := range x {
+					c[j] = normalizeZeroAndTime(x[j]).(float64)
+				}
+				return c
+			}
+		}
+	}
+	return v
+}
+
+// writeCanonicalPropValueList writes the canonical representations
+// (as written by function writeCanonicalPropValue) of the property values
+// in values to b, sorted, deduplicated, and separated by commas.
+//
+// It reports an error if any value in values is not a valid property value.
+func writeCanonicalPropValueList(b *strings.Builder, values []any) error {
+	keys := make([]string, len(values))
+	for i, v := range values {
+		var vb strings.Builder
+		err := writeCanonicalPropValue(&vb, v)
+		if err != nil {
+			return err
+		}
+		keys[i] = vb.String()
+	}
+	sort.Strings(keys)
+	for i := range keys {
+		if i > 0 && keys[i] == keys[i-1] {
+			continue
+		} else if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(keys[i])
+	}
+	return nil
+}
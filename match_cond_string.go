@@ -0,0 +1,267 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/donyori/gogo/container/mapping"
+)
+
+// String returns a readable and deterministic representation of pmc,
+// for example:
+//
+//	name="Bob" AND age>=18 AND Present{email}
+//
+// The conditions are grouped by component in the following order:
+// Equal, NotEqual, In, GreaterThan, GreaterEqual, LessThan, LessEqual,
+// HasPrefix, Contains, EqualFold, Matches, PropType, Present, and Absent.
+// Within each component, the conditions are sorted by property name.
+// The conditions are joined by " AND ".
+//
+// If pmc has no condition, String returns "TRUE".
+func (pmc *propMatchClauseImpl) String() string {
+	var terms []string
+	var names []PropName
+	for _, c := range [...]struct {
+		op string
+		pm PropMap
+	}{
+		{"=", pmc.equal},
+		{"!=", pmc.ne},
+	} {
+		names = sortedPropMapNames[any](c.pm, names[:0])
+		for _, name := range names {
+			v, _ := c.pm.Get(name)
+			terms = append(terms, name.String()+c.op+formatMatchValue(v))
+		}
+	}
+	names = sortedPropMapNames[[]any](pmc.in, names[:0])
+	for _, name := range names {
+		values, _ := pmc.in.Get(name)
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = formatMatchValue(v)
+		}
+		terms = append(terms, name.String()+" IN ["+strings.Join(list, ", ")+"]")
+	}
+	for _, c := range [...]struct {
+		op string
+		pm PropMap
+	}{
+		{">", pmc.gt},
+		{">=", pmc.ge},
+		{"<", pmc.lt},
+		{"<=", pmc.le},
+	} {
+		names = sortedPropMapNames[any](c.pm, names[:0])
+		for _, name := range names {
+			v, _ := c.pm.Get(name)
+			terms = append(terms, name.String()+c.op+formatMatchValue(v))
+		}
+	}
+	for _, c := range [...]struct {
+		fn string
+		m  mapping.Map[PropName, string]
+	}{
+		{"HasPrefix", pmc.prefix},
+		{"Contains", pmc.contains},
+		{"EqualFold", pmc.fold},
+	} {
+		names = sortedPropMapNames[string](c.m, names[:0])
+		for _, name := range names {
+			s, _ := c.m.Get(name)
+			terms = append(terms, c.fn+"("+name.String()+", "+strconv.Quote(s)+")")
+		}
+	}
+	names = sortedPropMapNames[*regexp.Regexp](pmc.matches, names[:0])
+	for _, name := range names {
+		re, _ := pmc.matches.Get(name)
+		terms = append(terms, "Matches("+name.String()+", "+strconv.Quote(re.String())+")")
+	}
+	names = sortedPropMapNames[PropType](pmc.ptype, names[:0])
+	for _, name := range names {
+		pt, _ := pmc.ptype.Get(name)
+		terms = append(terms, "TypeOf("+name.String()+")="+pt.String())
+	}
+	for _, c := range [...]struct {
+		name string
+		pns  PropNameSet
+	}{
+		{"Present", pmc.present},
+		{"Absent", pmc.absent},
+	} {
+		if c.pns.Len() == 0 {
+			continue
+		}
+		names = sortedPropNameSetNames(c.pns, names[:0])
+		list := make([]string, len(names))
+		for i, name := range names {
+			list[i] = name.String()
+		}
+		terms = append(terms, c.name+"{"+strings.Join(list, ", ")+"}")
+	}
+	return joinMatchTerms(terms)
+}
+
+// String returns a readable and deterministic representation of nmc,
+// for example:
+//
+//	Type=Person AND name="Bob" AND Present{age}
+//
+// The conditions are in the following order:
+// ID, Type, the conditions on properties
+// (as formatted by the method String of PropMatchClause),
+// and IncludeDeleted.
+// They are joined by " AND ".
+//
+// If nmc has no condition, String returns "TRUE".
+func (nmc *nodeMatchClauseImpl) String() string {
+	terms := nmc.nlMatchClauseImpl.appendTerms(nil)
+	if nmc.includeDeleted {
+		terms = append(terms, "IncludeDeleted")
+	}
+	return joinMatchTerms(terms)
+}
+
+// String returns a readable and deterministic representation of lmc,
+// for example:
+//
+//	Type=Knows AND since>2020 AND From(Type=Person AND name="Bob")
+//
+// The conditions are in the following order:
+// ID, Type, the conditions on properties
+// (as formatted by the method String of PropMatchClause),
+// the conditions on the node from which the link starts,
+// and the conditions on the node to which the link points.
+// They are joined by " AND ".
+//
+// If lmc has no condition, String returns "TRUE".
+func (lmc *linkMatchClauseImpl) String() string {
+	terms := lmc.nlMatchClauseImpl.appendTerms(nil)
+	if lmc.from != nil {
+		terms = append(terms, "From("+lmc.from.String()+")")
+	}
+	if lmc.to != nil {
+		terms = append(terms, "To("+lmc.to.String()+")")
+	}
+	return joinMatchTerms(terms)
+}
+
+// appendTerms appends the readable representations of the conditions
+// on the ID, type, and properties in nlmc to terms,
+// and returns the result.
+func (nlmc *nlMatchClauseImpl) appendTerms(terms []string) []string {
+	if nlmc.id.IsValid() {
+		terms = append(terms, "ID="+nlmc.id.String())
+	}
+	if nlmc.t.IsValid() {
+		terms = append(terms, "Type="+nlmc.t.String())
+	}
+	if nlmc.pmc != nil {
+		if s := nlmc.pmc.String(); s != "TRUE" {
+			terms = append(terms, s)
+		}
+	}
+	return terms
+}
+
+// String returns a readable and deterministic representation of cond.
+//
+// The non-nil clauses are formatted by their method String,
+// enclosed in parentheses if there is more than one clause,
+// and joined by " OR " in their order in cond.
+//
+// In particular, String returns "ANY" if cond is nil,
+// and "NONE" if cond has no non-nil clause.
+func (cond NodeMatchCond) String() string {
+	if cond == nil {
+		return "ANY"
+	}
+	clauses := make([]string, 0, len(cond))
+	for _, nmc := range cond {
+		if nmc != nil {
+			clauses = append(clauses, nmc.String())
+		}
+	}
+	return joinMatchClauses(clauses)
+}
+
+// String returns a readable and deterministic representation of cond.
+//
+// The non-nil clauses are formatted by their method String,
+// enclosed in parentheses if there is more than one clause,
+// and joined by " OR " in their order in cond.
+//
+// In particular, String returns "ANY" if cond is nil,
+// and "NONE" if cond has no non-nil clause.
+func (cond LinkMatchCond) String() string {
+	if cond == nil {
+		return "ANY"
+	}
+	clauses := make([]string, 0, len(cond))
+	for _, lmc := range cond {
+		if lmc != nil {
+			clauses = append(clauses, lmc.String())
+		}
+	}
+	return joinMatchClauses(clauses)
+}
+
+// joinMatchTerms joins the terms of a clause with " AND ".
+//
+// If terms is empty, it returns "TRUE".
+func joinMatchTerms(terms []string) string {
+	if len(terms) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(terms, " AND ")
+}
+
+// joinMatchClauses joins the representations of clauses with " OR ",
+// enclosing each in parentheses if there is more than one clause.
+//
+// If clauses is empty, it returns "NONE".
+func joinMatchClauses(clauses []string) string {
+	switch len(clauses) {
+	case 0:
+		return "NONE"
+	case 1:
+		return clauses[0]
+	}
+	return "(" + strings.Join(clauses, ") OR (") + ")"
+}
+
+// formatMatchValue returns a readable representation of
+// the property value v in a match condition.
+//
+// Strings are quoted, []byte values are formatted as
+// []byte("...") with the bytes quoted,
+// and other values are formatted by the function FormatPropValue.
+func formatMatchValue(v any) string {
+	switch x := v.(type) {
+	case string:
+		return strconv.Quote(x)
+	case []byte:
+		return "[]byte(" + strconv.Quote(string(x)) + ")"
+	}
+	return FormatPropValue(v)
+}
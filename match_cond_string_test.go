@@ -0,0 +1,103 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestMatchCond_String(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	name, age := gosln.MustNewPropName("name"), gosln.MustNewPropName("age")
+	email, phone := gosln.MustNewPropName("email"), gosln.MustNewPropName("phone")
+	since := gosln.MustNewPropName("since")
+	id := gosln.NewID(person, gosln.DateOfYearMonthDay(2023, time.March, 12), 1)
+
+	pmc := gosln.NewPropMatchClause(0, 0, 0)
+	pmc.Absent().Add(phone, email)
+	pmc.Present().Add(age)
+	pmc.Matches().Set(name, regexp.MustCompile(`^B`))
+	pmc.LessThan().Set(age, 65)
+	pmc.GreaterEqual().Set(age, 18)
+	pmc.In().Set(since, []any{2020, 2021})
+	pmc.NotEqual().Set(email, "")
+	pmc.Equal().Set(name, "Bob")
+
+	nmc := gosln.NewNodeMatchClause()
+	nmc.SetType(person)
+	nmc.SetPropMatchClause(pmc)
+	idOnly := gosln.NewNodeMatchClause()
+	idOnly.SetID(id)
+	idOnly.SetIncludeDeleted(true)
+
+	linkPMC := gosln.NewPropMatchClause(0, 0, 0)
+	linkPMC.GreaterThan().Set(since, 2020)
+	lmc := gosln.NewLinkMatchClause()
+	lmc.SetType(knows)
+	lmc.SetPropMatchClause(linkPMC)
+	lmc.SetFromNodeMatchClause(idOnly)
+	lmc.SetToNodeMatchClause(gosln.NewNodeMatchClause())
+
+	testCases := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{
+			"PropMatchClause",
+			pmc.String,
+			`name="Bob" AND email!="" AND since IN [2020, 2021] AND age>=18 AND age<65 AND Matches(name, "^B") AND Present{age} AND Absent{phone}`,
+		},
+		{"empty PropMatchClause", gosln.NewPropMatchClause(0, 0, 0).String, "TRUE"},
+		{
+			"NodeMatchClause",
+			nmc.String,
+			`Type=Person AND name="Bob" AND email!="" AND since IN [2020, 2021] AND age>=18 AND age<65 AND Matches(name, "^B") AND Present{age} AND Absent{phone}`,
+		},
+		{"empty NodeMatchClause", gosln.NewNodeMatchClause().String, "TRUE"},
+		{
+			"LinkMatchClause",
+			lmc.String,
+			"Type=Knows AND since>2020 AND From(ID=" + id.String() + " AND IncludeDeleted) AND To(TRUE)",
+		},
+		{"nil NodeMatchCond", gosln.NodeMatchCond(nil).String, "ANY"},
+		{"empty NodeMatchCond", gosln.NodeMatchCond{nil}.String, "NONE"},
+		{"one-clause NodeMatchCond", gosln.NodeMatchCond{idOnly}.String, "ID=" + id.String() + " AND IncludeDeleted"},
+		{
+			"NodeMatchCond",
+			gosln.NodeMatchCond{idOnly, nil, gosln.NewNodeMatchClause()}.String,
+			"(ID=" + id.String() + " AND IncludeDeleted) OR (TRUE)",
+		},
+		{"nil LinkMatchCond", gosln.LinkMatchCond(nil).String, "ANY"},
+		{"LinkMatchCond", gosln.LinkMatchCond{lmc}.String, lmc.String()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.got(); got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,653 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestPropMatchClause_Match(t *testing.T) {
+	name, data := gosln.MustNewPropName("name"), gosln.MustNewPropName("data")
+	when := gosln.MustNewPropName("when")
+	tm := time.Date(2023, time.March, 12, 8, 0, 0, 0, time.UTC)
+	props := gosln.NewPropMap(3)
+	props.Set(name, "Alice")
+	props.Set(data, []byte("abc"))
+	props.Set(when, tm)
+
+	newClause := func(kv ...any) gosln.PropMatchClause {
+		pmc := gosln.NewPropMatchClause(len(kv)/2, 0, 0)
+		for i := 0; i < len(kv); i += 2 {
+			pmc.Equal().Set(kv[i].(gosln.PropName), kv[i+1])
+		}
+		return pmc
+	}
+	presentOnly := gosln.NewPropMatchClause(0, 1, 0)
+	presentOnly.Present().Add(name)
+	absentName := gosln.NewPropMatchClause(0, 0, 1)
+	absentName.Absent().Add(name)
+	absentOther := gosln.NewPropMatchClause(0, 0, 1)
+	absentOther.Absent().Add(gosln.MustNewPropName("other"))
+
+	testCases := []struct {
+		name string
+		pmc  gosln.PropMatchClause
+		want bool
+	}{
+		{"empty", newClause(), true},
+		{"present only", presentOnly, true},
+		{"absent violated", absentName, false},
+		{"absent satisfied", absentOther, true},
+		{"string equal", newClause(name, "Alice"), true},
+		{"string not equal", newClause(name, "Bob"), false},
+		{"bytes equal", newClause(data, []byte("abc")), true},
+		{"bytes not equal", newClause(data, []byte("abd")), false},
+		{"bytes vs string", newClause(data, "abc"), false},
+		{"time in another location", newClause(when, tm.In(time.FixedZone("UTC+8", 8*3600))), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropMatchClause_Match_Range(t *testing.T) {
+	age, score := gosln.MustNewPropName("age"), gosln.MustNewPropName("score")
+	when, day := gosln.MustNewPropName("when"), gosln.MustNewPropName("day")
+	name := gosln.MustNewPropName("name")
+	tm := time.Date(2023, time.March, 12, 8, 0, 0, 0, time.UTC)
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	props := gosln.NewPropMap(5)
+	props.Set(age, 20)
+	props.Set(score, 3.5)
+	props.Set(when, tm)
+	props.Set(day, date)
+	props.Set(name, "Alice")
+
+	type cmp struct {
+		op    string
+		name  gosln.PropName
+		value any
+	}
+	testCases := []struct {
+		name  string
+		conds []cmp
+		want  bool
+	}{
+		{"int gt int", []cmp{{">", age, 18}}, true},
+		{"int gt equal int", []cmp{{">", age, 20}}, false},
+		{"int ge equal int", []cmp{{">=", age, 20}}, true},
+		{"int lt uint", []cmp{{"<", age, uint8(21)}}, true},
+		{"int ge float", []cmp{{">=", age, 19.5}}, true},
+		{"int le float", []cmp{{"<=", age, 19.5}}, false},
+		{"int gt negative", []cmp{{">", age, int64(-1)}}, true},
+		{"float lt int", []cmp{{"<", score, 4}}, true},
+		{"float gt NaN", []cmp{{">", score, math.NaN()}}, false},
+		{"between", []cmp{{">=", age, 18}, {"<", age, 30}}, true},
+		{"not between", []cmp{{">=", age, 21}, {"<", age, 30}}, false},
+		{"time after", []cmp{{">", when, tm.Add(-time.Hour)}}, true},
+		{"time before", []cmp{{"<", when, tm.Add(-time.Hour)}}, false},
+		{"date le", []cmp{{"<=", day, date}}, true},
+		{"date lt", []cmp{{"<", day, date}}, false},
+		{"time vs date", []cmp{{"<=", when, date}}, false},
+		{"string", []cmp{{">", name, "A"}}, false},
+		{"int vs string", []cmp{{">", age, "1"}}, false},
+		{"absent property", []cmp{{">", gosln.MustNewPropName("height"), 0}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmc := gosln.NewPropMatchClause(0, 0, 0)
+			for _, c := range tc.conds {
+				var pm gosln.PropMap
+				switch c.op {
+				case ">":
+					pm = pmc.GreaterThan()
+				case ">=":
+					pm = pmc.GreaterEqual()
+				case "<":
+					pm = pmc.LessThan()
+				case "<=":
+					pm = pmc.LessEqual()
+				}
+				pm.Set(c.name, c.value)
+			}
+			if got := pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropMatchClause_RangeAndAbsent(t *testing.T) {
+	age := gosln.MustNewPropName("age")
+	pmc := gosln.NewPropMatchClause(0, 0, 0)
+	pmc.GreaterEqual().Set(age, 18)
+	pmc.LessThan().Set(age, 30)
+	if pmc.GreaterEqual().Len() != 1 || pmc.LessThan().Len() != 1 {
+		t.Fatal("range conditions on the same property are not kept together")
+	}
+	pmc.Absent().Add(age)
+	if n := pmc.GreaterEqual().Len() + pmc.LessThan().Len(); n != 0 {
+		t.Errorf("got %d range conditions after adding to Absent; want 0", n)
+	}
+	pmc.GreaterThan().Set(age, 0)
+	if pmc.Absent().Len() != 0 {
+		t.Error("property is still in Absent after adding to GreaterThan")
+	}
+}
+
+func TestPropMatchClause_Match_String(t *testing.T) {
+	name, data := gosln.MustNewPropName("name"), gosln.MustNewPropName("data")
+	age := gosln.MustNewPropName("age")
+	props := gosln.NewPropMap(3)
+	props.Set(name, "Alice Smith")
+	props.Set(data, []byte("abc123"))
+	props.Set(age, 20)
+
+	testCases := []struct {
+		name  string
+		setup func(pmc gosln.PropMatchClause)
+		want  bool
+	}{
+		{"prefix", func(pmc gosln.PropMatchClause) {
+			pmc.HasPrefix().Set(name, "Alice")
+		}, true},
+		{"prefix mismatch", func(pmc gosln.PropMatchClause) {
+			pmc.HasPrefix().Set(name, "Smith")
+		}, false},
+		{"prefix on bytes", func(pmc gosln.PropMatchClause) {
+			pmc.HasPrefix().Set(data, "abc")
+		}, true},
+		{"contains", func(pmc gosln.PropMatchClause) {
+			pmc.Contains().Set(name, "ce Sm")
+		}, true},
+		{"contains mismatch", func(pmc gosln.PropMatchClause) {
+			pmc.Contains().Set(name, "Bob")
+		}, false},
+		{"regexp", func(pmc gosln.PropMatchClause) {
+			pmc.Matches().Set(data, regexp.MustCompile(`^[a-z]+\d{3}$`))
+		}, true},
+		{"regexp mismatch", func(pmc gosln.PropMatchClause) {
+			pmc.Matches().Set(name, regexp.MustCompile(`^\d+$`))
+		}, false},
+		{"equal fold", func(pmc gosln.PropMatchClause) {
+			pmc.EqualFold().Set(name, "ALICE smith")
+		}, true},
+		{"equal fold on bytes", func(pmc gosln.PropMatchClause) {
+			pmc.EqualFold().Set(data, "ABC123")
+		}, true},
+		{"equal fold mismatch", func(pmc gosln.PropMatchClause) {
+			pmc.EqualFold().Set(name, "alice")
+		}, false},
+		{"equal fold on non-string property", func(pmc gosln.PropMatchClause) {
+			pmc.EqualFold().Set(age, "20")
+		}, false},
+		{"prefix and regexp", func(pmc gosln.PropMatchClause) {
+			pmc.HasPrefix().Set(name, "Alice")
+			pmc.Matches().Set(name, regexp.MustCompile(`Smith$`))
+		}, true},
+		{"non-string property", func(pmc gosln.PropMatchClause) {
+			pmc.HasPrefix().Set(age, "2")
+		}, false},
+		{"absent property", func(pmc gosln.PropMatchClause) {
+			pmc.Contains().Set(gosln.MustNewPropName("email"), "")
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmc := gosln.NewPropMatchClause(0, 0, 0)
+			tc.setup(pmc)
+			if got := pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropMatchClause_NotEqual(t *testing.T) {
+	name, data := gosln.MustNewPropName("name"), gosln.MustNewPropName("data")
+	props := gosln.NewPropMap(2)
+	props.Set(name, "Alice")
+	props.Set(data, []byte("abc"))
+
+	testCases := []struct {
+		name  string
+		pName gosln.PropName
+		value any
+		want  bool
+	}{
+		{"different", name, "Bob", true},
+		{"same", name, "Alice", false},
+		{"different type", name, []byte("Alice"), true},
+		{"same bytes", data, []byte("abc"), false},
+		{"different bytes", data, []byte("abd"), true},
+		{"absent property", gosln.MustNewPropName("email"), "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmc := gosln.NewPropMatchClause(0, 0, 0)
+			pmc.NotEqual().Set(tc.pName, tc.value)
+			if got := pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("mutual exclusion", func(t *testing.T) {
+		pmc := gosln.NewPropMatchClause(0, 0, 0)
+		pmc.Equal().Set(name, "Alice")
+		pmc.Present().Add(name)
+		pmc.NotEqual().Set(name, "Bob")
+		if pmc.Equal().Len() != 0 {
+			t.Error("property is still in Equal after adding to NotEqual")
+		}
+		if !pmc.Present().ContainsItem(name) {
+			t.Error("property is removed from Present after adding to NotEqual")
+		}
+		pmc.Equal().Set(name, "Alice")
+		if pmc.NotEqual().Len() != 0 {
+			t.Error("property is still in NotEqual after adding to Equal")
+		}
+		pmc.NotEqual().Set(name, "Bob")
+		pmc.Absent().Add(name)
+		if pmc.NotEqual().Len() != 0 {
+			t.Error("property is still in NotEqual after adding to Absent")
+		}
+		pmc.NotEqual().Set(name, "Bob")
+		if pmc.Absent().Len() != 0 {
+			t.Error("property is still in Absent after adding to NotEqual")
+		}
+	})
+}
+
+func TestPropMatchClause_In(t *testing.T) {
+	status, data := gosln.MustNewPropName("status"), gosln.MustNewPropName("data")
+	props := gosln.NewPropMap(2)
+	props.Set(status, "active")
+	props.Set(data, []byte("abc"))
+
+	testCases := []struct {
+		name   string
+		pName  gosln.PropName
+		values []any
+		want   bool
+	}{
+		{"one of", status, []any{"pending", "active"}, true},
+		{"none of", status, []any{"pending", "closed"}, false},
+		{"empty list", status, []any{}, false},
+		{"bytes", data, []any{[]byte("xyz"), []byte("abc")}, true},
+		{"absent property", gosln.MustNewPropName("email"), []any{""}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmc := gosln.NewPropMatchClause(0, 0, 0)
+			pmc.In().Set(tc.pName, tc.values)
+			if got := pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		pmc := gosln.NewPropMatchClause(0, 0, 0)
+		defer func() {
+			e := recover()
+			err, ok := e.(error)
+			var target *gosln.InvalidPropValueError
+			if !ok || !errors.As(err, &target) {
+				t.Errorf("got panic %v; want *InvalidPropValueError", e)
+			}
+		}()
+		pmc.In().Set(status, []any{"active", struct{}{}})
+	})
+}
+
+func TestPropMatchClause_Clone(t *testing.T) {
+	name, age := gosln.MustNewPropName("name"), gosln.MustNewPropName("age")
+	data, email := gosln.MustNewPropName("data"), gosln.MustNewPropName("email")
+	pmc := gosln.NewPropMatchClause(2, 0, 1)
+	pmc.Equal().Set(data, []byte("abc"))
+	pmc.Present().Add(name)
+	pmc.Absent().Add(email)
+	pmc.GreaterEqual().Set(age, 18)
+	pmc.In().Set(name, []any{"Alice", "Bob"})
+	pmc.Matches().Set(name, regexp.MustCompile(`^[A-Z]`))
+
+	c := pmc.Clone()
+	props := gosln.NewPropMap(3)
+	props.Set(name, "Alice")
+	props.Set(age, 20)
+	props.Set(data, []byte("abc"))
+	if !c.Match(props) {
+		t.Fatal("clone does not match the properties matched by the original")
+	}
+
+	v, _ := c.Equal().Get(data)
+	v.([]byte)[0] = 'A'
+	if v, _ = pmc.Equal().Get(data); string(v.([]byte)) != "abc" {
+		t.Errorf("original bytes modified through the clone, got %q", v)
+	}
+	c.Equal().Set(email, "a@b.c")
+	if !pmc.Absent().ContainsItem(email) || pmc.Equal().Len() != 1 {
+		t.Error("original modified by the mutual exclusion of the clone")
+	}
+	if c.Absent().ContainsItem(email) {
+		t.Error("mutual exclusion broken in the clone")
+	}
+	c.Absent().Add(age)
+	if pmc.GreaterEqual().Len() != 1 || c.GreaterEqual().Len() != 0 {
+		t.Error("mutual exclusion of the clone is not independent of the original")
+	}
+
+	cond := gosln.PropMatchCond{pmc, nil}
+	cc := cond.Clone()
+	if len(cc) != 2 || cc[0] == nil || cc[0] == pmc || cc[1] != nil {
+		t.Errorf("got cloned cond %v; want a copy of the clause and a nil", cc)
+	}
+	if cc = gosln.PropMatchCond(nil).Clone(); cc != nil {
+		t.Errorf("got %v; want nil", cc)
+	}
+}
+
+func TestPropMatchClause_PropType(t *testing.T) {
+	birthday, age := gosln.MustNewPropName("birthday"), gosln.MustNewPropName("age")
+	props := gosln.NewPropMap(2)
+	props.Set(birthday, gosln.DateOfYearMonthDay(2000, time.January, 1))
+	props.Set(age, int64(23))
+
+	testCases := []struct {
+		name  string
+		pName gosln.PropName
+		pt    gosln.PropType
+		want  bool
+	}{
+		{"date", birthday, gosln.PTDate, true},
+		{"time", birthday, gosln.PTTime, false},
+		{"int64", age, gosln.PTInt64, true},
+		{"int", age, gosln.PTInt, false},
+		{"absent property", gosln.MustNewPropName("email"), gosln.PTString, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pmc := gosln.NewPropMatchClause(0, 0, 0)
+			pmc.PropType().Set(tc.pName, tc.pt)
+			if got := pmc.Match(props); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+			pmc.Absent().Add(tc.pName)
+			if pmc.PropType().Len() != 0 {
+				t.Error("property is still in PropType after adding to Absent")
+			}
+		})
+	}
+}
+
+func TestNodeMatchCond_Match_Deleted(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	node := &gosln.Node{NL: gosln.NL{
+		ID:   gosln.NewID(person, gosln.NowDate(), 0),
+		Type: person,
+	}}
+	deleted := &gosln.Node{NL: node.NL, Deleted: true}
+	exclude := gosln.NewNodeMatchClause()
+	exclude.SetType(person)
+	include := gosln.NewNodeMatchClause()
+	include.SetType(person)
+	include.SetIncludeDeleted(true)
+
+	testCases := []struct {
+		cond        gosln.NodeMatchCond
+		wantNode    bool
+		wantDeleted bool
+	}{
+		{nil, true, false},
+		{gosln.NodeMatchCond{}, false, false},
+		{gosln.NodeMatchCond{exclude}, true, false},
+		{gosln.NodeMatchCond{include}, true, true},
+		{gosln.NodeMatchCond{exclude, include}, true, true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("case %d", i), func(t *testing.T) {
+			if got := tc.cond.Match(node); got != tc.wantNode {
+				t.Errorf("node - got %t; want %t", got, tc.wantNode)
+			}
+			if got := tc.cond.Match(deleted); got != tc.wantDeleted {
+				t.Errorf("deleted node - got %t; want %t", got, tc.wantDeleted)
+			}
+		})
+	}
+
+	include.SetIDAndClearOtherConds(node.ID)
+	if include.GetIncludeDeleted() {
+		t.Error("got IncludeDeleted true after SetIDAndClearOtherConds; want false")
+	}
+}
+
+func TestNodeMatchCond_CanonicalKey(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	name := gosln.MustNewPropName("name")
+	age := gosln.MustNewPropName("age")
+	email := gosln.MustNewPropName("email")
+	phone := gosln.MustNewPropName("phone")
+	birth := gosln.MustNewPropName("birth")
+	score := gosln.MustNewPropName("score")
+
+	// newClause creates a NodeMatchClause with properties
+	// set in the order specified by reversed.
+	newClause := func(ageValue int, reversed bool) gosln.NodeMatchClause {
+		pmc := gosln.NewPropMatchClause(2, 0, 2)
+		if reversed {
+			pmc.Absent().Add(phone, email)
+			pmc.Equal().Set(age, ageValue)
+			pmc.Equal().Set(name, "Alice")
+		} else {
+			pmc.Equal().Set(name, "Alice")
+			pmc.Equal().Set(age, ageValue)
+			pmc.Absent().Add(email, phone)
+		}
+		nmc := gosln.NewNodeMatchClause()
+		nmc.SetType(person)
+		nmc.SetPropMatchClause(pmc)
+		return nmc
+	}
+	typeOnly := gosln.NewNodeMatchClause()
+	typeOnly.SetType(person)
+	typeWithEmptyPMC := gosln.NewNodeMatchClause()
+	typeWithEmptyPMC.SetType(person)
+	typeWithEmptyPMC.SetPropMatchClause(gosln.NewPropMatchClause(0, 0, 0))
+	typeIncludeDeleted := gosln.NewNodeMatchClause()
+	typeIncludeDeleted.SetType(person)
+	typeIncludeDeleted.SetIncludeDeleted(true)
+	// withPMC creates a NodeMatchClause with a PropMatchClause set up by f.
+	withPMC := func(f func(pmc gosln.PropMatchClause)) gosln.NodeMatchClause {
+		pmc := gosln.NewPropMatchClause(0, 0, 0)
+		f(pmc)
+		nmc := gosln.NewNodeMatchClause()
+		nmc.SetPropMatchClause(pmc)
+		return nmc
+	}
+
+	testCases := []struct {
+		name      string
+		a, b      gosln.NodeMatchCond
+		wantEqual bool
+	}{
+		{"property order", gosln.NodeMatchCond{newClause(30, false)}, gosln.NodeMatchCond{newClause(30, true)}, true},
+		{"clause order", gosln.NodeMatchCond{newClause(30, false), typeOnly}, gosln.NodeMatchCond{typeOnly, newClause(30, true)}, true},
+		{"duplicate and nil clauses", gosln.NodeMatchCond{typeOnly, nil, typeOnly}, gosln.NodeMatchCond{typeOnly}, true},
+		{"empty PropMatchClause", gosln.NodeMatchCond{typeOnly}, gosln.NodeMatchCond{typeWithEmptyPMC}, true},
+		{"different value", gosln.NodeMatchCond{newClause(30, false)}, gosln.NodeMatchCond{newClause(31, false)}, false},
+		{"include deleted", gosln.NodeMatchCond{typeOnly}, gosln.NodeMatchCond{typeIncludeDeleted}, false},
+		{"nil and empty", nil, gosln.NodeMatchCond{}, false},
+		{
+			"range order",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.GreaterEqual().Set(age, 18)
+				pmc.LessThan().Set(age, 30)
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.LessThan().Set(age, 30)
+				pmc.GreaterEqual().Set(age, 18)
+			})},
+			true,
+		},
+		{
+			"in order",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.In().Set(name, []any{"Alice", "Bob", "Alice"})
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.In().Set(name, []any{"Bob", "Alice"})
+			})},
+			true,
+		},
+		{
+			"time zone",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(birth, time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC))
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(birth, time.Date(2023, 1, 2, 13, 0, 0, 0, time.FixedZone("UTC+1", 3600)))
+			})},
+			true,
+		},
+		{
+			"different instant",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(birth, time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC))
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(birth, time.Date(2023, 1, 2, 12, 0, 0, 0, time.FixedZone("UTC+1", 3600)))
+			})},
+			false,
+		},
+		{
+			"negative zero",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(score, 0.0)
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(score, math.Copysign(0, -1))
+			})},
+			true,
+		},
+		{
+			"negative zero in slice",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(score, []float64{1, 0})
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.Equal().Set(score, []float64{1, math.Copysign(0, -1)})
+			})},
+			true,
+		},
+		{
+			"greater than and greater equal",
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.GreaterThan().Set(age, 18)
+			})},
+			gosln.NodeMatchCond{withPMC(func(pmc gosln.PropMatchClause) {
+				pmc.GreaterEqual().Set(age, 18)
+			})},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keyA, err := tc.a.CanonicalKey()
+			if err != nil {
+				t.Fatal("a -", err)
+			}
+			keyB, err := tc.b.CanonicalKey()
+			if err != nil {
+				t.Fatal("b -", err)
+			}
+			if (keyA == keyB) != tc.wantEqual {
+				t.Errorf("got keys %q and %q; want equal %t", keyA, keyB, tc.wantEqual)
+			}
+		})
+	}
+}
+
+func TestLinkMatchCond_CanonicalKey(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	since := gosln.MustNewPropName("since")
+
+	newClause := func(sinceValue any, fromPerson bool) gosln.LinkMatchClause {
+		pmc := gosln.NewPropMatchClause(1, 0, 0)
+		pmc.Equal().Set(since, sinceValue)
+		lmc := gosln.NewLinkMatchClause()
+		lmc.SetType(knows)
+		lmc.SetPropMatchClause(pmc)
+		nmc := gosln.NewNodeMatchClause()
+		nmc.SetType(person)
+		if fromPerson {
+			lmc.SetFromNodeMatchClause(nmc)
+		} else {
+			lmc.SetToNodeMatchClause(nmc)
+		}
+		return lmc
+	}
+
+	testCases := []struct {
+		name      string
+		a, b      gosln.LinkMatchCond
+		wantEqual bool
+	}{
+		{"same", gosln.LinkMatchCond{newClause(2020, true)}, gosln.LinkMatchCond{newClause(2020, true)}, true},
+		{"different value type", gosln.LinkMatchCond{newClause(2020, true)}, gosln.LinkMatchCond{newClause(int64(2020), true)}, false},
+		{"different end", gosln.LinkMatchCond{newClause(2020, true)}, gosln.LinkMatchCond{newClause(2020, false)}, false},
+		{"nil and empty", nil, gosln.LinkMatchCond{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keyA, err := tc.a.CanonicalKey()
+			if err != nil {
+				t.Fatal("a -", err)
+			}
+			keyB, err := tc.b.CanonicalKey()
+			if err != nil {
+				t.Fatal("b -", err)
+			}
+			if (keyA == keyB) != tc.wantEqual {
+				t.Errorf("got keys %q and %q; want equal %t", keyA, keyB, tc.wantEqual)
+			}
+		})
+	}
+}
@@ -0,0 +1,757 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln
+
+import (
+	"context"
+
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/notify"
+)
+
+// memSLN is an in-memory implementation of interface gosln.SLN.
+type memSLN struct {
+	*store
+
+	// serials record the last serial used in the IDs of each type.
+	//
+	// The nodes and links share the serials,
+	// so their IDs never collide even if they are of the same type.
+	serials map[gosln.Type]int64
+
+	// linkConstraints record the constraints on the endpoint types
+	// of each link type, registered by the method RegisterLinkConstraint.
+	//
+	// The constraints are replaced rather than modified on registration,
+	// so they can be shared between an SLN and its transactions.
+	linkConstraints map[gosln.Type]*linkConstraint
+
+	// notifier dispatches the events to the observers.
+	//
+	// It is nil for the working copy of a transaction,
+	// whose events are recorded in txEvents and
+	// pushed to the notifier of the SLN on commit.
+	notifier *notify.Notifier
+	txEvents []notify.Event
+}
+
+var _ gosln.SLN = (*memSLN)(nil)
+
+// linkConstraint is the constraint on the endpoint types of a link type.
+//
+// A nil set allows the nodes of any type.
+type linkConstraint struct {
+	from gosln.TypeSet // The types of the nodes from which the links can start.
+	to   gosln.TypeSet // The types of the nodes to which the links can point.
+}
+
+// allows reports whether a link satisfying c can start from
+// a node of type from and point to a node of type to.
+//
+// If c is nil, it returns true.
+func (c *linkConstraint) allows(from, to gosln.Type) bool {
+	return c == nil ||
+		(c.from == nil || c.from.ContainsItem(from)) &&
+			(c.to == nil || c.to.ContainsItem(to))
+}
+
+// New creates a new empty in-memory SLN.
+//
+// It assigns the ID of a new node or link by function gosln.NewID
+// with the current date and a monotonic serial
+// starting from 1 for each type.
+//
+// The returned SLN follows the documentation of gosln.SLN,
+// with the following details:
+//   - The nodes and links returned by GetAllNodes and GetAllLinks
+//     are sorted in ascending order of their IDs,
+//     as are the neighbors in a Neighborhood and a GroupedNode.
+//   - A property is converted to the type specified in the property types
+//     only if the conversion is between numeric types, between byte strings,
+//     between time.Time and gosln.Date, or between slices of numbers,
+//     or the property is an empty slice.
+//   - The From and To nodes of the links returned by
+//     GetLinkByID and GetAllLinks carry no properties.
+//   - The links whose other end has been soft-removed
+//     are excluded from the results of GetNeighborhood and
+//     GetNodeWithGroupedNeighbors.
+//   - The methods NumNodeType, NumLinkType, GetNodeTypes, and GetLinkTypes
+//     take the soft-removed nodes into account.
+//   - The methods NumNode, ExistsAtLeast, and NumLink take O(1) time
+//     if the conditions specify nothing other than the types.
+//   - The methods SetNodeProperties and MutateNodeProperties report
+//     a *gosln.NodeNotExistError if the node has been soft-removed.
+//   - The observers are notified on a dedicated goroutine.
+//     Close waits for the pending notifications to be delivered.
+//   - The context is checked only before each operation starts
+//     (and before each retry of UpdateNode).
+//     An operation in progress is not interrupted.
+//   - Snapshot copies the records of all nodes and links,
+//     costing time and memory proportional to the size of the network,
+//     but shares the property maps with this SLN
+//     as they are replaced rather than modified on update.
+func New() gosln.SLN {
+	s := &memSLN{
+		store:           newStore(),
+		serials:         make(map[gosln.Type]int64),
+		linkConstraints: make(map[gosln.Type]*linkConstraint),
+		notifier:        notify.New(),
+	}
+	s.owner = s
+	return s
+}
+
+func (s *memSLN) Close() error {
+	err := s.store.Close()
+	s.mu.Lock()
+	s.serials = nil
+	s.linkConstraints = nil
+	s.mu.Unlock()
+	s.notifier.Close()
+	return err
+}
+
+func (s *memSLN) RegisterObserver(obs gosln.Observer) (unregister func()) {
+	return s.notifier.Register(obs)
+}
+
+func (s *memSLN) Snapshot(ctx context.Context) (snap gosln.ReadOnlySLN, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	return &snapshot{store: s.clone(nil)}, nil
+}
+
+func (s *memSLN) RegisterLinkConstraint(
+	ctx context.Context,
+	linkType gosln.Type,
+	fromTypes, toTypes gosln.TypeSet,
+) error {
+	if !linkType.IsValid() {
+		return errors.AutoWrap(gosln.NewInvalidTypeError(linkType.String()))
+	}
+	c := &linkConstraint{
+		from: copyTypeSet(fromTypes),
+		to:   copyTypeSet(toTypes),
+	}
+	err := s.lock(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	if c.from == nil && c.to == nil {
+		delete(s.linkConstraints, linkType)
+	} else {
+		s.linkConstraints[linkType] = c
+	}
+	return nil
+}
+
+func (s *memSLN) CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	props = clonePropsToStore(props)
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.addNode(t, props)
+	s.notifyNode(rec, true)
+	return s.exportAllProps(rec), nil
+}
+
+func (s *memSLN) UpsertNode(ctx context.Context, t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+	node *gosln.Node, created bool, err error) {
+	if !t.IsValid() {
+		return nil, false, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	var key any
+	var present bool
+	if props != nil {
+		key, present = props.Get(keyName)
+	}
+	if !present {
+		return nil, false, errors.AutoWrap(gosln.NewInvalidPropNameError(keyName.String()))
+	}
+	props = clonePropsToStore(props)
+	nmc := gosln.NewNodeMatchClause()
+	nmc.SetType(t)
+	pmc := gosln.NewPropMatchClause(1, 0, 0)
+	pmc.Equal().Set(keyName, key)
+	nmc.SetPropMatchClause(pmc)
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, false, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	var rec *nodeRecord
+	for _, r := range s.nodes {
+		if r.t == t && nmc.Match(s.rawNode(r)) &&
+			(rec == nil || r.id.Compare(rec.id) < 0) {
+			rec = r
+		}
+	}
+	if rec == nil {
+		rec = s.addNode(t, props)
+		s.notifyNode(rec, true)
+		return s.exportAllProps(rec), true, nil
+	}
+	rec.props = gosln.MergePropMaps(rec.props, props)
+	s.notifyNode(rec, false)
+	return s.exportAllProps(rec), false, nil
+}
+
+func (s *memSLN) CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	props = clonePropsToStore(props)
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	for _, id := range []gosln.ID{from, to} {
+		if rec := s.nodes[id]; rec == nil || rec.deleted {
+			return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+		}
+	}
+	if !s.linkConstraints[t].allows(s.nodes[from].t, s.nodes[to].t) {
+		return nil, errors.AutoWrap(gosln.NewLinkConstraintError(t, from, to))
+	}
+	rec := &linkRecord{
+		id:    s.newID(t),
+		t:     t,
+		props: props,
+		from:  from,
+		to:    to,
+	}
+	s.links[rec.id] = rec
+	s.linkCounts[t]++
+	s.nodes[from].links[rec.id] = struct{}{}
+	s.nodes[to].links[rec.id] = struct{}{}
+	s.notifyLink(rec, true)
+	return s.exportLinkAllProps(rec), nil
+}
+
+func (s *memSLN) RemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	err := s.lock(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	if rec := s.nodes[id]; rec != nil {
+		s.removeNode(rec)
+	}
+	return nil
+}
+
+func (s *memSLN) RemoveNodesByCond(ctx context.Context, cond gosln.NodeMatchCond) (removed int, err error) {
+	if cond == nil {
+		return 0, errors.AutoNew("node match condition is nil")
+	}
+	err = s.lock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	for _, rec := range s.nodes {
+		if cond.Match(s.rawNode(rec)) {
+			s.removeNode(rec)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *memSLN) SoftRemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	err := s.lock(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil
+	}
+	rec.deleted = true
+	s.deletedCounts[rec.t]++
+	s.numDeleted++
+	if s.observed() {
+		s.emit(func(obs gosln.Observer) {
+			obs.OnNodeSoftRemoved(id)
+		})
+	}
+	return nil
+}
+
+func (s *memSLN) RestoreNodeByID(ctx context.Context, id gosln.ID) error {
+	err := s.lock(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.nodes[id]
+	if rec == nil {
+		return errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	if rec.deleted {
+		rec.deleted = false
+		decreaseCount(s.deletedCounts, rec.t)
+		s.numDeleted--
+		if s.observed() {
+			s.emit(func(obs gosln.Observer) {
+				obs.OnNodeRestored(id)
+			})
+		}
+	}
+	return nil
+}
+
+func (s *memSLN) RemoveLinkByID(ctx context.Context, id gosln.ID) error {
+	err := s.lock(ctx)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	if rec := s.links[id]; rec != nil {
+		s.removeLink(rec)
+	}
+	return nil
+}
+
+func (s *memSLN) RemoveLinksByCond(ctx context.Context, cond gosln.LinkMatchCond) (removed int, err error) {
+	if cond == nil {
+		return 0, errors.AutoNew("link match condition is nil")
+	}
+	err = s.lock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	for _, rec := range s.links {
+		if cond.Match(s.rawLink(rec)) {
+			s.removeLink(rec)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *memSLN) RenameType(ctx context.Context, oldType, newType gosln.Type) (
+	idMap map[gosln.ID]gosln.ID, err error) {
+	for _, t := range []gosln.Type{oldType, newType} {
+		if !t.IsValid() {
+			return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+		}
+	}
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	numNode, numLink := s.nodeCounts[oldType], s.linkCounts[oldType]
+	idMap = make(map[gosln.ID]gosln.ID, numNode+numLink)
+	if numNode == 0 && numLink == 0 {
+		return idMap, nil
+	}
+	if s.nodeCounts[newType] > 0 || s.linkCounts[newType] > 0 {
+		return nil, errors.AutoNew("type " + newType.String() + " is already used")
+	}
+
+	nodeIDs := make([]gosln.ID, 0, numNode)
+	for id, rec := range s.nodes {
+		if rec.t == oldType {
+			nodeIDs = append(nodeIDs, id)
+		}
+	}
+	gosln.SortIDs(nodeIDs)
+	for _, oldID := range nodeIDs {
+		rec := s.nodes[oldID]
+		delete(s.nodes, oldID)
+		rec.id, rec.t = s.newID(newType), newType
+		s.nodes[rec.id] = rec
+		idMap[oldID] = rec.id
+		for linkID := range rec.links {
+			l := s.links[linkID]
+			if l.from == oldID {
+				l.from = rec.id
+			}
+			if l.to == oldID {
+				l.to = rec.id
+			}
+		}
+	}
+
+	linkIDs := make([]gosln.ID, 0, numLink)
+	for id, rec := range s.links {
+		if rec.t == oldType {
+			linkIDs = append(linkIDs, id)
+		}
+	}
+	gosln.SortIDs(linkIDs)
+	for _, oldID := range linkIDs {
+		rec := s.links[oldID]
+		delete(s.links, oldID)
+		rec.id, rec.t = s.newID(newType), newType
+		s.links[rec.id] = rec
+		idMap[oldID] = rec.id
+		for _, nodeID := range []gosln.ID{rec.from, rec.to} {
+			links := s.nodes[nodeID].links
+			delete(links, oldID)
+			links[rec.id] = struct{}{}
+		}
+	}
+
+	moveCount(s.nodeCounts, oldType, newType)
+	moveCount(s.deletedCounts, oldType, newType)
+	moveCount(s.linkCounts, oldType, newType)
+	if s.observed() {
+		m := make(map[gosln.ID]gosln.ID, len(idMap))
+		for k, v := range idMap {
+			m[k] = v
+		}
+		s.emit(func(obs gosln.Observer) {
+			obs.OnTypeRenamed(oldType, newType, m)
+		})
+	}
+	return idMap, nil
+}
+
+func (s *memSLN) SetNodeProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	props = clonePropsToStore(props)
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	rec.props = props
+	s.notifyNode(rec, false)
+	return s.exportAllProps(rec), nil
+}
+
+func (s *memSLN) SetLinkProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	props = clonePropsToStore(props)
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.links[id]
+	if rec == nil {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	rec.props = props
+	s.notifyLink(rec, false)
+	return s.exportLinkAllProps(rec), nil
+}
+
+func (s *memSLN) MutateNodeProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	node *gosln.Node, err error) {
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	rec.props = gosln.ApplyMutate(rec.props, pma)
+	s.notifyNode(rec, false)
+	return s.exportAllProps(rec), nil
+}
+
+// UpdateNode calls fn without holding the lock of the SLN,
+// and then applies the mutation only if the properties on the node
+// have not been replaced in the meantime.
+// Otherwise, it calls fn again with the latest properties.
+func (s *memSLN) UpdateNode(
+	ctx context.Context,
+	id gosln.ID,
+	propTypes gosln.PropTypeMap,
+	fn func(current gosln.PropMap) (gosln.PropMutateArg, error),
+) (node *gosln.Node, err error) {
+	if fn == nil {
+		return nil, errors.AutoNew("update function is nil")
+	}
+	for {
+		var props gosln.PropMap
+		node, props, err = s.getNodeForUpdate(ctx, id, propTypes)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		var pma gosln.PropMutateArg
+		pma, err = fn(node.Props)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		} else if pma == nil || pma.IsEmpty() {
+			return node, nil
+		}
+		err = s.lock(ctx)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		rec := s.nodes[id]
+		if rec == nil || rec.deleted {
+			s.mu.Unlock()
+			return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+		} else if rec.props != props {
+			s.mu.Unlock()
+			continue // the node has been updated since fetched
+		}
+		rec.props = gosln.ApplyMutate(rec.props, pma)
+		s.notifyNode(rec, false)
+		node, err = s.exportNode(rec, propTypes)
+		s.mu.Unlock()
+		return node, errors.AutoWrap(err)
+	}
+}
+
+// getNodeForUpdate returns the node with the specified ID
+// with the properties converted according to propTypes,
+// together with the property map in the record of the node
+// to detect whether the node is updated afterward.
+//
+// It reports a *gosln.NodeNotExistError if the node does not exist
+// or has been soft-removed.
+func (s *memSLN) getNodeForUpdate(ctx context.Context, id gosln.ID, propTypes gosln.PropTypeMap) (
+	node *gosln.Node, props gosln.PropMap, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer s.mu.RUnlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	node, err = s.exportNode(rec, propTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return node, rec.props, nil
+}
+
+func (s *memSLN) MutateLinkProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	link *gosln.Link, err error) {
+	err = s.lock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.Unlock()
+	rec := s.links[id]
+	if rec == nil {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	rec.props = gosln.ApplyMutate(rec.props, pma)
+	s.notifyLink(rec, false)
+	return s.exportLinkAllProps(rec), nil
+}
+
+// newID returns a new ID of type t.
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) newID(t gosln.Type) gosln.ID {
+	s.serials[t]++
+	return gosln.NewID(t, gosln.NowDate(), s.serials[t])
+}
+
+// addNode adds a new node of type t with the specified properties
+// and returns its record.
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) addNode(t gosln.Type, props gosln.PropMap) *nodeRecord {
+	rec := &nodeRecord{
+		id:    s.newID(t),
+		t:     t,
+		props: props,
+		links: make(map[gosln.ID]struct{}),
+	}
+	s.nodes[rec.id] = rec
+	s.nodeCounts[t]++
+	return rec
+}
+
+// removeNode removes the node rec and all links associated with it.
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) removeNode(rec *nodeRecord) {
+	for linkID := range rec.links {
+		s.removeLink(s.links[linkID])
+	}
+	delete(s.nodes, rec.id)
+	decreaseCount(s.nodeCounts, rec.t)
+	if rec.deleted {
+		decreaseCount(s.deletedCounts, rec.t)
+		s.numDeleted--
+	}
+	if s.observed() {
+		id := rec.id
+		s.emit(func(obs gosln.Observer) {
+			obs.OnNodeRemoved(id)
+		})
+	}
+}
+
+// removeLink removes the link rec and updates the nodes it connects.
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) removeLink(rec *linkRecord) {
+	delete(s.nodes[rec.from].links, rec.id)
+	delete(s.nodes[rec.to].links, rec.id)
+	delete(s.links, rec.id)
+	decreaseCount(s.linkCounts, rec.t)
+	if s.observed() {
+		id := rec.id
+		s.emit(func(obs gosln.Observer) {
+			obs.OnLinkRemoved(id)
+		})
+	}
+}
+
+// observed reports whether the events should be emitted,
+// that is, any observer is registered or s is the working copy
+// of a transaction.
+func (s *memSLN) observed() bool {
+	return s.notifier == nil || s.notifier.Observed()
+}
+
+// emit pushes ev to the notifier of s,
+// or records it if s is the working copy of a transaction.
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) emit(ev notify.Event) {
+	if s.notifier == nil {
+		s.txEvents = append(s.txEvents, ev)
+	} else {
+		s.notifier.Push(ev)
+	}
+}
+
+// notifyNode emits an event that the node rec is created
+// (if created is true) or updated (if created is false).
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) notifyNode(rec *nodeRecord, created bool) {
+	if !s.observed() {
+		return
+	}
+	node := s.exportAllProps(rec)
+	if created {
+		s.emit(func(obs gosln.Observer) {
+			obs.OnNodeCreated(node)
+		})
+	} else {
+		s.emit(func(obs gosln.Observer) {
+			obs.OnNodeUpdated(node)
+		})
+	}
+}
+
+// notifyLink emits an event that the link rec is created
+// (if created is true) or updated (if created is false).
+//
+// The caller should hold the write lock of s.
+func (s *memSLN) notifyLink(rec *linkRecord, created bool) {
+	if !s.observed() {
+		return
+	}
+	link := s.exportLinkAllProps(rec)
+	if created {
+		s.emit(func(obs gosln.Observer) {
+			obs.OnLinkCreated(link)
+		})
+	} else {
+		s.emit(func(obs gosln.Observer) {
+			obs.OnLinkUpdated(link)
+		})
+	}
+}
+
+// exportAllProps returns a node corresponding to rec
+// with a copy of all properties on it.
+func (s *memSLN) exportAllProps(rec *nodeRecord) *gosln.Node {
+	node := s.rawNode(rec)
+	node.Props = gosln.ClonePropMap(rec.props)
+	return node
+}
+
+// exportLinkAllProps returns a link corresponding to rec
+// with a copy of all properties on it.
+//
+// The caller should hold the lock of s.
+func (s *memSLN) exportLinkAllProps(rec *linkRecord) *gosln.Link {
+	// exportLink never fails without property types.
+	link, _ := s.exportLink(rec, nil, nil, nil)
+	link.Props = gosln.ClonePropMap(rec.props)
+	return link
+}
+
+// clonePropsToStore returns a copy of props to be stored in a record.
+//
+// If props is nil, it returns an empty PropMap.
+func clonePropsToStore(props gosln.PropMap) gosln.PropMap {
+	if props == nil {
+		return gosln.NewPropMap(0)
+	}
+	return gosln.ClonePropMap(props)
+}
+
+// decreaseCount decreases the counter of type t in counts by 1,
+// and removes t from counts if the counter becomes 0.
+func decreaseCount(counts map[gosln.Type]int, t gosln.Type) {
+	if counts[t] <= 1 {
+		delete(counts, t)
+	} else {
+		counts[t]--
+	}
+}
+
+// moveCount moves the counter of type oldType in counts to type newType.
+func moveCount(counts map[gosln.Type]int, oldType, newType gosln.Type) {
+	if n := counts[oldType]; n > 0 {
+		delete(counts, oldType)
+		counts[newType] = n
+	}
+}
+
+// copyTypeSet returns a copy of ts.
+//
+// If ts is nil or empty, it returns nil.
+func copyTypeSet(ts gosln.TypeSet) gosln.TypeSet {
+	if ts == nil || ts.Len() == 0 {
+		return nil
+	}
+	return gosln.FilteredTypeSet(ts, nil)
+}
@@ -0,0 +1,1063 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/memsln"
+	"github.com/donyori/gosln/slntest"
+)
+
+var (
+	person  = gosln.MustNewType("Person")
+	city    = gosln.MustNewType("City")
+	knows   = gosln.MustNewType("Knows")
+	livesIn = gosln.MustNewType("LivesIn")
+
+	nameProp = gosln.MustNewPropName("name")
+	ageProp  = gosln.MustNewPropName("age")
+)
+
+// newTestSLN creates an in-memory SLN with three persons (Alice, Bob, Carol)
+// and a city (Paris), where Alice knows Bob and Carol,
+// Bob knows Alice, and Alice and Bob live in Paris.
+//
+// It returns the SLN and the IDs of the nodes in the above order.
+func newTestSLN(t *testing.T) (sln gosln.SLN, ids []gosln.ID) {
+	ctx := context.Background()
+	sln = memsln.New()
+	t.Cleanup(func() {
+		_ = sln.Close()
+	})
+	for _, x := range []struct {
+		t    gosln.Type
+		name string
+		age  int
+	}{
+		{person, "Alice", 30},
+		{person, "Bob", 25},
+		{person, "Carol", 0},
+		{city, "Paris", 0},
+	} {
+		props := gosln.NewPropMap(2)
+		props.Set(nameProp, x.name)
+		if x.age > 0 {
+			props.Set(ageProp, x.age)
+		}
+		node, err := sln.CreateNode(ctx, x.t, props)
+		if err != nil {
+			t.Fatal("create node -", err)
+		}
+		ids = append(ids, node.ID)
+	}
+	for _, x := range [][3]int{{0, 1, 0}, {0, 2, 0}, {1, 0, 0}, {0, 3, 1}, {1, 3, 1}} {
+		_, err := sln.CreateLink(ctx, []gosln.Type{knows, livesIn}[x[2]], ids[x[0]], ids[x[1]], nil)
+		if err != nil {
+			t.Fatal("create link -", err)
+		}
+	}
+	return
+}
+
+// nameTypes is a PropTypeMap holding the property "name" of type string.
+func nameTypes() gosln.PropTypeMap {
+	ptm := gosln.NewPropTypeMap(1)
+	ptm.Set(nameProp, gosln.PTString)
+	return ptm
+}
+
+// nodeNames returns the property "name" on the nodes.
+func nodeNames(t *testing.T, nodes []*gosln.Node) []string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		name, err := gosln.PropMapGet[string](node.Props, nameProp)
+		if err != nil {
+			t.Fatalf("get name of %v - %v", node.ID, err)
+		}
+		names[i] = name
+	}
+	return names
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNew_Conformance(t *testing.T) {
+	slntest.RunSLNConformance(t, func(t *testing.T) (gosln.SLN, func()) {
+		return memsln.New(), nil
+	})
+}
+
+func TestNew_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+
+	node, err := sln.GetNodeByID(ctx, ids[0], nameTypes())
+	if err != nil {
+		t.Fatal("get node -", err)
+	}
+	if node.SLN != sln || node.ID != ids[0] || node.Type != person || node.Deleted {
+		t.Errorf("got node %+v", node)
+	}
+	if node.Props.Len() != 1 {
+		t.Errorf("got %d properties; want 1", node.Props.Len())
+	}
+	if serial, ok := node.ID.Serial(); !ok || serial != 1 {
+		t.Errorf("got serial %d, %t; want 1, true", serial, ok)
+	}
+
+	ptm := gosln.NewPropTypeMap(1)
+	ptm.Set(ageProp, gosln.PTFloat64)
+	node, err = sln.GetNodeByID(ctx, ids[0], ptm)
+	if err != nil {
+		t.Fatal("get node with age as float64 -", err)
+	}
+	if age, err := gosln.PropMapGet[float64](node.Props, ageProp); err != nil || age != 30 {
+		t.Errorf("got age %v, %v; want 30, <nil>", age, err)
+	}
+	ptm.Set(nameProp, gosln.PTInt)
+	var pte *gosln.PropTypeError
+	if _, err = sln.GetNodeByID(ctx, ids[0], ptm); !errors.As(err, &pte) {
+		t.Errorf("get node with name as int - got %v; want *PropTypeError", err)
+	}
+
+	var nne *gosln.NodeNotExistError
+	if _, err = sln.GetNodeByID(ctx, gosln.NewID(person, gosln.NowDate(), 100), nil); !errors.As(err, &nne) {
+		t.Errorf("get nonexistent node - got %v; want *NodeNotExistError", err)
+	}
+	if _, err = sln.CreateLink(ctx, knows, ids[0], gosln.NewID(person, gosln.NowDate(), 100), nil); !errors.As(err, &nne) {
+		t.Errorf("create link to nonexistent node - got %v; want *NodeNotExistError", err)
+	}
+	var ite *gosln.InvalidTypeError
+	if _, err = sln.CreateNode(ctx, gosln.Type{}, nil); !errors.As(err, &ite) {
+		t.Errorf("create node of invalid type - got %v; want *InvalidTypeError", err)
+	}
+
+	links, err := sln.GetAllLinks(ctx, nil, nil)
+	if err != nil {
+		t.Fatal("get all links -", err)
+	}
+	if len(links) != 5 {
+		t.Fatalf("got %d links; want 5", len(links))
+	}
+	link, err := sln.GetLinkByID(ctx, links[0].ID, nil)
+	if err != nil {
+		t.Fatal("get link -", err)
+	}
+	if link.From.ID != ids[0] || link.To.ID != ids[1] || link.Type != knows {
+		t.Errorf("got link from %v to %v of type %v; want from %v to %v of type %v",
+			link.From.ID, link.To.ID, link.Type, ids[0], ids[1], knows)
+	}
+	for _, link := range links {
+		if link.ID.Type() == person || link.ID == ids[0] {
+			t.Errorf("link ID %v collides with node IDs", link.ID)
+		}
+	}
+}
+
+func TestNew_UpsertNode(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	testCases := []struct {
+		name        string
+		key         string
+		age         int
+		wantCreated bool
+		wantNumNode int
+	}{
+		{"update Bob", "Bob", 26, false, 4},
+		{"create Dave", "Dave", 40, true, 5},
+		{"update Dave", "Dave", 41, false, 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			props := gosln.NewPropMap(2)
+			props.Set(nameProp, tc.key)
+			props.Set(ageProp, tc.age)
+			node, created, err := sln.UpsertNode(ctx, person, nameProp, props)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if created != tc.wantCreated {
+				t.Errorf("got created %t; want %t", created, tc.wantCreated)
+			}
+			if !tc.wantCreated && tc.key == "Bob" && node.ID != ids[1] {
+				t.Errorf("got node %v; want %v", node.ID, ids[1])
+			}
+			if age, err := gosln.PropMapGet[int](node.Props, ageProp); err != nil || age != tc.age {
+				t.Errorf("got age %d, %v; want %d, <nil>", age, err, tc.age)
+			}
+			if n, err := sln.NumNode(ctx, nil); err != nil || n != tc.wantNumNode {
+				t.Errorf("got NumNode %d, %v; want %d, <nil>", n, err, tc.wantNumNode)
+			}
+		})
+	}
+
+	props := gosln.NewPropMap(1)
+	props.Set(ageProp, 1)
+	var ipne *gosln.InvalidPropNameError
+	if _, _, err := sln.UpsertNode(ctx, person, nameProp, props); !errors.As(err, &ipne) {
+		t.Errorf("upsert without key - got %v; want *InvalidPropNameError", err)
+	}
+}
+
+func TestNew_GetNodesByIDs(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	if err := sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+	missing := gosln.NewID(person, gosln.NowDate(), 100)
+	nodes, err := sln.GetNodesByIDs(ctx, []gosln.ID{ids[0], ids[2], missing, ids[3], ids[0], {}}, nameTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 {
+		t.Errorf("got %d nodes; want 2", len(nodes))
+	}
+	for _, id := range []gosln.ID{ids[0], ids[3]} {
+		if node := nodes[id]; node == nil || node.ID != id {
+			t.Errorf("node %v is not in the result", id)
+		}
+	}
+}
+
+func TestNew_GetAllNodes(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	if err := sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+
+	personClause := gosln.NewNodeMatchClause()
+	personClause.SetType(person)
+	includeDeleted := gosln.NewNodeMatchClause()
+	includeDeleted.SetType(person)
+	includeDeleted.SetIncludeDeleted(true)
+	ageClause := gosln.NewNodeMatchClause()
+	pmc := gosln.NewPropMatchClause(0, 1, 0)
+	pmc.Present().Add(ageProp)
+	ageClause.SetPropMatchClause(pmc)
+
+	testCases := []struct {
+		name string
+		cond gosln.NodeMatchCond
+		want []string
+	}{
+		{"nil", nil, []string{"Paris", "Alice", "Bob"}},
+		{"empty", gosln.NodeMatchCond{}, nil},
+		{"type", gosln.NodeMatchCond{personClause}, []string{"Alice", "Bob"}},
+		{"include deleted", gosln.NodeMatchCond{includeDeleted}, []string{"Alice", "Bob", "Carol"}},
+		{"props", gosln.NodeMatchCond{ageClause}, []string{"Alice", "Bob"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, err := sln.GetAllNodes(ctx, nameTypes(), tc.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := nodeNames(t, nodes); !equalStrings(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+			n, err := sln.NumNode(ctx, tc.cond)
+			if err != nil {
+				t.Fatal("num node -", err)
+			} else if n != len(tc.want) {
+				t.Errorf("got NumNode %d; want %d", n, len(tc.want))
+			}
+			ok, err := sln.ExistsAtLeast(ctx, tc.cond, 2)
+			if err != nil {
+				t.Fatal("exists at least -", err)
+			} else if ok != (len(tc.want) >= 2) {
+				t.Errorf("got ExistsAtLeast %t; want %t", ok, len(tc.want) >= 2)
+			}
+		})
+	}
+}
+
+func TestNew_GetNodesPage(t *testing.T) {
+	ctx := context.Background()
+	sln, _ := newTestSLN(t)
+	testCases := []struct {
+		name string
+		page gosln.Page
+		want []string
+	}{
+		{"no limit", gosln.Page{}, []string{"Paris", "Alice", "Bob", "Carol"}},
+		{"by age", gosln.Page{Limit: 2, SortKey: ageProp}, []string{"Bob", "Alice"}},
+		{"by name desc", gosln.Page{Offset: 1, Limit: 2, SortKey: nameProp, Descending: true}, []string{"Carol", "Bob"}},
+		{"beyond", gosln.Page{Offset: 4}, []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes, total, err := sln.GetNodesPage(ctx, nameTypes(), nil, tc.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 4 {
+				t.Errorf("got total %d; want 4", total)
+			}
+			if nodes == nil {
+				t.Error("got nil nodes")
+			}
+			if got := nodeNames(t, nodes); !equalStrings(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	links, total, err := sln.GetLinksPage(ctx, nil, nil, gosln.Page{Offset: 3, Limit: 10})
+	if err != nil {
+		t.Fatal("get links page -", err)
+	}
+	if total != 5 || len(links) != 2 {
+		t.Errorf("got %d links, total %d; want 2, 5", len(links), total)
+	}
+}
+
+func TestNew_RegisterLinkConstraint(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	persons := gosln.NewTypeSet(1)
+	persons.Add(person)
+	if err := sln.RegisterLinkConstraint(ctx, knows, persons, persons); err != nil {
+		t.Fatal("register -", err)
+	}
+	persons.Add(city) // must not affect the registered constraint
+
+	if _, err := sln.CreateLink(ctx, knows, ids[0], ids[1], nil); err != nil {
+		t.Error("create Alice -Knows-> Bob -", err)
+	}
+	_, err := sln.CreateLink(ctx, knows, ids[0], ids[3], nil)
+	var e *gosln.LinkConstraintError
+	if !errors.As(err, &e) {
+		t.Errorf("create Alice -Knows-> Paris - got %v; want *LinkConstraintError", err)
+	} else if e.LinkType() != knows || e.From() != ids[0] || e.To() != ids[3] {
+		t.Errorf("got error %q; want link type %v from %v to %v", e, knows, ids[0], ids[3])
+	}
+	// LivesIn is not constrained.
+	if _, err = sln.CreateLink(ctx, livesIn, ids[2], ids[3], nil); err != nil {
+		t.Error("create Carol -LivesIn-> Paris -", err)
+	}
+
+	tx, err := sln.BeginTx(ctx)
+	if err != nil {
+		t.Fatal("begin transaction -", err)
+	}
+	_, err = tx.CreateLink(ctx, knows, ids[3], ids[2], nil)
+	if !errors.As(err, &e) {
+		t.Errorf("create Paris -Knows-> Carol in transaction - got %v; want *LinkConstraintError", err)
+	}
+	if err = tx.Rollback(); err != nil {
+		t.Error("rollback -", err)
+	}
+
+	if err = sln.RegisterLinkConstraint(ctx, knows, nil, nil); err != nil {
+		t.Fatal("remove constraint -", err)
+	}
+	if _, err = sln.CreateLink(ctx, knows, ids[0], ids[3], nil); err != nil {
+		t.Error("create Alice -Knows-> Paris after removing constraint -", err)
+	}
+
+	var ite *gosln.InvalidTypeError
+	if err = sln.RegisterLinkConstraint(ctx, gosln.Type{}, nil, nil); !errors.As(err, &ite) {
+		t.Errorf("register invalid type - got %v; want *InvalidTypeError", err)
+	}
+}
+
+func TestNew_RemoveNodeByID(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	if err := sln.RemoveNodeByID(ctx, ids[0]); err != nil {
+		t.Fatal(err)
+	}
+	// Only the link Bob -LivesIn-> Paris remains.
+	links, err := sln.GetAllLinks(ctx, nil, nil)
+	if err != nil {
+		t.Fatal("get all links -", err)
+	}
+	if len(links) != 1 || links[0].From.ID != ids[1] || links[0].To.ID != ids[3] {
+		t.Errorf("got %d links; want only the link from Bob to Paris", len(links))
+	}
+	if n, err := sln.NumLinkType(ctx); err != nil || n != 1 {
+		t.Errorf("got NumLinkType %d, %v; want 1, <nil>", n, err)
+	}
+	if err = sln.RemoveNodeByID(ctx, ids[0]); err != nil {
+		t.Error("remove again -", err)
+	}
+}
+
+func TestNew_RemoveByCond(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	if _, err := sln.RemoveNodesByCond(ctx, nil); err == nil {
+		t.Error("remove nodes by nil condition - got nil error")
+	}
+	if _, err := sln.RemoveLinksByCond(ctx, nil); err == nil {
+		t.Error("remove links by nil condition - got nil error")
+	}
+
+	lmc := gosln.NewLinkMatchClause()
+	lmc.SetType(knows)
+	lmc.SetToNodeMatchClause(typeClause(person))
+	removed, err := sln.RemoveLinksByCond(ctx, gosln.LinkMatchCond{lmc})
+	if err != nil || removed != 3 {
+		t.Errorf("remove Knows links - got %d, %v; want 3, <nil>", removed, err)
+	}
+
+	if err = sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+	// Carol is soft-removed and not matched.
+	removed, err = sln.RemoveNodesByCond(ctx, gosln.NodeMatchCond{typeClause(person)})
+	if err != nil || removed != 2 {
+		t.Errorf("remove persons - got %d, %v; want 2, <nil>", removed, err)
+	}
+	if n, err := sln.NumLink(ctx, nil); err != nil || n != 0 {
+		t.Errorf("got NumLink %d, %v; want 0, <nil>", n, err)
+	}
+	if err = sln.RestoreNodeByID(ctx, ids[2]); err != nil {
+		t.Error("restore Carol -", err)
+	}
+	if n, err := sln.NumNode(ctx, nil); err != nil || n != 2 {
+		t.Errorf("got NumNode %d, %v; want 2, <nil>", n, err)
+	}
+}
+
+func TestNew_UpdateNode(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	increaseAge := func(current gosln.PropMap) (gosln.PropMutateArg, error) {
+		age, err := gosln.PropMapGet[int64](current, ageProp)
+		if err != nil {
+			return nil, err
+		}
+		pma := gosln.NewPropMutateArg(1, 0)
+		pma.ToBeSet().Set(ageProp, age+1)
+		return pma, nil
+	}
+	ageTypes := gosln.NewPropTypeMap(1)
+	ageTypes.Set(ageProp, gosln.PTInt64)
+
+	const NumGoroutine = 20
+	var wg sync.WaitGroup
+	wg.Add(NumGoroutine)
+	for i := 0; i < NumGoroutine; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := sln.UpdateNode(ctx, ids[0], ageTypes, increaseAge); err != nil {
+				t.Error("increase age -", err)
+			}
+		}()
+	}
+	wg.Wait()
+	node, err := sln.GetNodeByID(ctx, ids[0], ageTypes)
+	if err != nil {
+		t.Fatal("get Alice -", err)
+	} else if age, _ := node.Props.Get(ageProp); age != int64(30+NumGoroutine) {
+		t.Errorf("got age %v; want %d", age, 30+NumGoroutine)
+	}
+
+	// Carol has no age, so increaseAge fails and Carol is not modified.
+	_, err = sln.UpdateNode(ctx, ids[2], ageTypes, increaseAge)
+	var pne *gosln.PropNotExistError
+	if !errors.As(err, &pne) {
+		t.Errorf("update Carol - got %v; want *PropNotExistError", err)
+	}
+
+	node, err = sln.UpdateNode(ctx, ids[1], nameTypes(), func(gosln.PropMap) (gosln.PropMutateArg, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Error("no-op update -", err)
+	} else if name, _ := node.Props.Get(nameProp); name != "Bob" {
+		t.Errorf("no-op update - got name %v; want Bob", name)
+	}
+
+	if err = sln.SoftRemoveNodeByID(ctx, ids[1]); err != nil {
+		t.Fatal("soft remove Bob -", err)
+	}
+	var nne *gosln.NodeNotExistError
+	if _, err = sln.UpdateNode(ctx, ids[1], nil, increaseAge); !errors.As(err, &nne) {
+		t.Errorf("update soft-removed Bob - got %v; want *NodeNotExistError", err)
+	}
+	if _, err = sln.UpdateNode(ctx, ids[0], nil, nil); err == nil {
+		t.Error("nil function - got nil error")
+	}
+}
+
+func TestNew_SoftRemoveAndRestore(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	if err := sln.SoftRemoveNodeByID(ctx, ids[1]); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+	var nne *gosln.NodeNotExistError
+	if _, err := sln.GetNodeByID(ctx, ids[1], nil); !errors.As(err, &nne) {
+		t.Errorf("get soft-removed node - got %v; want *NodeNotExistError", err)
+	}
+	if _, err := sln.CreateLink(ctx, knows, ids[0], ids[1], nil); !errors.As(err, &nne) {
+		t.Errorf("create link to soft-removed node - got %v; want *NodeNotExistError", err)
+	}
+	nh, err := sln.GetNeighborhood(ctx, ids[0], gosln.NeighborhoodOptions{NeighborPropTypes: nameTypes()})
+	if err != nil {
+		t.Fatal("get neighborhood -", err)
+	}
+	if got := nodeNames(t, nh.Neighbors); !equalStrings(got, []string{"Paris", "Carol"}) {
+		t.Errorf("got neighbors %v; want [Paris Carol]", got)
+	}
+
+	if err = sln.RestoreNodeByID(ctx, ids[1]); err != nil {
+		t.Fatal("restore -", err)
+	}
+	node, err := sln.GetNodeByID(ctx, ids[1], nil)
+	if err != nil {
+		t.Fatal("get restored node -", err)
+	} else if node.Deleted {
+		t.Error("restored node is marked deleted")
+	}
+	if err = sln.RestoreNodeByID(ctx, gosln.NewID(person, gosln.NowDate(), 100)); !errors.As(err, &nne) {
+		t.Errorf("restore nonexistent node - got %v; want *NodeNotExistError", err)
+	}
+}
+
+func TestNew_RenameType(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	human := gosln.MustNewType("Human")
+	if err := sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+
+	idMap, err := sln.RenameType(ctx, person, human)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idMap) != 3 {
+		t.Fatalf("got %d IDs in mapping; want 3", len(idMap))
+	}
+	for _, id := range ids[:3] {
+		newID := idMap[id]
+		if newID.Type() != human {
+			t.Errorf("%v - got new ID %v; want of type %v", id, newID, human)
+		}
+	}
+	if n, err := sln.NumNode(ctx, gosln.NodeMatchCond{typeClause(person)}); err != nil || n != 0 {
+		t.Errorf("got %d nodes of old type, %v; want 0, <nil>", n, err)
+	}
+	if n, err := sln.NumNode(ctx, gosln.NodeMatchCond{typeClause(human)}); err != nil || n != 2 {
+		t.Errorf("got %d nodes of new type, %v; want 2, <nil>", n, err)
+	}
+	if err = sln.RestoreNodeByID(ctx, idMap[ids[2]]); err != nil {
+		t.Error("restore renamed soft-removed node -", err)
+	}
+	nh, err := sln.GetNeighborhood(ctx, idMap[ids[0]], gosln.NeighborhoodOptions{Direction: gosln.DirOutgoing})
+	if err != nil {
+		t.Fatal("get neighborhood -", err)
+	}
+	if len(nh.Links) != 3 || len(nh.Neighbors) != 3 {
+		t.Errorf("got %d links and %d neighbors; want 3 and 3", len(nh.Links), len(nh.Neighbors))
+	}
+
+	idMap, err = sln.RenameType(ctx, person, human)
+	if err != nil || len(idMap) != 0 {
+		t.Errorf("rename absent type - got %v, %v; want empty mapping, <nil>", idMap, err)
+	}
+	if _, err = sln.RenameType(ctx, human, city); err == nil {
+		t.Error("rename to used type - got nil error")
+	}
+	var ite *gosln.InvalidTypeError
+	if _, err = sln.RenameType(ctx, human, gosln.Type{}); !errors.As(err, &ite) {
+		t.Errorf("rename to invalid type - got %v; want *InvalidTypeError", err)
+	}
+}
+
+func TestNew_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	snap, err := sln.Snapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = snap.Close()
+	}()
+
+	props := gosln.NewPropMap(1)
+	props.Set(nameProp, "Alicia")
+	if _, err = sln.SetNodeProperties(ctx, ids[0], props); err != nil {
+		t.Fatal("set properties -", err)
+	}
+	if err = sln.RemoveNodeByID(ctx, ids[1]); err != nil {
+		t.Fatal("remove node -", err)
+	}
+	if _, err = sln.CreateNode(ctx, city, nil); err != nil {
+		t.Fatal("create node -", err)
+	}
+
+	nodes, err := snap.GetAllNodes(ctx, nameTypes(), nil)
+	if err != nil {
+		t.Fatal("get all nodes from snapshot -", err)
+	}
+	want := []string{"Paris", "Alice", "Bob", "Carol"}
+	if got := nodeNames(t, nodes); !equalStrings(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	for _, node := range nodes {
+		if node.SLN != nil {
+			t.Errorf("%v - got non-nil SLN", node.ID)
+		}
+	}
+	if n, err := snap.NumLink(ctx, nil); err != nil || n != 5 {
+		t.Errorf("got NumLink %d, %v; want 5, <nil>", n, err)
+	}
+
+	if err = snap.Close(); err != nil {
+		t.Fatal("close snapshot -", err)
+	}
+	if _, err = snap.NumNode(ctx, nil); !errors.Is(err, gosln.ErrSLNClosed) {
+		t.Errorf("use closed snapshot - got %v; want ErrSLNClosed", err)
+	}
+	if n, err := sln.NumNode(ctx, nil); err != nil || n != 4 {
+		t.Errorf("got NumNode %d, %v after closing snapshot; want 4, <nil>", n, err)
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	nodes, err := sln.GetAllNodes(ctx, nameTypes(), nil)
+	if err != nil {
+		t.Fatal("get all nodes -", err)
+	}
+	links, err := sln.GetAllLinks(ctx, nil, nil)
+	if err != nil {
+		t.Fatal("get all links -", err)
+	}
+	snap, err := memsln.NewSnapshot(nodes, links)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = snap.Close()
+	}()
+
+	node, err := snap.GetNodeByID(ctx, ids[0], nameTypes())
+	if err != nil {
+		t.Fatal("get node -", err)
+	} else if node.SLN != nil {
+		t.Error("got non-nil SLN")
+	}
+	if got := nodeNames(t, []*gosln.Node{node}); !equalStrings(got, []string{"Alice"}) {
+		t.Errorf("got %v; want [Alice]", got)
+	}
+	nh, err := snap.GetNeighborhood(ctx, ids[0], gosln.NeighborhoodOptions{NeighborPropTypes: nameTypes()})
+	if err != nil {
+		t.Fatal("get neighborhood -", err)
+	}
+	want := []string{"Paris", "Bob", "Carol"}
+	if got := nodeNames(t, nh.Neighbors); !equalStrings(got, want) {
+		t.Errorf("got neighbors %v; want %v", got, want)
+	}
+
+	_, err = memsln.NewSnapshot(nodes[1:], links)
+	if err == nil {
+		t.Error("link to absent node - got nil error")
+	}
+	_, err = memsln.NewSnapshot(append(nodes, nodes[0]), nil)
+	if err == nil {
+		t.Error("duplicate node ID - got nil error")
+	}
+	var invalidIDErr *gosln.InvalidIDError
+	_, err = memsln.NewSnapshot([]*gosln.Node{{}}, nil)
+	if !errors.As(err, &invalidIDErr) {
+		t.Errorf("invalid ID - got %v; want *InvalidIDError", err)
+	}
+}
+
+func TestNew_GetNodeWithGroupedNeighbors(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	opts := gosln.NeighborhoodOptions{NeighborPropTypes: nameTypes()}
+	wantAll := map[gosln.Type][]string{
+		knows:   {"Bob", "Carol"},
+		livesIn: {"Paris"},
+	}
+	onlyKnows := gosln.NewTypeSet(2)
+	onlyKnows.Add(knows, gosln.MustNewType("Likes"))
+	wantKnows := map[gosln.Type][]string{
+		knows:                      {"Bob", "Carol"},
+		gosln.MustNewType("Likes"): nil,
+	}
+
+	testCases := []struct {
+		name      string
+		linkTypes gosln.TypeSet
+		want      map[gosln.Type][]string
+	}{
+		{"nil", nil, wantAll},
+		{"knows and likes", onlyKnows, wantKnows},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gn, err := sln.GetNodeWithGroupedNeighbors(ctx, ids[0], tc.linkTypes, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gn.Center.ID != ids[0] {
+				t.Errorf("got center %v; want %v", gn.Center.ID, ids[0])
+			}
+			if len(gn.Neighbors) != len(tc.want) {
+				t.Errorf("got %d groups; want %d", len(gn.Neighbors), len(tc.want))
+			}
+			for lt, want := range tc.want {
+				nodes, ok := gn.Neighbors[lt]
+				if !ok {
+					t.Errorf("link type %v is not a key", lt)
+				} else if got := nodeNames(t, nodes); !equalStrings(got, want) {
+					t.Errorf("%v - got %v; want %v", lt, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNew_GetLinksByNode(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	knowsLMC := gosln.NewLinkMatchClause()
+	knowsLMC.SetType(knows)
+
+	testCases := []struct {
+		name       string
+		dir        gosln.Direction
+		cond       gosln.LinkMatchCond
+		softRemove bool
+		want       [][2]int // Indexes of the from and to nodes in ids.
+	}{
+		{"outgoing", gosln.DirOutgoing, nil, false, [][2]int{{0, 1}, {0, 2}, {0, 3}}},
+		{"incoming", gosln.DirIncoming, nil, false, [][2]int{{1, 0}}},
+		{"both", gosln.DirBoth, nil, false, [][2]int{{0, 1}, {0, 2}, {1, 0}, {0, 3}}},
+		{"zero-value", 0, nil, false, [][2]int{{0, 1}, {0, 2}, {1, 0}, {0, 3}}},
+		{"both knows", gosln.DirBoth, gosln.LinkMatchCond{knowsLMC}, false, [][2]int{{0, 1}, {0, 2}, {1, 0}}},
+		{"empty cond", gosln.DirBoth, gosln.LinkMatchCond{}, false, nil},
+		{"Carol soft-removed", gosln.DirOutgoing, nil, true, [][2]int{{0, 1}, {0, 3}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.softRemove {
+				if err := sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+					t.Fatal("soft remove -", err)
+				}
+				t.Cleanup(func() {
+					if err := sln.RestoreNodeByID(ctx, ids[2]); err != nil {
+						t.Error("restore -", err)
+					}
+				})
+			}
+			links, err := sln.GetLinksByNode(ctx, ids[0], tc.dir, nil, tc.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(links) != len(tc.want) {
+				t.Fatalf("got %d links; want %d", len(links), len(tc.want))
+			}
+			got := make(map[[2]gosln.ID]int, len(links))
+			for _, link := range links {
+				got[[2]gosln.ID{link.From.ID, link.To.ID}]++
+			}
+			for _, w := range tc.want {
+				if got[[2]gosln.ID{ids[w[0]], ids[w[1]]}] != 1 {
+					t.Errorf("got links %v; want link from %v to %v once", links, ids[w[0]], ids[w[1]])
+				}
+			}
+		})
+	}
+
+	var nne *gosln.NodeNotExistError
+	if _, err := sln.GetLinksByNode(ctx, gosln.NewID(person, gosln.NowDate(), 100), gosln.DirBoth, nil, nil); !errors.As(err, &nne) {
+		t.Errorf("nonexistent node - got %v; want *NodeNotExistError", err)
+	}
+}
+
+func TestNew_GetCommonPropertyNames(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	testCases := []struct {
+		name       string
+		softRemove bool
+		want       []gosln.PropName
+	}{
+		{"with Carol", false, []gosln.PropName{nameProp}},
+		{"without Carol", true, []gosln.PropName{ageProp, nameProp}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.softRemove {
+				if err := sln.SoftRemoveNodeByID(ctx, ids[2]); err != nil {
+					t.Fatal("soft remove -", err)
+				}
+			}
+			names, err := sln.GetCommonPropertyNames(ctx, person)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names.Len() != len(tc.want) {
+				t.Fatalf("got %d names; want %v", names.Len(), tc.want)
+			}
+			for _, name := range tc.want {
+				if !names.ContainsItem(name) {
+					t.Errorf("%v is not in the result", name)
+				}
+			}
+		})
+	}
+	names, err := sln.GetCommonPropertyNames(ctx, gosln.MustNewType("Nobody"))
+	if err != nil || names.Len() != 0 {
+		t.Errorf("absent type - got %v, %v; want empty, <nil>", names, err)
+	}
+}
+
+func TestNew_DegreeDistributionAndAggregateNumeric(t *testing.T) {
+	ctx := context.Background()
+	sln, ids := newTestSLN(t)
+	testCases := []struct {
+		name       string
+		dir        gosln.Direction
+		softRemove bool
+		want       map[int]int
+	}{
+		// Alice: 3, Bob: 2, Carol: 0, Paris: 0.
+		{"outgoing", gosln.DirOutgoing, false, map[int]int{0: 2, 2: 1, 3: 1}},
+		// Alice is soft-removed. Bob: 0, Carol: 0, Paris: 1.
+		{"incoming without Alice", gosln.DirIncoming, true, map[int]int{0: 2, 1: 1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.softRemove {
+				if err := sln.SoftRemoveNodeByID(ctx, ids[0]); err != nil {
+					t.Fatal("soft remove -", err)
+				}
+				t.Cleanup(func() {
+					if err := sln.RestoreNodeByID(ctx, ids[0]); err != nil {
+						t.Error("restore -", err)
+					}
+				})
+			}
+			dist, err := sln.DegreeDistribution(ctx, tc.dir, nil)
+			if err != nil {
+				t.Fatal("degree distribution -", err)
+			}
+			if len(dist) != len(tc.want) {
+				t.Errorf("got %v; want %v", dist, tc.want)
+			}
+			for k, v := range tc.want {
+				if dist[k] != v {
+					t.Errorf("got %v; want %v", dist, tc.want)
+					break
+				}
+			}
+		})
+	}
+
+	min, max, sum, count, err := sln.AggregateNumeric(ctx, person, ageProp)
+	if err != nil {
+		t.Fatal("aggregate -", err)
+	}
+	if min != 25 || max != 30 || sum != 55 || count != 2 {
+		t.Errorf("got %v, %v, %v, %d; want 25, 30, 55, 2", min, max, sum, count)
+	}
+	var pte *gosln.PropTypeError
+	if _, _, _, _, err = sln.AggregateNumeric(ctx, person, nameProp); !errors.As(err, &pte) {
+		t.Errorf("aggregate strings - got %v; want *PropTypeError", err)
+	}
+}
+
+func TestNew_DistinctPropValues(t *testing.T) {
+	ctx := context.Background()
+	sln, _ := newTestSLN(t)
+	for _, age := range []any{int8(30), 25.0, 41} {
+		props := gosln.NewPropMap(1)
+		props.Set(ageProp, age)
+		if _, err := sln.CreateNode(ctx, person, props); err != nil {
+			t.Fatal("create node -", err)
+		}
+	}
+	values, err := sln.DistinctPropValues(ctx, person, ageProp, gosln.PTInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{30, 25, 41}
+	if len(values) != len(want) {
+		t.Fatalf("got %v; want %v", values, want)
+	}
+	for i := range values {
+		if values[i] != want[i] {
+			t.Fatalf("got %v; want %v", values, want)
+		}
+	}
+
+	scores := gosln.MustNewPropName("scores")
+	negZero, nan := math.Copysign(0, -1), math.NaN()
+	for _, s := range [][]float64{{0, 1}, {negZero, 1}, {nan}, {nan}} {
+		props := gosln.NewPropMap(1)
+		props.Set(scores, s)
+		if _, err = sln.CreateNode(ctx, person, props); err != nil {
+			t.Fatal("create node -", err)
+		}
+	}
+	values, err = sln.DistinctPropValues(ctx, person, scores, gosln.PTFloat64Slice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {0, 1} and {-0, 1} are equal, while {NaN} is not equal to itself.
+	if len(values) != 3 {
+		t.Errorf("got %v; want [[0 1] [NaN] [NaN]]", values)
+	} else {
+		for i, v := range values {
+			s, _ := v.([]float64)
+			if i == 0 && (len(s) != 2 || s[0] != 0 || s[1] != 1) ||
+				i > 0 && (len(s) != 1 || !math.IsNaN(s[0])) {
+				t.Errorf("got %v; want [[0 1] [NaN] [NaN]]", values)
+				break
+			}
+		}
+	}
+
+	var pte *gosln.PropTypeError
+	if _, err = sln.DistinctPropValues(ctx, person, nameProp, gosln.PTInt); !errors.As(err, &pte) {
+		t.Errorf("names as int - got %v; want *PropTypeError", err)
+	}
+	var ipte *gosln.InvalidPropTypeError
+	if _, err = sln.DistinctPropValues(ctx, person, nameProp, 0); !errors.As(err, &ipte) {
+		t.Errorf("invalid property type - got %v; want *InvalidPropTypeError", err)
+	}
+}
+
+func TestNew_Close(t *testing.T) {
+	ctx := context.Background()
+	sln := memsln.New()
+	if sln.Closed() {
+		t.Fatal("new SLN is closed")
+	}
+	for i := 0; i < 2; i++ {
+		if err := sln.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !sln.Closed() {
+		t.Error("got not closed after Close")
+	}
+	if _, err := sln.CreateNode(ctx, person, nil); !errors.Is(err, gosln.ErrSLNClosed) {
+		t.Errorf("create node - got %v; want ErrSLNClosed", err)
+	}
+	if _, err := sln.GetAllNodes(ctx, nil, nil); !errors.Is(err, gosln.ErrSLNClosed) {
+		t.Errorf("get all nodes - got %v; want ErrSLNClosed", err)
+	}
+}
+
+func TestNew_ConcurrentCounters(t *testing.T) {
+	ctx := context.Background()
+	sln := memsln.New()
+	defer func() {
+		_ = sln.Close()
+	}()
+	const numWriter, numIter = 8, 50
+	types := []gosln.Type{person, city}
+	errC := make(chan error, numWriter+1)
+	var writers sync.WaitGroup
+	writers.Add(numWriter)
+	for i := 0; i < numWriter; i++ {
+		go func(t gosln.Type) {
+			defer writers.Done()
+			for j := 0; j < numIter; j++ {
+				node, err := sln.CreateNode(ctx, t, nil)
+				if err == nil && j%2 == 1 {
+					err = sln.RemoveNodeByID(ctx, node.ID)
+				}
+				if err != nil {
+					errC <- err
+					return
+				}
+			}
+		}(types[i%len(types)])
+	}
+	done, readerDone := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			n, err := sln.NumNodeType(ctx)
+			if err == nil && n > len(types) {
+				err = errors.New("too many node types")
+			}
+			if err == nil {
+				_, err = sln.NumNode(ctx, gosln.NodeMatchCond{typeClause(person)})
+			}
+			if err != nil {
+				errC <- err
+				return
+			}
+		}
+	}()
+	writers.Wait()
+	close(done)
+	<-readerDone
+	close(errC)
+	for err := range errC {
+		t.Error(err)
+	}
+
+	want := numWriter / len(types) * numIter / 2
+	for _, typ := range types {
+		n, err := sln.NumNode(ctx, gosln.NodeMatchCond{typeClause(typ)})
+		if err != nil || n != want {
+			t.Errorf("%v - got %d, %v; want %d, <nil>", typ, n, err, want)
+		}
+	}
+	if n, err := sln.NumNodeType(ctx); err != nil || n != len(types) {
+		t.Errorf("got NumNodeType %d, %v; want %d, <nil>", n, err, len(types))
+	}
+}
+
+// typeClause returns a NodeMatchClause specifying only the node type t.
+func typeClause(t gosln.Type) gosln.NodeMatchClause {
+	nmc := gosln.NewNodeMatchClause()
+	nmc.SetType(t)
+	return nmc
+}
@@ -0,0 +1,127 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/memsln"
+)
+
+// recordingObserver records the notifications as strings.
+type recordingObserver struct {
+	gosln.NopObserver
+	mu     sync.Mutex
+	events []string
+}
+
+func (obs *recordingObserver) record(format string, args ...any) {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	obs.events = append(obs.events, fmt.Sprintf(format, args...))
+}
+
+func (obs *recordingObserver) OnNodeCreated(node *gosln.Node) {
+	obs.record("node created %v", node.Type)
+}
+
+func (obs *recordingObserver) OnNodeUpdated(node *gosln.Node) {
+	obs.record("node updated %d", node.Props.Len())
+}
+
+func (obs *recordingObserver) OnNodeRemoved(gosln.ID) {
+	obs.record("node removed")
+}
+
+func (obs *recordingObserver) OnNodeSoftRemoved(gosln.ID) {
+	obs.record("node soft-removed")
+}
+
+func (obs *recordingObserver) OnLinkCreated(link *gosln.Link) {
+	obs.record("link created %v", link.Type)
+}
+
+func (obs *recordingObserver) OnLinkRemoved(gosln.ID) {
+	obs.record("link removed")
+}
+
+func TestNew_RegisterObserver(t *testing.T) {
+	ctx := context.Background()
+	sln := memsln.New()
+	obs, unregistered := new(recordingObserver), new(recordingObserver)
+	sln.RegisterObserver(obs)
+	unregister := sln.RegisterObserver(unregistered)
+	unregister()
+	unregister() // no-op
+	sln.RegisterObserver(nil)()
+
+	a, err := sln.CreateNode(ctx, person, nil)
+	if err != nil {
+		t.Fatal("create node -", err)
+	}
+	b, err := sln.CreateNode(ctx, city, nil)
+	if err != nil {
+		t.Fatal("create node -", err)
+	}
+	if _, err = sln.CreateLink(ctx, livesIn, a.ID, b.ID, nil); err != nil {
+		t.Fatal("create link -", err)
+	}
+	props := gosln.NewPropMap(1)
+	props.Set(nameProp, "Alice")
+	if _, err = sln.SetNodeProperties(ctx, a.ID, props); err != nil {
+		t.Fatal("set properties -", err)
+	}
+	if err = sln.SoftRemoveNodeByID(ctx, b.ID); err != nil {
+		t.Fatal("soft remove -", err)
+	}
+	tx, err := sln.BeginTx(ctx)
+	if err != nil {
+		t.Fatal("begin -", err)
+	}
+	if err = tx.RemoveNodeByID(ctx, a.ID); err != nil {
+		t.Fatal("remove node in tx -", err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal("commit -", err)
+	}
+	if err = sln.Close(); err != nil { // wait for the notifications
+		t.Fatal("close -", err)
+	}
+
+	want := []string{
+		"node created Person",
+		"node created City",
+		"link created LivesIn",
+		"node updated 1",
+		"node soft-removed",
+		"link removed",
+		"node removed",
+	}
+	if got := strings.Join(obs.events, "; "); got != strings.Join(want, "; ") {
+		t.Errorf("got %s\nwant %s", got, strings.Join(want, "; "))
+	}
+	if len(unregistered.events) != 0 {
+		t.Errorf("unregistered observer got %v", unregistered.events)
+	}
+}
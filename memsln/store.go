@@ -0,0 +1,1061 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/propconv"
+)
+
+// nodeRecord is the record of a semantic node stored in the memory.
+type nodeRecord struct {
+	id      gosln.ID              // The node ID.
+	t       gosln.Type            // The node type.
+	props   gosln.PropMap         // The properties on the node, never modified after being stored.
+	deleted bool                  // Whether the node has been soft-removed.
+	links   map[gosln.ID]struct{} // IDs of the links starting from or pointing to the node.
+}
+
+// linkRecord is the record of a semantic link stored in the memory.
+type linkRecord struct {
+	id    gosln.ID      // The link ID.
+	t     gosln.Type    // The link type.
+	props gosln.PropMap // The properties on the link, never modified after being stored.
+	from  gosln.ID      // ID of the node from which the link starts.
+	to    gosln.ID      // ID of the node to which the link points.
+}
+
+// store holds the nodes and links and implements
+// the read operations on them, i.e., interface gosln.ReadOnlySLN.
+//
+// The property maps in the records are replaced rather than modified
+// when the properties are updated,
+// so they can be shared between a store and its snapshots.
+type store struct {
+	mu     sync.RWMutex
+	closed bool
+
+	// version is increased each time the write lock is acquired
+	// by the method lock, to detect modifications.
+	version uint64
+
+	// owner is set to the field SLN of the returned nodes and links.
+	// It is nil for a snapshot.
+	owner gosln.SLN
+
+	nodes map[gosln.ID]*nodeRecord
+	links map[gosln.ID]*linkRecord
+
+	// The following counters are updated together with nodes and links,
+	// so that the number of types and the number of nodes and links
+	// of each type can be obtained in O(1) time.
+
+	nodeCounts    map[gosln.Type]int // Number of nodes of each type, including the soft-removed nodes.
+	deletedCounts map[gosln.Type]int // Number of soft-removed nodes of each type.
+	linkCounts    map[gosln.Type]int // Number of links of each type.
+	numDeleted    int                // Total number of soft-removed nodes.
+}
+
+var _ gosln.ReadOnlySLN = (*snapshot)(nil)
+
+// snapshot is a point-in-time read-only view of an in-memory SLN.
+type snapshot struct {
+	*store
+}
+
+// newStore creates a new empty store.
+func newStore() *store {
+	return &store{
+		nodes:         make(map[gosln.ID]*nodeRecord),
+		links:         make(map[gosln.ID]*linkRecord),
+		nodeCounts:    make(map[gosln.Type]int),
+		deletedCounts: make(map[gosln.Type]int),
+		linkCounts:    make(map[gosln.Type]int),
+	}
+}
+
+// NewSnapshot creates a read-only in-memory SLN
+// holding the specified nodes and links with their IDs kept.
+//
+// It helps other implementations of gosln.SLN implement
+// the method Snapshot by loading the whole network into the memory.
+//
+// The type of each node and link is taken from its ID,
+// and its properties are copied.
+// The nil nodes and links are ignored.
+// The field SLN of the nodes and links returned by
+// the read-only SLN is nil.
+//
+// NewSnapshot reports a *gosln.InvalidIDError if any ID is invalid.
+// (To test whether err is *gosln.InvalidIDError, use function errors.As.)
+//
+// NewSnapshot reports an error if any ID is duplicate,
+// or any link starts from or points to a node absent from nodes.
+func NewSnapshot(nodes []*gosln.Node, links []*gosln.Link) (snap gosln.ReadOnlySLN, err error) {
+	s := newStore()
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		} else if !node.ID.IsValid() {
+			return nil, errors.AutoWrap(gosln.NewInvalidIDError(node.ID))
+		} else if s.nodes[node.ID] != nil {
+			return nil, errors.AutoNew("duplicate node ID " + node.ID.String())
+		}
+		rec := &nodeRecord{
+			id:      node.ID,
+			t:       node.ID.Type(),
+			props:   clonePropsToStore(node.Props),
+			deleted: node.Deleted,
+			links:   make(map[gosln.ID]struct{}),
+		}
+		s.nodes[rec.id] = rec
+		s.nodeCounts[rec.t]++
+		if rec.deleted {
+			s.deletedCounts[rec.t]++
+			s.numDeleted++
+		}
+	}
+	for _, link := range links {
+		if link == nil {
+			continue
+		} else if !link.ID.IsValid() {
+			return nil, errors.AutoWrap(gosln.NewInvalidIDError(link.ID))
+		} else if s.links[link.ID] != nil || s.nodes[link.ID] != nil {
+			return nil, errors.AutoNew("duplicate link ID " + link.ID.String())
+		}
+		if link.From == nil || s.nodes[link.From.ID] == nil ||
+			link.To == nil || s.nodes[link.To.ID] == nil {
+			return nil, errors.AutoNew("link " + link.ID.String() + " has an endpoint absent from nodes")
+		}
+		rec := &linkRecord{
+			id:    link.ID,
+			t:     link.ID.Type(),
+			props: clonePropsToStore(link.Props),
+			from:  link.From.ID,
+			to:    link.To.ID,
+		}
+		s.links[rec.id] = rec
+		s.linkCounts[rec.t]++
+		s.nodes[rec.from].links[rec.id] = struct{}{}
+		s.nodes[rec.to].links[rec.id] = struct{}{}
+	}
+	return &snapshot{store: s}, nil
+}
+
+// clone returns a copy of s with the specified owner.
+//
+// The caller should hold the lock of s.
+func (s *store) clone(owner gosln.SLN) *store {
+	c := &store{
+		owner:         owner,
+		nodes:         make(map[gosln.ID]*nodeRecord, len(s.nodes)),
+		links:         make(map[gosln.ID]*linkRecord, len(s.links)),
+		nodeCounts:    make(map[gosln.Type]int, len(s.nodeCounts)),
+		deletedCounts: make(map[gosln.Type]int, len(s.deletedCounts)),
+		linkCounts:    make(map[gosln.Type]int, len(s.linkCounts)),
+		numDeleted:    s.numDeleted,
+	}
+	for id, rec := range s.nodes {
+		r := *rec
+		r.links = make(map[gosln.ID]struct{}, len(rec.links))
+		for linkID := range rec.links {
+			r.links[linkID] = struct{}{}
+		}
+		c.nodes[id] = &r
+	}
+	for id, rec := range s.links {
+		r := *rec
+		c.links[id] = &r
+	}
+	for t, n := range s.nodeCounts {
+		c.nodeCounts[t] = n
+	}
+	for t, n := range s.deletedCounts {
+		c.deletedCounts[t] = n
+	}
+	for t, n := range s.linkCounts {
+		c.linkCounts[t] = n
+	}
+	return c
+}
+
+// rLock checks ctx and acquires the read lock of s.
+//
+// If ctx is done or s is closed, it returns an error
+// without holding the lock.
+// Otherwise, it returns nil, and the caller should release the lock.
+func (s *store) rLock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return gosln.ErrSLNClosed
+	}
+	return nil
+}
+
+// lock checks ctx and acquires the write lock of s.
+//
+// If ctx is done or s is closed, it returns an error
+// without holding the lock.
+// Otherwise, it increases the version of s and returns nil,
+// and the caller should release the lock.
+func (s *store) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return gosln.ErrSLNClosed
+	}
+	s.version++
+	return nil
+}
+
+// Close marks s as closed and releases the nodes and links.
+//
+// It waits for the in-flight operations to finish.
+func (s *store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.nodes, s.links = nil, nil
+		s.nodeCounts, s.deletedCounts, s.linkCounts = nil, nil, nil
+		s.numDeleted = 0
+	}
+	return nil
+}
+
+func (s *store) Closed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closed
+}
+
+func (s *store) NumNodeType(ctx context.Context) (n int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	return len(s.nodeCounts), nil
+}
+
+func (s *store) NumLinkType(ctx context.Context) (n int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	return len(s.linkCounts), nil
+}
+
+func (s *store) NumNode(ctx context.Context, cond gosln.NodeMatchCond) (n int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	n, ok := s.countNodesByType(cond)
+	if ok {
+		return n, nil
+	}
+	for _, rec := range s.nodes {
+		if cond.Match(s.rawNode(rec)) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *store) ExistsAtLeast(ctx context.Context, cond gosln.NodeMatchCond, k int) (ok bool, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return false, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	if k <= 0 {
+		return true, nil
+	}
+	n, ok := s.countNodesByType(cond)
+	if ok {
+		return n >= k, nil
+	}
+	for _, rec := range s.nodes {
+		if cond.Match(s.rawNode(rec)) {
+			n++
+			if n >= k {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *store) NumLink(ctx context.Context, cond gosln.LinkMatchCond) (n int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	n, ok := s.countLinksByType(cond)
+	if ok {
+		return n, nil
+	}
+	for _, rec := range s.links {
+		if cond.Match(s.rawLink(rec)) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *store) GetNodeTypes(ctx context.Context) (types []gosln.Type, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	return sortedTypes(s.nodeCounts), nil
+}
+
+func (s *store) GetLinkTypes(ctx context.Context) (types []gosln.Type, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	return sortedTypes(s.linkCounts), nil
+}
+
+func (s *store) InferSchema(ctx context.Context) (schema *gosln.Schema, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	schema = gosln.NewSchema()
+	for _, rec := range s.nodes {
+		if !rec.deleted {
+			schema.AddNode(s.rawNode(rec))
+		}
+	}
+	for _, rec := range s.links {
+		schema.AddLink(&gosln.Link{NL: gosln.NL{
+			SLN:   s.owner,
+			ID:    rec.id,
+			Type:  rec.t,
+			Props: rec.props,
+		}})
+	}
+	return schema, nil
+}
+
+func (s *store) GetCommonPropertyNames(ctx context.Context, t gosln.Type) (names gosln.PropNameSet, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	names = gosln.NewPropNameSet(0)
+	first := true
+	for _, rec := range s.nodes {
+		if rec.deleted || rec.t != t {
+			continue
+		}
+		if first {
+			rec.props.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
+				names.Add(x.Key)
+				return true
+			})
+			first = false
+		} else {
+			names.Filter(func(x gosln.PropName) (keep bool) {
+				_, present := rec.props.Get(x)
+				return present
+			})
+		}
+		if names.Len() == 0 {
+			break
+		}
+	}
+	return names, nil
+}
+
+func (s *store) GetNodeByID(ctx context.Context, id gosln.ID, propTypes gosln.PropTypeMap) (
+	node *gosln.Node, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	node, err = s.exportNode(rec, propTypes)
+	return node, errors.AutoWrap(err)
+}
+
+func (s *store) GetNodesByIDs(ctx context.Context, ids []gosln.ID, propTypes gosln.PropTypeMap) (
+	nodes map[gosln.ID]*gosln.Node, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	nodes = make(map[gosln.ID]*gosln.Node, len(ids))
+	for _, id := range ids {
+		if _, ok := nodes[id]; ok {
+			continue
+		}
+		rec := s.nodes[id]
+		if rec == nil || rec.deleted {
+			continue
+		}
+		nodes[id], err = s.exportNode(rec, propTypes)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return nodes, nil
+}
+
+func (s *store) GetLinkByID(ctx context.Context, id gosln.ID, propTypes gosln.PropTypeMap) (
+	link *gosln.Link, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	rec := s.links[id]
+	if rec == nil {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	link, err = s.exportLink(rec, propTypes, nil, nil)
+	return link, errors.AutoWrap(err)
+}
+
+func (s *store) GetAllNodes(ctx context.Context, propTypes gosln.PropTypeMap, cond gosln.NodeMatchCond) (
+	nodes []*gosln.Node, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	var ids []gosln.ID
+	for id, rec := range s.nodes {
+		if cond.Match(s.rawNode(rec)) {
+			ids = append(ids, id)
+		}
+	}
+	gosln.SortIDs(ids)
+	nodes = make([]*gosln.Node, len(ids))
+	for i, id := range ids {
+		nodes[i], err = s.exportNode(s.nodes[id], propTypes)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return nodes, nil
+}
+
+func (s *store) GetAllLinks(ctx context.Context, propTypes gosln.PropTypeMap, cond gosln.LinkMatchCond) (
+	links []*gosln.Link, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	var ids []gosln.ID
+	for id, rec := range s.links {
+		if cond.Match(s.rawLink(rec)) {
+			ids = append(ids, id)
+		}
+	}
+	gosln.SortIDs(ids)
+	links = make([]*gosln.Link, len(ids))
+	for i, id := range ids {
+		links[i], err = s.exportLink(s.links[id], propTypes, nil, nil)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return links, nil
+}
+
+func (s *store) GetNodesPage(
+	ctx context.Context,
+	propTypes gosln.PropTypeMap,
+	cond gosln.NodeMatchCond,
+	page gosln.Page,
+) (nodes []*gosln.Node, total int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	var matched []*gosln.Node
+	for _, rec := range s.nodes {
+		if node := s.rawNode(rec); cond.Match(node) {
+			matched = append(matched, node)
+		}
+	}
+	total = len(matched)
+	// Page on the raw nodes, as the sort key
+	// may be absent from propTypes.
+	matched = gosln.PageNodes(matched, page)
+	nodes = make([]*gosln.Node, len(matched))
+	for i, node := range matched {
+		nodes[i], err = s.exportNode(s.nodes[node.ID], propTypes)
+		if err != nil {
+			return nil, 0, errors.AutoWrap(err)
+		}
+	}
+	return nodes, total, nil
+}
+
+func (s *store) GetLinksPage(
+	ctx context.Context,
+	propTypes gosln.PropTypeMap,
+	cond gosln.LinkMatchCond,
+	page gosln.Page,
+) (links []*gosln.Link, total int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	var matched []*gosln.Link
+	for _, rec := range s.links {
+		if link := s.rawLink(rec); cond.Match(link) {
+			matched = append(matched, link)
+		}
+	}
+	total = len(matched)
+	matched = gosln.PageLinks(matched, page)
+	links = make([]*gosln.Link, len(matched))
+	for i, link := range matched {
+		links[i], err = s.exportLink(s.links[link.ID], propTypes, nil, nil)
+		if err != nil {
+			return nil, 0, errors.AutoWrap(err)
+		}
+	}
+	return links, total, nil
+}
+
+func (s *store) GetNeighborhood(ctx context.Context, id gosln.ID, opts gosln.NeighborhoodOptions) (
+	neighborhood *gosln.Neighborhood, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	neighborhood = new(gosln.Neighborhood)
+	neighborhood.Center, err = s.exportNode(rec, opts.CenterPropTypes)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	neighbors := make(map[gosln.ID]*gosln.Node)
+	err = s.rangeIncidentLinks(rec, opts, func(l *linkRecord, other *nodeRecord) error {
+		otherNode := neighborhood.Center
+		if other != rec {
+			otherNode = neighbors[other.id]
+			if otherNode == nil {
+				var err error
+				otherNode, err = s.exportNode(other, opts.NeighborPropTypes)
+				if err != nil {
+					return err
+				}
+				neighbors[other.id] = otherNode
+				neighborhood.Neighbors = append(neighborhood.Neighbors, otherNode)
+			}
+		}
+		from, to := neighborhood.Center, otherNode
+		if l.from != rec.id {
+			from, to = to, from
+		}
+		link, err := s.exportLink(l, opts.LinkPropTypes, from, to)
+		if err != nil {
+			return err
+		}
+		neighborhood.Links = append(neighborhood.Links, link)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	sortNodes(neighborhood.Neighbors)
+	return neighborhood, nil
+}
+
+func (s *store) GetLinksByNode(
+	ctx context.Context,
+	id gosln.ID,
+	dir gosln.Direction,
+	propTypes gosln.PropTypeMap,
+	cond gosln.LinkMatchCond,
+) (links []*gosln.Link, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	links = make([]*gosln.Link, 0)
+	opts := gosln.NeighborhoodOptions{Direction: dir, LinkCond: cond}
+	err = s.rangeIncidentLinks(rec, opts, func(l *linkRecord, _ *nodeRecord) error {
+		link, err := s.exportLink(l, propTypes, nil, nil)
+		if err != nil {
+			return err
+		}
+		links = append(links, link)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	return links, nil
+}
+
+func (s *store) GetNodeWithGroupedNeighbors(
+	ctx context.Context,
+	id gosln.ID,
+	linkTypes gosln.TypeSet,
+	opts gosln.NeighborhoodOptions,
+) (groupedNode *gosln.GroupedNode, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	rec := s.nodes[id]
+	if rec == nil || rec.deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	groupedNode = &gosln.GroupedNode{Neighbors: make(map[gosln.Type][]*gosln.Node)}
+	groupedNode.Center, err = s.exportNode(rec, opts.CenterPropTypes)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	if linkTypes != nil {
+		linkTypes.Range(func(x gosln.Type) (cont bool) {
+			groupedNode.Neighbors[x] = make([]*gosln.Node, 0)
+			return true
+		})
+	}
+	neighbors := make(map[gosln.ID]*gosln.Node)
+	grouped := make(map[gosln.Type]map[gosln.ID]struct{})
+	err = s.rangeIncidentLinks(rec, opts, func(l *linkRecord, other *nodeRecord) error {
+		if other == rec || linkTypes != nil && !linkTypes.ContainsItem(l.t) {
+			return nil
+		}
+		group := grouped[l.t]
+		if group == nil {
+			group = make(map[gosln.ID]struct{})
+			grouped[l.t] = group
+		} else if _, ok := group[other.id]; ok {
+			return nil
+		}
+		group[other.id] = struct{}{}
+		node := neighbors[other.id]
+		if node == nil {
+			var err error
+			node, err = s.exportNode(other, opts.NeighborPropTypes)
+			if err != nil {
+				return err
+			}
+			neighbors[other.id] = node
+		}
+		groupedNode.Neighbors[l.t] = append(groupedNode.Neighbors[l.t], node)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	for _, nodes := range groupedNode.Neighbors {
+		sortNodes(nodes)
+	}
+	return groupedNode, nil
+}
+
+func (s *store) DistinctPropValues(ctx context.Context, t gosln.Type, name gosln.PropName, pt gosln.PropType) (
+	values []any, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	} else if !pt.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidPropTypeError(pt))
+	}
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	var ids []gosln.ID
+	for id, rec := range s.nodes {
+		if !rec.deleted && rec.t == t {
+			ids = append(ids, id)
+		}
+	}
+	gosln.SortIDs(ids)
+	seen := make(map[any]struct{})
+	values = make([]any, 0)
+	for _, id := range ids {
+		v, present := s.nodes[id].props.Get(name)
+		if !present {
+			continue
+		}
+		v, err = propconv.ConvertValue(name, v, pt)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		key := propconv.DistinctKey(v)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func (s *store) DegreeDistribution(ctx context.Context, direction gosln.Direction, cond gosln.LinkMatchCond) (
+	dist map[int]int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	if !direction.IsValid() {
+		direction = gosln.DirBoth
+	}
+	degrees := make(map[gosln.ID]int, len(s.nodes))
+	for _, rec := range s.links {
+		if s.nodes[rec.from].deleted || s.nodes[rec.to].deleted ||
+			!cond.Match(s.rawLink(rec)) {
+			continue
+		}
+		if direction != gosln.DirIncoming {
+			degrees[rec.from]++
+		}
+		if direction != gosln.DirOutgoing {
+			degrees[rec.to]++
+		}
+	}
+	dist = make(map[int]int)
+	for id, rec := range s.nodes {
+		if !rec.deleted {
+			dist[degrees[id]]++
+		}
+	}
+	return dist, nil
+}
+
+func (s *store) AggregateNumeric(ctx context.Context, t gosln.Type, name gosln.PropName) (
+	min, max, sum float64, count int, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return 0, 0, 0, 0, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	// Add the values in ascending order of the node IDs
+	// so that the sum is deterministic.
+	var ids []gosln.ID
+	for id, rec := range s.nodes {
+		if !rec.deleted && rec.t == t {
+			ids = append(ids, id)
+		}
+	}
+	gosln.SortIDs(ids)
+	for _, id := range ids {
+		v, present := s.nodes[id].props.Get(name)
+		if !present {
+			continue
+		}
+		if !gosln.PropTypeOf(v).IsRealNumber() {
+			return 0, 0, 0, 0, errors.AutoWrap(gosln.NewPropTypeError(
+				name, v, reflect.TypeOf(float64(0))))
+		}
+		x := propconv.RealNumberToFloat64(reflect.ValueOf(v))
+		if count == 0 || x < min {
+			min = x
+		}
+		if count == 0 || x > max {
+			max = x
+		}
+		sum += x
+		count++
+	}
+	return
+}
+
+// countNodesByType returns the number of nodes that satisfy cond
+// using the per-type counters, and true,
+// if cond specifies nothing other than the node types.
+// Otherwise, it returns (0, false).
+//
+// The caller should hold the lock of s.
+func (s *store) countNodesByType(cond gosln.NodeMatchCond) (n int, ok bool) {
+	if cond == nil {
+		return len(s.nodes) - s.numDeleted, true
+	}
+	types := make(map[gosln.Type]struct{}, len(cond))
+	for _, nmc := range cond {
+		switch {
+		case nmc == nil:
+		case nmc.GetID().IsValid(), nmc.GetPropMatchClause() != nil, nmc.GetIncludeDeleted():
+			return 0, false
+		case !nmc.GetType().IsValid():
+			return len(s.nodes) - s.numDeleted, true
+		default:
+			types[nmc.GetType()] = struct{}{}
+		}
+	}
+	for t := range types {
+		n += s.nodeCounts[t] - s.deletedCounts[t]
+	}
+	return n, true
+}
+
+// countLinksByType returns the number of links that satisfy cond
+// using the per-type counters, and true,
+// if cond specifies nothing other than the link types.
+// Otherwise, it returns (0, false).
+//
+// The caller should hold the lock of s.
+func (s *store) countLinksByType(cond gosln.LinkMatchCond) (n int, ok bool) {
+	if cond == nil {
+		return len(s.links), true
+	}
+	types := make(map[gosln.Type]struct{}, len(cond))
+	for _, lmc := range cond {
+		switch {
+		case lmc == nil:
+		case lmc.GetID().IsValid(),
+			lmc.GetPropMatchClause() != nil,
+			lmc.GetFromNodeMatchClause() != nil,
+			lmc.GetToNodeMatchClause() != nil:
+			return 0, false
+		case !lmc.GetType().IsValid():
+			return len(s.links), true
+		default:
+			types[lmc.GetType()] = struct{}{}
+		}
+	}
+	for t := range types {
+		n += s.linkCounts[t]
+	}
+	return n, true
+}
+
+// rangeIncidentLinks calls handler on each link that starts from
+// or points to the node rec in the direction specified by opts
+// and satisfies the link conditions in opts,
+// together with the node at the other end of the link,
+// in ascending order of the link IDs.
+//
+// The links whose other end has been soft-removed are skipped.
+// If the link starts from and points to rec, other is rec.
+//
+// If handler returns a non-nil error,
+// rangeIncidentLinks stops and returns that error.
+//
+// The caller should hold the lock of s.
+func (s *store) rangeIncidentLinks(
+	rec *nodeRecord,
+	opts gosln.NeighborhoodOptions,
+	handler func(l *linkRecord, other *nodeRecord) error,
+) error {
+	dir := opts.Direction
+	if !dir.IsValid() {
+		dir = gosln.DirBoth
+	}
+	ids := make([]gosln.ID, 0, len(rec.links))
+	for id := range rec.links {
+		ids = append(ids, id)
+	}
+	gosln.SortIDs(ids)
+	for _, id := range ids {
+		l := s.links[id]
+		if dir == gosln.DirOutgoing && l.from != rec.id ||
+			dir == gosln.DirIncoming && l.to != rec.id {
+			continue
+		}
+		otherID := l.to
+		if otherID == rec.id {
+			otherID = l.from
+		}
+		other := s.nodes[otherID]
+		if other.deleted || !opts.LinkCond.Match(s.rawLink(l)) {
+			continue
+		}
+		err := handler(l, other)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawNode returns a node corresponding to rec for matching.
+//
+// The properties on the returned node are those stored in rec,
+// so the caller must not modify them.
+func (s *store) rawNode(rec *nodeRecord) *gosln.Node {
+	return &gosln.Node{
+		NL: gosln.NL{
+			SLN:   s.owner,
+			ID:    rec.id,
+			Type:  rec.t,
+			Props: rec.props,
+		},
+		Deleted: rec.deleted,
+	}
+}
+
+// rawLink returns a link corresponding to rec for matching.
+//
+// The properties on the returned link and its From and To nodes
+// are those stored in the records,
+// so the caller must not modify them.
+//
+// The caller should hold the lock of s.
+func (s *store) rawLink(rec *linkRecord) *gosln.Link {
+	link := &gosln.Link{
+		NL: gosln.NL{
+			SLN:   s.owner,
+			ID:    rec.id,
+			Type:  rec.t,
+			Props: rec.props,
+		},
+		From: s.rawNode(s.nodes[rec.from]),
+	}
+	if rec.to == rec.from {
+		link.To = link.From
+	} else {
+		link.To = s.rawNode(s.nodes[rec.to])
+	}
+	return link
+}
+
+// exportNode returns a node corresponding to rec
+// with the properties converted according to propTypes.
+func (s *store) exportNode(rec *nodeRecord, propTypes gosln.PropTypeMap) (
+	node *gosln.Node, err error) {
+	node = s.rawNode(rec)
+	node.Props, err = propconv.Convert(rec.props, propTypes)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// exportLink returns a link corresponding to rec
+// with the properties converted according to propTypes.
+//
+// from and to are set to the fields From and To of the returned link.
+// If from is nil, a node without properties is used instead, as is to.
+//
+// The caller should hold the lock of s.
+func (s *store) exportLink(
+	rec *linkRecord,
+	propTypes gosln.PropTypeMap,
+	from, to *gosln.Node,
+) (link *gosln.Link, err error) {
+	link = &gosln.Link{
+		NL: gosln.NL{
+			SLN:  s.owner,
+			ID:   rec.id,
+			Type: rec.t,
+		},
+		From: from,
+		To:   to,
+	}
+	link.Props, err = propconv.Convert(rec.props, propTypes)
+	if err != nil {
+		return nil, err
+	}
+	if link.From == nil {
+		link.From = s.endpointNode(rec.from)
+	}
+	if link.To == nil {
+		if rec.to == rec.from {
+			link.To = link.From
+		} else {
+			link.To = s.endpointNode(rec.to)
+		}
+	}
+	return link, nil
+}
+
+// endpointNode returns a node with the specified ID
+// as the end of a link, which carries no properties.
+//
+// The caller should hold the lock of s.
+func (s *store) endpointNode(id gosln.ID) *gosln.Node {
+	rec := s.nodes[id]
+	return &gosln.Node{
+		NL: gosln.NL{
+			SLN:  s.owner,
+			ID:   id,
+			Type: rec.t,
+		},
+		Deleted: rec.deleted,
+	}
+}
+
+// sortedTypes returns the types in counts in ascending order.
+func sortedTypes(counts map[gosln.Type]int) []gosln.Type {
+	types := make([]gosln.Type, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Compare(types[j]) < 0
+	})
+	return types
+}
+
+// sortNodes sorts the nodes in ascending order of their IDs.
+func sortNodes(nodes []*gosln.Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID.Compare(nodes[j].ID) < 0
+	})
+}
@@ -0,0 +1,265 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln
+
+import (
+	"context"
+	"sync"
+
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+)
+
+// ErrTxConflict is an error indicating that the transaction cannot commit
+// because the SLN has been modified after the transaction began.
+//
+// The transaction is rolled back in this case.
+// The client can begin a new transaction to retry.
+//
+// The client should use errors.Is to test whether an error is ErrTxConflict.
+var ErrTxConflict = errors.AutoNewCustom(
+	"SLN has been modified after the transaction began",
+	errors.PrependFullPkgName,
+	0,
+)
+
+// memTx is an implementation of interface gosln.Tx for memSLN.
+//
+// It applies the operations to a copy of the SLN,
+// and swaps the copy in on commit.
+type memTx struct {
+	mu      sync.RWMutex
+	done    bool
+	s       *memSLN // The SLN that begins the transaction.
+	work    *memSLN // The copy of the SLN to which the operations apply.
+	version uint64  // The version of s when the transaction began.
+}
+
+var _ gosln.Tx = (*memTx)(nil)
+
+// BeginTx begins a transaction by copying the records of
+// all nodes and links, as the method Snapshot does.
+//
+// The transaction is optimistic:
+// it does not block the other operations on this SLN,
+// but its method Commit reports ErrTxConflict
+// if this SLN has been modified after the transaction began.
+func (s *memSLN) BeginTx(ctx context.Context) (tx gosln.Tx, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	work := &memSLN{
+		store:           s.clone(s),
+		serials:         make(map[gosln.Type]int64, len(s.serials)),
+		linkConstraints: make(map[gosln.Type]*linkConstraint, len(s.linkConstraints)),
+	}
+	for t, serial := range s.serials {
+		work.serials[t] = serial
+	}
+	for t, c := range s.linkConstraints {
+		work.linkConstraints[t] = c
+	}
+	return &memTx{s: s, work: work, version: s.version}, nil
+}
+
+func (tx *memTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errors.AutoWrap(gosln.ErrTxDone)
+	}
+	tx.done = true
+	s, work := tx.s, tx.work
+	tx.work = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.closed:
+		return errors.AutoWrap(gosln.ErrSLNClosed)
+	case s.version != tx.version:
+		return errors.AutoWrap(ErrTxConflict)
+	}
+	s.version++
+	s.nodes, s.links = work.nodes, work.links
+	s.nodeCounts, s.deletedCounts, s.linkCounts = work.nodeCounts, work.deletedCounts, work.linkCounts
+	s.numDeleted = work.numDeleted
+	s.serials = work.serials
+	s.notifier.Push(work.txEvents...)
+	return nil
+}
+
+func (tx *memTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errors.AutoWrap(gosln.ErrTxDone)
+	}
+	tx.done = true
+	tx.work = nil
+	return nil
+}
+
+func (tx *memTx) CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	node, err = work.CreateNode(ctx, t, props)
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *memTx) UpsertNode(ctx context.Context, t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+	node *gosln.Node, created bool, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, false, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	node, created, err = work.UpsertNode(ctx, t, keyName, props)
+	return node, created, errors.AutoWrap(err)
+}
+
+func (tx *memTx) CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	link, err = work.CreateLink(ctx, t, from, to, props)
+	return link, errors.AutoWrap(err)
+}
+
+func (tx *memTx) RemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	work, err := tx.begin()
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	return errors.AutoWrap(work.RemoveNodeByID(ctx, id))
+}
+
+func (tx *memTx) RemoveNodesByCond(ctx context.Context, cond gosln.NodeMatchCond) (removed int, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	removed, err = work.RemoveNodesByCond(ctx, cond)
+	return removed, errors.AutoWrap(err)
+}
+
+func (tx *memTx) SoftRemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	work, err := tx.begin()
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	return errors.AutoWrap(work.SoftRemoveNodeByID(ctx, id))
+}
+
+func (tx *memTx) RestoreNodeByID(ctx context.Context, id gosln.ID) error {
+	work, err := tx.begin()
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	return errors.AutoWrap(work.RestoreNodeByID(ctx, id))
+}
+
+func (tx *memTx) RemoveLinkByID(ctx context.Context, id gosln.ID) error {
+	work, err := tx.begin()
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	return errors.AutoWrap(work.RemoveLinkByID(ctx, id))
+}
+
+func (tx *memTx) RemoveLinksByCond(ctx context.Context, cond gosln.LinkMatchCond) (removed int, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	removed, err = work.RemoveLinksByCond(ctx, cond)
+	return removed, errors.AutoWrap(err)
+}
+
+func (tx *memTx) SetNodeProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	node, err = work.SetNodeProperties(ctx, id, props)
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *memTx) SetLinkProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	link, err = work.SetLinkProperties(ctx, id, props)
+	return link, errors.AutoWrap(err)
+}
+
+func (tx *memTx) MutateNodeProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	node *gosln.Node, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	node, err = work.MutateNodeProperties(ctx, id, pma)
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *memTx) MutateLinkProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	link *gosln.Link, err error) {
+	work, err := tx.begin()
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer tx.mu.RUnlock()
+	link, err = work.MutateLinkProperties(ctx, id, pma)
+	return link, errors.AutoWrap(err)
+}
+
+// begin acquires the read lock of tx and returns the working copy.
+//
+// If tx is done, it returns gosln.ErrTxDone without holding the lock.
+// Otherwise, the caller should release the lock.
+func (tx *memTx) begin() (work *memSLN, err error) {
+	tx.mu.RLock()
+	if tx.done {
+		tx.mu.RUnlock()
+		return nil, gosln.ErrTxDone
+	}
+	return tx.work, nil
+}
@@ -0,0 +1,128 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package memsln_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/memsln"
+)
+
+func TestMemTx(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name        string
+		commit      bool
+		modify      bool // whether to modify the SLN outside the transaction
+		wantErr     error
+		wantNumNode int
+	}{
+		{"commit", true, false, nil, 6},
+		{"rollback", false, false, nil, 4},
+		{"conflict", true, true, memsln.ErrTxConflict, 5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sln, ids := newTestSLN(t)
+			tx, err := sln.BeginTx(ctx)
+			if err != nil {
+				t.Fatal("begin -", err)
+			}
+			a, err := tx.CreateNode(ctx, person, nil)
+			if err != nil {
+				t.Fatal("create node in tx -", err)
+			}
+			if a.SLN != sln {
+				t.Error("the field SLN of the node is not the SLN")
+			}
+			b, err := tx.CreateNode(ctx, person, nil)
+			if err != nil {
+				t.Fatal("create node in tx -", err)
+			}
+			if _, err = tx.CreateLink(ctx, knows, a.ID, b.ID, nil); err != nil {
+				t.Fatal("create link in tx -", err)
+			}
+			if err = tx.RemoveLinkByID(ctx, gosln.ID{}); err != nil {
+				t.Fatal("remove link in tx -", err)
+			}
+			if n, err := sln.NumNode(ctx, nil); err != nil || n != 4 {
+				t.Errorf("before ending - got NumNode %d, %v; want 4, <nil>", n, err)
+			}
+			if tc.modify {
+				if _, err = sln.CreateNode(ctx, city, nil); err != nil {
+					t.Fatal("create node outside tx -", err)
+				}
+			}
+
+			if tc.commit {
+				err = tx.Commit()
+			} else {
+				err = tx.Rollback()
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("end - got %v; want %v", err, tc.wantErr)
+			}
+			if n, err := sln.NumNode(ctx, nil); err != nil || n != tc.wantNumNode {
+				t.Errorf("got NumNode %d, %v; want %d, <nil>", n, err, tc.wantNumNode)
+			}
+			if _, err = sln.GetNodeByID(ctx, ids[0], nil); err != nil {
+				t.Error("get existing node -", err)
+			}
+
+			if _, err = tx.CreateNode(ctx, person, nil); !errors.Is(err, gosln.ErrTxDone) {
+				t.Errorf("create node after end - got %v; want ErrTxDone", err)
+			}
+			if err = tx.Commit(); !errors.Is(err, gosln.ErrTxDone) {
+				t.Errorf("commit after end - got %v; want ErrTxDone", err)
+			}
+			if err = tx.Rollback(); !errors.Is(err, gosln.ErrTxDone) {
+				t.Errorf("rollback after end - got %v; want ErrTxDone", err)
+			}
+		})
+	}
+}
+
+func TestMemTx_SerialAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	sln := memsln.New()
+	defer func() {
+		_ = sln.Close()
+	}()
+	tx, err := sln.BeginTx(ctx)
+	if err != nil {
+		t.Fatal("begin -", err)
+	}
+	node, err := tx.CreateNode(ctx, person, nil)
+	if err != nil {
+		t.Fatal("create node in tx -", err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal("commit -", err)
+	}
+	node2, err := sln.CreateNode(ctx, person, nil)
+	if err != nil {
+		t.Fatal("create node -", err)
+	}
+	if node2.ID == node.ID {
+		t.Errorf("ID %v is reused after commit", node.ID)
+	}
+}
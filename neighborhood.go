@@ -0,0 +1,83 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+// NeighborhoodOptions specifies the options for
+// fetching the one-hop neighborhood of a semantic node.
+type NeighborhoodOptions struct {
+	// Direction is the direction of the links to be fetched,
+	// relative to the center node.
+	//
+	// If Direction is invalid (such as zero-value), DirBoth is used.
+	Direction Direction
+
+	// LinkCond specifies the conditions that the links must satisfy.
+	//
+	// A nil LinkCond matches any link.
+	LinkCond LinkMatchCond
+
+	// CenterPropTypes specify the types of properties on the center node.
+	// The properties not in CenterPropTypes are discarded.
+	CenterPropTypes PropTypeMap
+
+	// LinkPropTypes specify the types of properties on the links.
+	// The properties not in LinkPropTypes are discarded.
+	LinkPropTypes PropTypeMap
+
+	// NeighborPropTypes specify the types of properties on the neighbors.
+	// The properties not in NeighborPropTypes are discarded.
+	NeighborPropTypes PropTypeMap
+}
+
+// Neighborhood is the one-hop neighborhood of a semantic node.
+type Neighborhood struct {
+	// Center is the node whose neighborhood is fetched.
+	Center *Node
+
+	// Links are the links that start from or point to the center node
+	// and satisfy the specified conditions.
+	//
+	// The fields From and To of the links refer to Center
+	// and the nodes in Neighbors.
+	Links []*Link
+
+	// Neighbors are the nodes at the other end of the links in Links.
+	//
+	// Each neighbor appears once, even if it connects to
+	// the center node through multiple links.
+	// If a link starts from and points to the center node,
+	// the center node is not included in Neighbors.
+	Neighbors []*Node
+}
+
+// GroupedNode is a semantic node with its neighbors
+// grouped by the types of the links connecting them.
+type GroupedNode struct {
+	// Center is the node whose neighbors are fetched.
+	Center *Node
+
+	// Neighbors maps the link types to the nodes
+	// at the other end of the links of those types.
+	//
+	// For each link type, each neighbor appears once,
+	// even if it connects to the center node through multiple links.
+	// If a link starts from and points to the center node,
+	// the center node is not included.
+	Neighbors map[Type][]*Node
+}
@@ -0,0 +1,49 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import "time"
+
+// Config is the configuration of the SLN created by function New.
+type Config struct {
+	// DatabaseName is the name of the Neo4j database
+	// in which the nodes and links are stored.
+	//
+	// If it is empty, the default database of the Neo4j server is used.
+	DatabaseName string
+
+	// MaxRetries is the maximum number of times that a write operation
+	// is retried after failing with a retryable error
+	// (see function neo4j.IsRetryable),
+	// such as a deadlock or a switch of the cluster leader.
+	//
+	// These retries are in addition to those made by the Neo4j driver
+	// within its maximum transaction retry time.
+	// They do not apply to the transactions returned by BeginTx.
+	//
+	// If it is nonpositive, the write operations are not retried.
+	MaxRetries int
+
+	// RetryBackoff is the waiting time before the first retry
+	// of a write operation.
+	// The waiting time doubles after each retry.
+	//
+	// If it is nonpositive, the write operations are retried immediately.
+	RetryBackoff time.Duration
+}
@@ -0,0 +1,101 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/neo4jsln"
+	"github.com/donyori/gosln/slntest"
+)
+
+// The environment variables to configure the Neo4j database
+// used by TestNew_Conformance.
+const (
+	envURI      = "NEO4J_TEST_URI"      // Required; the test is skipped if it is absent.
+	envUsername = "NEO4J_TEST_USERNAME" // Optional; no authentication if it is absent.
+	envPassword = "NEO4J_TEST_PASSWORD" // Optional.
+	envDatabase = "NEO4J_TEST_DATABASE" // Optional; the default database if it is absent.
+)
+
+// TestNew_Conformance runs the conformance tests in package slntest
+// on a Neo4j database specified by the environment variables above.
+//
+// All nodes and relationships in the database are removed
+// before and after each subtest,
+// so the database must be dedicated to testing.
+func TestNew_Conformance(t *testing.T) {
+	uri := os.Getenv(envURI)
+	if uri == "" {
+		t.Skipf("%s is not set", envURI)
+	}
+	auth := neo4j.NoAuth()
+	if username := os.Getenv(envUsername); username != "" {
+		auth = neo4j.BasicAuth(username, os.Getenv(envPassword), "")
+	}
+	cfg := neo4jsln.Config{DatabaseName: os.Getenv(envDatabase)}
+	slntest.RunSLNConformance(t, func(t *testing.T) (gosln.SLN, func()) {
+		ctx := context.Background()
+		driver, err := neo4j.NewDriverWithContext(uri, auth)
+		if err != nil {
+			t.Fatal("create driver -", err)
+		}
+		if err = clearDatabase(ctx, driver, cfg.DatabaseName); err != nil {
+			_ = driver.Close(ctx)
+			t.Fatal("clear database -", err)
+		}
+		sln := neo4jsln.New(driver, cfg)
+		return sln, func() {
+			if err := sln.Close(); err != nil {
+				t.Error("close SLN -", err)
+			}
+			if err := clearDatabase(ctx, driver, cfg.DatabaseName); err != nil {
+				t.Error("clear database -", err)
+			}
+			if err := driver.Close(ctx); err != nil {
+				t.Error("close driver -", err)
+			}
+		}
+	})
+}
+
+// clearDatabase removes all nodes and relationships
+// in the specified database.
+func clearDatabase(ctx context.Context, driver neo4j.DriverWithContext, dbName string) error {
+	sess := driver.NewSession(ctx, neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeWrite,
+		DatabaseName: dbName,
+	})
+	defer func() {
+		_ = sess.Close(ctx)
+	}()
+	_, err := sess.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, "MATCH (n) DETACH DELETE n", nil)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+	return err
+}
@@ -19,6 +19,12 @@
 package neo4jsln
 
 import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/donyori/gogo/container/mapping"
 	"github.com/donyori/gogo/errors"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
@@ -29,9 +35,41 @@ import (
 // slnIDPropName is the property name of SLN ID in Cypher.
 const slnIDPropName = "slnID"
 
+// slnDeletedPropName is the property name of the mark of
+// soft-removed nodes in Cypher.
+//
+// It is set to true on the soft-removed nodes
+// and absent from the other nodes.
+const slnDeletedPropName = "slnDeleted"
+
+// serialLabel is the label of the Neo4j nodes that record
+// the last serial used in the IDs of each type.
+//
+// It begins with "SLN", so it never collides with the SLN types.
+const serialLabel = "SLNSerial"
+
+// linkConstraintLabel is the label of the Neo4j nodes that record
+// the constraints on the endpoint types of each link type,
+// registered by the method RegisterLinkConstraint.
+//
+// It begins with "SLN", so it never collides with the SLN types.
+const linkConstraintLabel = "SLNLinkConstraint"
+
+// errCondNotTranslatable is an error indicating that
+// the match conditions cannot be translated into Cypher by buildWhere.
+var errCondNotTranslatable = errors.AutoNewCustom(
+	"match condition cannot be translated into Cypher",
+	errors.PrependFullPkgName,
+	0,
+)
+
 // makeParameterMap renders a semantic node or link ID, a property map,
 // and property names about to be removed as a parameter map for Cypher.
 //
+// The property values are converted by function paramValue.
+// The property names about to be removed are mapped to nil,
+// which removes the properties by "SET n += $para" in Cypher.
+//
 // If paraName is empty, makeParameterMap reports an error.
 //
 // If id is invalid, it is ignored.
@@ -63,11 +101,7 @@ func makeParameterMap(
 	}
 	if props != nil {
 		props.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
-			if date, ok := x.Value.(gosln.Date); ok {
-				m[x.Key.String()] = neo4j.DateOf(date.GoTime())
-			} else {
-				m[x.Key.String()] = x.Value
-			}
+			m[x.Key.String()] = paramValue(x.Value)
 			return true
 		})
 	}
@@ -79,3 +113,271 @@ func makeParameterMap(
 	}
 	return map[string]any{paraName: m}, nil
 }
+
+// paramValue converts the property value v to
+// a Cypher parameter value accepted by the Neo4j driver.
+//
+// It converts the temporal values to the Neo4j temporal types as follows:
+//   - gosln.Date: converted to neo4j.Date.
+//   - time.Time: converted to UTC and then to neo4j.LocalDateTime,
+//     so that the instant is kept but the time zone is not,
+//     which is consistent with the method Equal of time.Time
+//     used to compare time.Time property values.
+//
+// The []byte values are returned as they are,
+// which the driver sends as Neo4j byte arrays (not base64 strings),
+// and reads back as []byte.
+// The other values are also returned as they are.
+func paramValue(v any) any {
+	switch x := v.(type) {
+	case gosln.Date:
+		return neo4j.DateOf(x.GoTime())
+	case time.Time:
+		return neo4j.LocalDateTimeOf(x.UTC())
+	}
+	return v
+}
+
+// label returns the Neo4j label or relationship type
+// corresponding to the SLN type t, quoted for Cypher.
+//
+// The caller should guarantee that t is valid.
+func label(t gosln.Type) string {
+	return "`" + t.String() + "`"
+}
+
+// typeStrings returns the string values of the types in ts
+// in ascending order, to be stored as a Neo4j list.
+//
+// If ts is nil or empty, it returns nil.
+func typeStrings(ts gosln.TypeSet) []string {
+	if ts == nil || ts.Len() == 0 {
+		return nil
+	}
+	types := ts.ToSortedSlice()
+	r := make([]string, len(types))
+	for i := range types {
+		r[i] = types[i].String()
+	}
+	return r
+}
+
+// typeAllowed reports whether the type t is in the Neo4j list v
+// stored by function typeStrings.
+//
+// If v is nil or empty, it returns true, which allows any type.
+func typeAllowed(v any, t gosln.Type) bool {
+	list, _ := v.([]any)
+	if len(list) == 0 {
+		return true
+	}
+	for _, x := range list {
+		if x == t.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// buildWhere translates cond into a Cypher predicate on
+// the node variable varName, to be used in a WHERE clause,
+// and returns the predicate with the parameters it references.
+//
+// Each NodeMatchClause in cond is rendered as a parenthesized
+// conjunction of the following predicates,
+// and the conjunctions are joined by OR:
+//   - The node ID: "var.slnID = $p".
+//   - The node type: "var:`Type`".
+//   - Unless the clause includes the soft-removed nodes:
+//     "var.slnDeleted IS NULL".
+//   - Each property in the component Equal of the PropMatchClause:
+//     "var.`prop` = $p",
+//     followed by "toString(var.`prop`) = toString($p)"
+//     if the value is of type int64 or float64 (see function equalTranslation).
+//   - Each property name in the component Present: "var.`prop` IS NOT NULL".
+//   - Each property name in the component Absent: "var.`prop` IS NULL".
+//
+// A nil cond is rendered as "var.slnDeleted IS NULL",
+// and a non-nil but empty cond is rendered as "false".
+// The properties are rendered in ascending order of their names,
+// so the result is deterministic.
+//
+// The parameters are named with varName followed by "_p" and a number,
+// and the property values are converted as in function makeParameterMap.
+//
+// If any PropMatchClause in cond has components other than
+// Equal, Present, and Absent,
+// or has a value in Equal that cannot be translated
+// (see function equalTranslation),
+// buildWhere reports errCondNotTranslatable,
+// and the caller should evaluate cond on the client side instead.
+// (To test whether err is errCondNotTranslatable, use function errors.Is.)
+//
+// If varName is empty, buildWhere reports an error.
+func buildWhere(varName string, cond gosln.NodeMatchCond) (
+	cypher string, params map[string]any, err error) {
+	if varName == "" {
+		return "", nil, errors.AutoNew("variable name is empty")
+	}
+	params = make(map[string]any)
+	if cond == nil {
+		return varName + "." + slnDeletedPropName + " IS NULL", params, nil
+	}
+	var disjuncts []string
+	for _, nmc := range cond {
+		if nmc == nil {
+			continue
+		}
+		conjuncts, err := buildClauseConjuncts(varName, nmc, params)
+		if err != nil {
+			return "", nil, errors.AutoWrap(err)
+		}
+		if len(conjuncts) == 0 {
+			// The clause matches any node.
+			return "true", make(map[string]any), nil
+		}
+		disjuncts = append(disjuncts, "("+strings.Join(conjuncts, " AND ")+")")
+	}
+	if len(disjuncts) == 0 {
+		return "false", params, nil
+	}
+	return strings.Join(disjuncts, " OR "), params, nil
+}
+
+// buildClauseConjuncts returns the predicates of buildWhere
+// translated from nmc, and adds the parameters they reference to params.
+//
+// It is the implementation of function buildWhere.
+func buildClauseConjuncts(varName string, nmc gosln.NodeMatchClause, params map[string]any) (
+	conjuncts []string, err error) {
+	addParam := func(v any) string {
+		name := varName + "_p" + strconv.Itoa(len(params))
+		params[name] = v
+		return "$" + name
+	}
+	if id := nmc.GetID(); id.IsValid() {
+		conjuncts = append(conjuncts, varName+"."+slnIDPropName+" = "+addParam(id.String()))
+	}
+	if t := nmc.GetType(); t.IsValid() {
+		conjuncts = append(conjuncts, varName+":"+label(t))
+	}
+	if !nmc.GetIncludeDeleted() {
+		conjuncts = append(conjuncts, varName+"."+slnDeletedPropName+" IS NULL")
+	}
+	pmc := nmc.GetPropMatchClause()
+	if pmc == nil {
+		return conjuncts, nil
+	}
+	if pmc.NotEqual().Len() > 0 || pmc.In().Len() > 0 ||
+		pmc.GreaterThan().Len() > 0 || pmc.GreaterEqual().Len() > 0 ||
+		pmc.LessThan().Len() > 0 || pmc.LessEqual().Len() > 0 ||
+		pmc.HasPrefix().Len() > 0 || pmc.Contains().Len() > 0 ||
+		pmc.EqualFold().Len() > 0 || pmc.Matches().Len() > 0 ||
+		pmc.PropType().Len() > 0 {
+		return nil, errors.AutoWrap(errCondNotTranslatable)
+	}
+	equal := pmc.Equal()
+	names := make([]gosln.PropName, 0, equal.Len())
+	equal.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
+		names = append(names, x.Key)
+		return true
+	})
+	sortPropNames(names)
+	for _, name := range names {
+		v, _ := equal.Get(name)
+		exact, ok := equalTranslation(v)
+		if !ok {
+			return nil, errors.AutoWrap(errCondNotTranslatable)
+		}
+		ref, p := propRef(varName, name), addParam(paramValue(v))
+		conjuncts = append(conjuncts, ref+" = "+p)
+		if !exact {
+			conjuncts = append(conjuncts, "toString("+ref+") = toString("+p+")")
+		}
+	}
+	for _, x := range []struct {
+		names gosln.PropNameSet
+		op    string
+	}{
+		{pmc.Present(), " IS NOT NULL"},
+		{pmc.Absent(), " IS NULL"},
+	} {
+		names = make([]gosln.PropName, 0, x.names.Len())
+		x.names.Range(func(name gosln.PropName) (cont bool) {
+			names = append(names, name)
+			return true
+		})
+		sortPropNames(names)
+		for _, name := range names {
+			conjuncts = append(conjuncts, propRef(varName, name)+x.op)
+		}
+	}
+	return conjuncts, nil
+}
+
+// equalTranslation reports whether the property value v in
+// the component Equal of a PropMatchClause can be translated into
+// the Cypher equality "var.`prop` = $p" (i.e., ok),
+// and whether the equality alone is exact (i.e., exact),
+// so that the database and the method Match of gosln.PropMatchClause
+// agree on the result.
+//
+// The method Match requires the values to be of the same Go type,
+// and the properties are compared as read back from the database
+// (see function propFromNeo4j).
+// Thus, v can be translated only if its Go type is
+// the type of the values read back from the database.
+// Neo4j considers an integer equal to a floating-point number
+// with the same value, so the equality is not exact
+// for the int64 and float64 values,
+// in which case the caller should also compare
+// their string representations to tell integers from floats.
+// The nonempty lists of numbers are not translated,
+// as Neo4j cannot tell their element types apart in the same way.
+// The empty lists are read back as empty []string,
+// so only an empty []string can be translated among the empty slices.
+func equalTranslation(v any) (exact, ok bool) {
+	switch x := v.(type) {
+	case bool, string, []byte, gosln.Date, time.Time:
+		return true, true
+	case int64, float64:
+		return false, true
+	case []bool:
+		return true, len(x) > 0
+	case []string:
+		return true, true
+	}
+	return false, false
+}
+
+// sameNumberKind reports whether the property values a and b
+// are both integers, both floating-point numbers, or neither,
+// or, if they are slices of scalars, whether their elements are.
+// An empty slice is considered of the same kind as any slice,
+// as Neo4j does not record the element type of an empty list.
+//
+// The values stored as Neo4j integers and floats can be told apart
+// only by their kinds, as Neo4j compares them by their values.
+func sameNumberKind(a, b any) bool {
+	pa, pb := gosln.PropTypeOf(a), gosln.PropTypeOf(b)
+	if pa.IsSlice() && pb.IsSlice() {
+		if reflect.ValueOf(a).Len() == 0 || reflect.ValueOf(b).Len() == 0 {
+			return true
+		}
+		pa, pb = pa.ElemType(), pb.ElemType()
+	}
+	return pa.IsInteger() == pb.IsInteger() && pa.IsFloat() == pb.IsFloat()
+}
+
+// sortPropNames sorts the property names in ascending order.
+func sortPropNames(names []gosln.PropName) {
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Compare(names[j]) < 0
+	})
+}
+
+// propRef returns a Cypher expression referring to
+// the property with the specified name on the variable varName.
+func propRef(varName string, name gosln.PropName) string {
+	return varName + ".`" + name.String() + "`"
+}
@@ -0,0 +1,279 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+)
+
+func TestBuildWhere(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	id := gosln.NewID(person, gosln.DateOfYearMonthDay(2023, 1, 2), 3)
+	name := gosln.MustNewPropName("name")
+	birthday := gosln.MustNewPropName("birthday")
+	email := gosln.MustNewPropName("email")
+	phone := gosln.MustNewPropName("phone")
+	date := gosln.DateOfYearMonthDay(1990, 5, 6)
+
+	typeAndProps := gosln.NewNodeMatchClause()
+	typeAndProps.SetType(person)
+	pmc := gosln.NewPropMatchClause(2, 1, 1)
+	pmc.Equal().Set(name, "Alice")
+	pmc.Equal().Set(birthday, date)
+	pmc.Present().Add(email)
+	pmc.Absent().Add(phone)
+	typeAndProps.SetPropMatchClause(pmc)
+
+	idIncludeDeleted := gosln.NewNodeMatchClause()
+	idIncludeDeleted.SetID(id)
+	idIncludeDeleted.SetIncludeDeleted(true)
+
+	anyNode := gosln.NewNodeMatchClause()
+	anyNode.SetIncludeDeleted(true)
+
+	numbers := gosln.NewNodeMatchClause()
+	pmc = gosln.NewPropMatchClause(2, 0, 0)
+	pmc.Equal().Set(gosln.MustNewPropName("age"), int64(30))
+	pmc.Equal().Set(gosln.MustNewPropName("score"), 2.5)
+	numbers.SetPropMatchClause(pmc)
+
+	testCases := []struct {
+		name       string
+		cond       gosln.NodeMatchCond
+		wantCypher string
+		wantParams map[string]any
+	}{
+		{
+			"nil",
+			nil,
+			"n.slnDeleted IS NULL",
+			map[string]any{},
+		},
+		{
+			"empty",
+			gosln.NodeMatchCond{},
+			"false",
+			map[string]any{},
+		},
+		{
+			"nil clause",
+			gosln.NodeMatchCond{nil},
+			"false",
+			map[string]any{},
+		},
+		{
+			"type and props",
+			gosln.NodeMatchCond{typeAndProps},
+			"(n:`Person` AND n.slnDeleted IS NULL AND n.`birthday` = $n_p0 AND n.`name` = $n_p1 AND " +
+				"n.`email` IS NOT NULL AND n.`phone` IS NULL)",
+			map[string]any{"n_p0": neo4j.DateOf(date.GoTime()), "n_p1": "Alice"},
+		},
+		{
+			"two clauses",
+			gosln.NodeMatchCond{typeAndProps, nil, idIncludeDeleted},
+			"(n:`Person` AND n.slnDeleted IS NULL AND n.`birthday` = $n_p0 AND n.`name` = $n_p1 AND " +
+				"n.`email` IS NOT NULL AND n.`phone` IS NULL) OR (n.slnID = $n_p2)",
+			map[string]any{"n_p0": neo4j.DateOf(date.GoTime()), "n_p1": "Alice", "n_p2": id.String()},
+		},
+		{
+			"numbers",
+			gosln.NodeMatchCond{numbers},
+			"(n.slnDeleted IS NULL AND n.`age` = $n_p0 AND toString(n.`age`) = toString($n_p0) AND " +
+				"n.`score` = $n_p1 AND toString(n.`score`) = toString($n_p1))",
+			map[string]any{"n_p0": int64(30), "n_p1": 2.5},
+		},
+		{
+			"any node",
+			gosln.NodeMatchCond{idIncludeDeleted, anyNode},
+			"true",
+			map[string]any{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cypher, params, err := buildWhere("n", tc.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cypher != tc.wantCypher {
+				t.Errorf("got Cypher %q; want %q", cypher, tc.wantCypher)
+			}
+			if len(params) != len(tc.wantParams) {
+				t.Errorf("got params %v; want %v", params, tc.wantParams)
+			}
+			for k, want := range tc.wantParams {
+				if got, ok := params[k]; !ok || got != want {
+					t.Errorf("got param %s %v (present: %t); want %v", k, got, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildWhere_Error(t *testing.T) {
+	age := gosln.MustNewPropName("age")
+	testCases := []struct {
+		name string
+		set  func(pmc gosln.PropMatchClause)
+	}{
+		{"greater than", func(pmc gosln.PropMatchClause) {
+			pmc.GreaterThan().Set(age, 18)
+		}},
+		{"equal int", func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(age, 30)
+		}},
+		{"equal float32", func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(age, float32(30))
+		}},
+		{"equal []int64", func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(age, []int64{30})
+		}},
+		{"equal empty []bool", func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(age, []bool{})
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nmc := gosln.NewNodeMatchClause()
+			pmc := gosln.NewPropMatchClause(1, 0, 0)
+			tc.set(pmc)
+			nmc.SetPropMatchClause(pmc)
+			_, _, err := buildWhere("n", gosln.NodeMatchCond{nmc})
+			if !errors.Is(err, errCondNotTranslatable) {
+				t.Errorf("got %v; want errCondNotTranslatable", err)
+			}
+		})
+	}
+	_, _, err := buildWhere("", nil)
+	if err == nil {
+		t.Error("empty variable name - got nil error")
+	}
+}
+
+func TestMakeParameterMap(t *testing.T) {
+	id := gosln.NewID(gosln.MustNewType("Person"), gosln.DateOfYearMonthDay(2023, 1, 2), 3)
+	dateName := gosln.MustNewPropName("birthday")
+	timeName := gosln.MustNewPropName("updatedAt")
+	bytesName := gosln.MustNewPropName("avatar")
+	intName := gosln.MustNewPropName("age")
+	removeName := gosln.MustNewPropName("phone")
+	date := gosln.DateOfYearMonthDay(1990, 5, 6)
+	tm := time.Date(2023, 7, 8, 9, 10, 11, 12, time.FixedZone("UTC+8", 8*3600))
+	avatar := []byte{1, 2, 3}
+
+	props := gosln.NewPropMap(4)
+	props.Set(dateName, date)
+	props.Set(timeName, tm)
+	props.Set(bytesName, avatar)
+	props.Set(intName, 30)
+	remove := gosln.NewPropNameSet(1)
+	remove.Add(removeName)
+
+	para, err := makeParameterMap("props", id, props, remove)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := para["props"].(map[string]any)
+	if !ok {
+		t.Fatalf("got %#v; want a map", para["props"])
+	}
+	if len(m) != 6 {
+		t.Errorf("got %d parameters; want 6", len(m))
+	}
+	if got := m[slnIDPropName]; got != id.String() {
+		t.Errorf("got ID %v; want %s", got, id)
+	}
+	if got, ok := m[dateName.String()].(neo4j.Date); !ok {
+		t.Errorf("got date %#v; want a neo4j.Date", m[dateName.String()])
+	} else if y, mo, d := got.Time().Date(); y != 1990 || mo != 5 || d != 6 {
+		t.Errorf("got date %v; want 1990-05-06", got)
+	}
+	if got, ok := m[timeName.String()].(neo4j.LocalDateTime); !ok {
+		t.Errorf("got time %#v; want a neo4j.LocalDateTime", m[timeName.String()])
+	} else {
+		gt, want := got.Time(), tm.UTC()
+		if gt.Year() != want.Year() || gt.YearDay() != want.YearDay() ||
+			gt.Hour() != want.Hour() || gt.Minute() != want.Minute() ||
+			gt.Second() != want.Second() || gt.Nanosecond() != want.Nanosecond() {
+			t.Errorf("got time %v; want %v", gt, want)
+		}
+	}
+	if got, ok := m[bytesName.String()].([]byte); !ok || !bytes.Equal(got, avatar) {
+		t.Errorf("got bytes %#v; want %v", m[bytesName.String()], avatar)
+	}
+	if got := m[intName.String()]; got != 30 {
+		t.Errorf("got int %#v; want 30", got)
+	}
+	if got, ok := m[removeName.String()]; !ok || got != nil {
+		t.Errorf("got removed %#v (present: %t); want nil", got, ok)
+	}
+}
+
+func TestMakeParameterMap_Empty(t *testing.T) {
+	para, err := makeParameterMap("props", gosln.ID{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := para["props"]; len(para) != 1 || !ok || v != nil {
+		t.Errorf("got %#v; want map[props:<nil>]", para)
+	}
+	_, err = makeParameterMap("", gosln.ID{}, nil, nil)
+	if err == nil {
+		t.Error("empty parameter name - got nil error")
+	}
+}
+
+func TestTypeStringsAndTypeAllowed(t *testing.T) {
+	a, b, c := gosln.MustNewType("A"), gosln.MustNewType("B"), gosln.MustNewType("C")
+	ts := gosln.NewTypeSet(2)
+	ts.Add(b, a)
+	strs := typeStrings(ts)
+	if len(strs) != 2 || strs[0] != "A" || strs[1] != "B" {
+		t.Errorf("got %q; want [A B]", strs)
+	}
+	if strs = typeStrings(gosln.NewTypeSet(0)); strs != nil {
+		t.Errorf("got %q for empty set; want nil", strs)
+	}
+
+	list := []any{"A", "B"} // as returned by the Neo4j driver
+	testCases := []struct {
+		v    any
+		t    gosln.Type
+		want bool
+	}{
+		{list, a, true},
+		{list, b, true},
+		{list, c, false},
+		{nil, c, true},
+		{[]any{}, c, true},
+	}
+	for _, tc := range testCases {
+		if got := typeAllowed(tc.v, tc.t); got != tc.want {
+			t.Errorf("typeAllowed(%v, %v) = %t; want %t", tc.v, tc.t, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/donyori/gogo/errors"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/notify"
+	"github.com/donyori/gosln/memsln"
+)
+
+// session is the part of neo4j.SessionWithContext used by neo4jSLN.
+type session interface {
+	ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork,
+		configurers ...func(*neo4j.TransactionConfig)) (any, error)
+	ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork,
+		configurers ...func(*neo4j.TransactionConfig)) (any, error)
+	BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (
+		neo4j.ExplicitTransaction, error)
+	Close(ctx context.Context) error
+}
+
+// runner runs Cypher queries.
+//
+// It is implemented by neo4j.ManagedTransaction
+// and neo4j.ExplicitTransaction.
+type runner interface {
+	Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error)
+}
+
+// neo4jSLN is an implementation of interface gosln.SLN
+// backed by a Neo4j database.
+type neo4jSLN struct {
+	// mu is read-locked during each operation,
+	// so that Close can wait for the in-flight operations.
+	mu     sync.RWMutex
+	closed bool
+
+	// newSession opens a new session in the specified access mode.
+	newSession func(ctx context.Context, mode neo4j.AccessMode) session
+
+	// maxRetries and retryBackoff are Config.MaxRetries
+	// and Config.RetryBackoff.
+	maxRetries   int
+	retryBackoff time.Duration
+
+	// notifier dispatches the events to the observers.
+	notifier *notify.Notifier
+}
+
+var _ gosln.SLN = (*neo4jSLN)(nil)
+
+// New creates an SLN backed by the Neo4j database
+// that driver connects to.
+//
+// The SLN maps each semantic node to a Neo4j node
+// and each semantic link to a Neo4j relationship.
+// The type of the node or link is used as the label of the Neo4j node
+// or the type of the Neo4j relationship,
+// and the ID is stored in the property "slnID".
+// The last serial used in the IDs of each type is recorded
+// in a Neo4j node labeled "SLNSerial",
+// and the constraint registered by RegisterLinkConstraint
+// for each link type is recorded
+// in a Neo4j node labeled "SLNLinkConstraint".
+// The client is recommended to create a uniqueness constraint on
+// the property "type" of the nodes labeled "SLNSerial"
+// and an index on the property "slnID",
+// which New does not do.
+//
+// The returned SLN follows the documentation of gosln.SLN,
+// with the following details:
+//   - The node match conditions are translated into Cypher
+//     to filter the nodes in the database,
+//     if they specify nothing other than the node IDs, node types,
+//     whether to include the soft-removed nodes,
+//     and the components Equal, Present, and Absent of
+//     the property match conditions,
+//     where the values in Equal are of the types read back
+//     from the database (see below) other than nonempty lists of numbers.
+//     The translated conditions give the same result as
+//     evaluating them on the client side.
+//     The other match conditions are evaluated on the client side
+//     after fetching all nodes or links from the database.
+//   - The nodes and links returned by GetAllNodes and GetAllLinks
+//     are sorted in ascending order of their IDs,
+//     as are the neighbors in a Neighborhood and a GroupedNode.
+//   - The property values are those returned by the Neo4j driver,
+//     except for the temporal values.
+//     The gosln.Date values are stored as Neo4j dates,
+//     and the time.Time values are stored as Neo4j local date-times in UTC,
+//     so they are read back as gosln.Date and time.Time (in UTC).
+//     In particular, the integers are of type int64
+//     and the floating-point numbers are of type float64
+//     unless the property types specify otherwise.
+//     Similarly, the slices of scalars are stored as Neo4j lists
+//     and read back as []bool, []int64, []float64, or []string,
+//     where an empty list is read back as an empty []string.
+//   - A property is converted to the type specified in the property types
+//     only if the conversion is between numeric types, between byte strings,
+//     between time.Time and gosln.Date, or between slices of numbers,
+//     or the property is an empty slice.
+//   - The From and To nodes of the links returned by
+//     GetLinkByID, GetAllLinks, and the mutating methods
+//     carry no properties.
+//   - The links whose other end has been soft-removed
+//     are excluded from the results of GetNeighborhood and
+//     GetNodeWithGroupedNeighbors.
+//   - The methods NumNodeType, NumLinkType, GetNodeTypes, and GetLinkTypes
+//     take the soft-removed nodes into account.
+//   - The methods SetNodeProperties and MutateNodeProperties report
+//     a *gosln.NodeNotExistError if the node has been soft-removed.
+//   - The observers are notified on a dedicated goroutine
+//     after the Neo4j transaction is committed,
+//     in the order in which the operations return.
+//     The changes made by other clients of the database are not notified.
+//     Close waits for the pending notifications to be delivered.
+//   - An operation in progress is interrupted when the context is done,
+//     as the Neo4j driver aborts the transaction,
+//     in which case the transaction is rolled back.
+//   - Snapshot loads all nodes and links into the memory
+//     in a single read transaction,
+//     costing time and memory proportional to the size of the network.
+//   - The write operations that fail with a retryable error are retried
+//     as specified by cfg.MaxRetries and cfg.RetryBackoff.
+//     After the retries are exhausted,
+//     the error of the last attempt is returned.
+//   - The transactions returned by BeginTx are Neo4j explicit transactions.
+//     If an operation in such a transaction fails,
+//     the transaction may become unusable and should be rolled back.
+//
+// Close does not close driver.
+// The client should close driver after closing the SLN.
+func New(driver neo4j.DriverWithContext, cfg Config) gosln.SLN {
+	return &neo4jSLN{
+		newSession: func(ctx context.Context, mode neo4j.AccessMode) session {
+			return driver.NewSession(ctx, neo4j.SessionConfig{
+				AccessMode:   mode,
+				DatabaseName: cfg.DatabaseName,
+			})
+		},
+		maxRetries:   cfg.MaxRetries,
+		retryBackoff: cfg.RetryBackoff,
+		notifier:     notify.New(),
+	}
+}
+
+func (s *neo4jSLN) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.notifier.Close()
+	return nil
+}
+
+func (s *neo4jSLN) Closed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closed
+}
+
+func (s *neo4jSLN) RegisterObserver(obs gosln.Observer) (unregister func()) {
+	return s.notifier.Register(obs)
+}
+
+func (s *neo4jSLN) Snapshot(ctx context.Context) (snap gosln.ReadOnlySLN, err error) {
+	g, err := s.loadGraph(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	snap, err = memsln.NewSnapshot(g.nodes, g.links)
+	return snap, errors.AutoWrap(err)
+}
+
+// rLock checks ctx and acquires the read lock of s.
+//
+// If ctx is done or s is closed, it returns an error
+// without holding the lock.
+// Otherwise, it returns nil, and the caller should release the lock.
+func (s *neo4jSLN) rLock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return gosln.ErrSLNClosed
+	}
+	return nil
+}
+
+// read runs work in a Neo4j managed read transaction
+// and returns its result.
+func read[T any](ctx context.Context, s *neo4jSLN, work func(run runner) (T, error)) (
+	result T, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return
+	}
+	defer s.mu.RUnlock()
+	sess := s.newSession(ctx, neo4j.AccessModeRead)
+	defer closeSession(ctx, sess, &err)
+	v, err := sess.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return work(tx)
+	})
+	if err != nil {
+		return
+	}
+	result, _ = v.(T)
+	return
+}
+
+// write runs work in a Neo4j managed write transaction
+// and returns its result.
+//
+// If the transaction fails with a retryable error,
+// including a *neo4j.TransactionExecutionLimit whose last error
+// is retryable (which the driver returns after its own retries),
+// write retries it at most s.maxRetries times,
+// waiting s.retryBackoff before the first retry
+// and doubling the waiting time after each retry.
+//
+// After the transaction is committed,
+// it pushes the events emitted by work to the notifier of s.
+func write[T any](ctx context.Context, s *neo4jSLN, work func(w *writer) (T, error)) (
+	result T, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return
+	}
+	defer s.mu.RUnlock()
+	sess := s.newSession(ctx, neo4j.AccessModeWrite)
+	defer closeSession(ctx, sess, &err)
+	var events []notify.Event
+	txWork := func(tx neo4j.ManagedTransaction) (any, error) {
+		// The transaction may be retried, so use a new writer for each attempt.
+		w := &writer{ctx: ctx, s: s, run: tx}
+		r, err := work(w)
+		events = w.events
+		return r, err
+	}
+	v, err := sess.ExecuteWrite(ctx, txWork)
+	var retries int
+	for backoff := s.retryBackoff; retries < s.maxRetries && isRetryable(err); retries++ {
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				err = errors.AutoWrap(errors.Join(
+					err,
+					errors.AutoNew(fmt.Sprintf("retry canceled after %d retries", retries)),
+					ctx.Err(),
+				))
+				return
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+		v, err = sess.ExecuteWrite(ctx, txWork)
+	}
+	if err != nil {
+		if retries > 0 {
+			err = errors.Join(
+				errors.AutoNew(fmt.Sprintf("write failed after %d retries", retries)),
+				err,
+			)
+		}
+		return
+	}
+	s.notifier.Push(events...)
+	result, _ = v.(T)
+	return
+}
+
+// isRetryable reports whether a write transaction failed with err
+// can be retried.
+//
+// The Neo4j driver reports a transaction that still fails after its own
+// retries as a *neo4j.TransactionExecutionLimit,
+// which does not unwrap to the errors it has encountered.
+// In this case, isRetryable checks the last of these errors.
+func isRetryable(err error) bool {
+	var tel *neo4j.TransactionExecutionLimit
+	if errors.As(err, &tel) {
+		if len(tel.Errors) == 0 {
+			return false
+		}
+		err = tel.Errors[len(tel.Errors)-1]
+	}
+	return neo4j.IsRetryable(err)
+}
+
+// closeSession closes sess.
+//
+// If *err is nil, closeSession sets it to the error encountered.
+func closeSession(ctx context.Context, sess session, err *error) {
+	closeErr := sess.Close(ctx)
+	if *err == nil && closeErr != nil {
+		*err = closeErr
+	}
+}
@@ -0,0 +1,274 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/notify"
+)
+
+// fakeSession is a session whose ExecuteWrite fails
+// with the errors in errs one by one before running the work.
+// As with the Neo4j driver, a transient failure should be reported
+// as a *neo4j.TransactionExecutionLimit (see transientLimit).
+//
+// The Cypher queries run in its transactions are recorded in queries
+// and answered by run.
+type fakeSession struct {
+	session // The methods other than ExecuteRead, ExecuteWrite, and Close are not used.
+
+	errs  []error
+	calls int
+
+	run     func(cypher string, params map[string]any) ([]*neo4j.Record, error)
+	queries []string
+}
+
+func (fs *fakeSession) ExecuteRead(_ context.Context, work neo4j.ManagedTransactionWork,
+	_ ...func(*neo4j.TransactionConfig)) (any, error) {
+	return work(&fakeTx{fs: fs})
+}
+
+func (fs *fakeSession) ExecuteWrite(_ context.Context, work neo4j.ManagedTransactionWork,
+	_ ...func(*neo4j.TransactionConfig)) (any, error) {
+	fs.calls++
+	if fs.calls <= len(fs.errs) {
+		return nil, fs.errs[fs.calls-1]
+	}
+	return work(&fakeTx{fs: fs})
+}
+
+func (fs *fakeSession) Close(context.Context) error {
+	return nil
+}
+
+// fakeTx is a transaction that runs the Cypher queries by its fakeSession.
+type fakeTx struct {
+	neo4j.ManagedTransaction // The methods other than Run are not used.
+
+	fs *fakeSession
+}
+
+func (tx *fakeTx) Run(_ context.Context, cypher string, params map[string]any) (
+	neo4j.ResultWithContext, error) {
+	tx.fs.queries = append(tx.fs.queries, cypher)
+	records, err := tx.fs.run(cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeResult{records: records}, nil
+}
+
+// fakeResult is a result holding the specified records.
+type fakeResult struct {
+	neo4j.ResultWithContext // The methods other than Collect and Single are not used.
+
+	records []*neo4j.Record
+}
+
+func (r *fakeResult) Collect(context.Context) ([]*neo4j.Record, error) {
+	return r.records, nil
+}
+
+func (r *fakeResult) Single(context.Context) (*neo4j.Record, error) {
+	if len(r.records) != 1 {
+		return nil, fmt.Errorf("got %d records; want exactly one", len(r.records))
+	}
+	return r.records[0], nil
+}
+
+// nodeRecords returns a run function for fakeSession
+// that answers every query with the Neo4j nodes
+// holding the specified properties, regardless of the conditions.
+func nodeRecords(props ...map[string]any) func(string, map[string]any) ([]*neo4j.Record, error) {
+	return func(string, map[string]any) ([]*neo4j.Record, error) {
+		records := make([]*neo4j.Record, len(props))
+		for i := range props {
+			records[i] = &neo4j.Record{
+				Keys:   []string{"n"},
+				Values: []any{neo4j.Node{Props: props[i]}},
+			}
+		}
+		return records, nil
+	}
+}
+
+// transientLimit returns the error that the Neo4j driver returns
+// from ExecuteWrite after the transaction keeps failing
+// with a transient error until the maximum retry time expires.
+func transientLimit() *neo4j.TransactionExecutionLimit {
+	transientErr := &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}
+	return &neo4j.TransactionExecutionLimit{
+		Errors: []error{transientErr, transientErr},
+		Causes: []string{"timeout (exceeded max retry time: 30s)"},
+	}
+}
+
+func TestWrite_Retry(t *testing.T) {
+	transientErr := transientLimit()
+	deadlockErr := &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}
+	clientLimitErr := &neo4j.TransactionExecutionLimit{
+		Errors: []error{deadlockErr, &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}},
+		Causes: []string{"timeout (exceeded max retry time: 30s)"},
+	}
+	emptyLimitErr := new(neo4j.TransactionExecutionLimit)
+	clientErr := &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}
+	testCases := []struct {
+		name       string
+		errs       []error
+		maxRetries int
+		wantCalls  int
+		wantErr    error
+	}{
+		{"no errors", nil, 0, 1, nil},
+		{"fail twice-retry twice", []error{transientErr, transientErr}, 2, 3, nil},
+		{"fail twice-retry thrice", []error{transientErr, transientErr}, 3, 3, nil},
+		{"fail twice-retry once", []error{transientErr, transientErr}, 1, 2, transientErr},
+		{"fail twice-no retries", []error{transientErr, transientErr}, 0, 1, transientErr},
+		{"non-retryable", []error{clientErr, transientErr}, 2, 1, clientErr},
+		{"non-retryable after retry", []error{transientErr, clientErr}, 2, 2, clientErr},
+		{"unwrapped transient", []error{deadlockErr}, 1, 2, nil},
+		{"limit ending non-retryable", []error{clientLimitErr, transientErr}, 2, 1, clientLimitErr},
+		{"empty limit", []error{emptyLimitErr}, 2, 1, emptyLimitErr},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := &fakeSession{errs: tc.errs}
+			s := newFakeSLN(fs, tc.maxRetries, time.Millisecond)
+			defer func() {
+				if err := s.Close(); err != nil {
+					t.Error("close -", err)
+				}
+			}()
+			r, err := write(context.Background(), s, func(w *writer) (int, error) {
+				return 42, nil
+			})
+			if tc.wantErr == nil {
+				if err != nil {
+					t.Error(err)
+				} else if r != 42 {
+					t.Errorf("got %d; want 42", r)
+				}
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Errorf("got error %v; want %v", err, tc.wantErr)
+			}
+			if fs.calls != tc.wantCalls {
+				t.Errorf("got %d calls; want %d", fs.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWrite_RetryCanceled(t *testing.T) {
+	transientErr := transientLimit()
+	fs := &fakeSession{errs: []error{transientErr, transientErr}}
+	s := newFakeSLN(fs, 2, time.Hour)
+	defer func() {
+		if err := s.Close(); err != nil {
+			t.Error("close -", err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := write(ctx, s, func(w *writer) (int, error) {
+		return 42, nil
+	})
+	if !errors.Is(err, transientErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v; want both %v and %v", err, transientErr, context.DeadlineExceeded)
+	}
+	if fs.calls != 1 {
+		t.Errorf("got %d calls; want 1", fs.calls)
+	}
+}
+
+// newFakeSLN returns a neo4jSLN that always uses sess.
+func newFakeSLN(sess session, maxRetries int, retryBackoff time.Duration) *neo4jSLN {
+	return &neo4jSLN{
+		newSession: func(context.Context, neo4j.AccessMode) session {
+			return sess
+		},
+		maxRetries:   maxRetries,
+		retryBackoff: retryBackoff,
+		notifier:     notify.New(),
+	}
+}
+
+func TestGetAllNodes_EqualTranslation(t *testing.T) {
+	ctx := context.Background()
+	id := gosln.NewID(gosln.MustNewType("Person"), gosln.DateOfYearMonthDay(2023, 1, 2), 3)
+	age := gosln.MustNewPropName("age")
+	testCases := []struct {
+		name         string
+		value        any
+		wantMatch    bool
+		wantPushDown bool
+	}{
+		// The driver reads back every integer as int64,
+		// so an int value matches nothing on the client side,
+		// and it must not be translated into Cypher,
+		// where it would match the node.
+		{"int", 30, false, false},
+		{"int64", int64(30), true, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The fake database does not evaluate the conditions
+			// and returns the node for any query.
+			fs := &fakeSession{run: nodeRecords(map[string]any{slnIDPropName: id.String(), "age": int64(30)})}
+			s := newFakeSLN(fs, 0, 0)
+			defer func() {
+				if err := s.Close(); err != nil {
+					t.Error("close -", err)
+				}
+			}()
+			// onlyEqual is translated into Cypher if the value can be,
+			// while withGT is always evaluated on the client side.
+			onlyEqual := gosln.NewPropMatchClause(1, 0, 0)
+			onlyEqual.Equal().Set(age, tc.value)
+			withGT := gosln.NewPropMatchClause(1, 0, 0)
+			withGT.Equal().Set(age, tc.value)
+			withGT.GreaterThan().Set(age, int64(0))
+			for i, pmc := range []gosln.PropMatchClause{onlyEqual, withGT} {
+				nmc := gosln.NewNodeMatchClause()
+				nmc.SetPropMatchClause(pmc)
+				fs.queries = nil
+				nodes, err := s.GetAllNodes(ctx, nil, gosln.NodeMatchCond{nmc})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := len(nodes) == 1 && nodes[0].ID == id; got != tc.wantMatch || len(nodes) > 1 {
+					t.Errorf("clause %d, got %d nodes; want match %t", i, len(nodes), tc.wantMatch)
+				}
+				pushedDown := len(fs.queries) == 1 && fs.queries[0] != matchAllNodes
+				if want := tc.wantPushDown && i == 0; pushedDown != want {
+					t.Errorf("clause %d, got queries %q; want pushed down %t", i, fs.queries, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,745 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/propconv"
+)
+
+// Cypher queries used by the read operations.
+const (
+	matchNodeTypes     = "MATCH (n) WHERE n.slnID IS NOT NULL UNWIND labels(n) AS t RETURN DISTINCT t"
+	matchLinkTypes     = "MATCH ()-[r]->() WHERE r.slnID IS NOT NULL RETURN DISTINCT type(r)"
+	matchNodeByID      = "MATCH (n {slnID: $id}) RETURN n"
+	matchNodesByIDs    = "MATCH (n) WHERE n.slnID IN $ids RETURN n"
+	matchLinkByID      = "MATCH (a)-[r {slnID: $id}]->(b) RETURN r, a, b"
+	matchIncidentLinks = "MATCH ({slnID: $id})-[r]-() WHERE r.slnID IS NOT NULL " +
+		"WITH DISTINCT r RETURN r, startNode(r), endNode(r)"
+)
+
+// matchLiveNodesOfType returns a Cypher query that matches
+// the nodes of type t that have not been soft-removed.
+//
+// The caller should guarantee that t is valid.
+func matchLiveNodesOfType(t gosln.Type) string {
+	return "MATCH (n:" + label(t) + ") WHERE n.slnID IS NOT NULL AND n.slnDeleted IS NULL RETURN n"
+}
+
+func (s *neo4jSLN) NumNodeType(ctx context.Context) (n int, err error) {
+	types, err := s.GetNodeTypes(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	return len(types), nil
+}
+
+func (s *neo4jSLN) NumLinkType(ctx context.Context) (n int, err error) {
+	types, err := s.GetLinkTypes(ctx)
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	return len(types), nil
+}
+
+func (s *neo4jSLN) NumNode(ctx context.Context, cond gosln.NodeMatchCond) (n int, err error) {
+	n, err = s.countMatchedNodes(ctx, cond, 0)
+	return n, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) ExistsAtLeast(ctx context.Context, cond gosln.NodeMatchCond, k int) (ok bool, err error) {
+	if k <= 0 {
+		// Check ctx and whether s is closed.
+		_, err = read(ctx, s, func(runner) (struct{}, error) {
+			return struct{}{}, nil
+		})
+		return err == nil, errors.AutoWrap(err)
+	}
+	n, err := s.countMatchedNodes(ctx, cond, k)
+	if err != nil {
+		return false, errors.AutoWrap(err)
+	}
+	return n >= k, nil
+}
+
+func (s *neo4jSLN) NumLink(ctx context.Context, cond gosln.LinkMatchCond) (n int, err error) {
+	links, err := read(ctx, s, func(run runner) ([]*gosln.Link, error) {
+		return s.queryLinks(ctx, run, matchAllLinks, nil)
+	})
+	if err != nil {
+		return 0, errors.AutoWrap(err)
+	}
+	for _, link := range links {
+		if cond.Match(link) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *neo4jSLN) GetNodeTypes(ctx context.Context) (types []gosln.Type, err error) {
+	types, err = read(ctx, s, func(run runner) ([]gosln.Type, error) {
+		return queryTypes(ctx, run, matchNodeTypes)
+	})
+	return types, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) GetLinkTypes(ctx context.Context) (types []gosln.Type, err error) {
+	types, err = read(ctx, s, func(run runner) ([]gosln.Type, error) {
+		return queryTypes(ctx, run, matchLinkTypes)
+	})
+	return types, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) InferSchema(ctx context.Context) (schema *gosln.Schema, err error) {
+	g, err := s.loadGraph(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	schema = gosln.NewSchema()
+	for _, node := range g.nodes {
+		if !node.Deleted {
+			schema.AddNode(node)
+		}
+	}
+	for _, link := range g.links {
+		schema.AddLink(link)
+	}
+	return schema, nil
+}
+
+func (s *neo4jSLN) GetCommonPropertyNames(ctx context.Context, t gosln.Type) (names gosln.PropNameSet, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	nodes, err := read(ctx, s, func(run runner) ([]*gosln.Node, error) {
+		return s.queryNodes(ctx, run, matchLiveNodesOfType(t), nil)
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	names = gosln.NewPropNameSet(0)
+	for i, node := range nodes {
+		if i == 0 {
+			node.Props.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
+				names.Add(x.Key)
+				return true
+			})
+		} else {
+			names.Filter(func(x gosln.PropName) (keep bool) {
+				_, present := node.Props.Get(x)
+				return present
+			})
+		}
+		if names.Len() == 0 {
+			break
+		}
+	}
+	return names, nil
+}
+
+func (s *neo4jSLN) GetNodeByID(ctx context.Context, id gosln.ID, propTypes gosln.PropTypeMap) (
+	node *gosln.Node, err error) {
+	node, err = read(ctx, s, func(run runner) (*gosln.Node, error) {
+		return s.getNode(ctx, run, id)
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	} else if node == nil || node.Deleted {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	node, err = exportNode(node, propTypes)
+	return node, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) GetNodesByIDs(ctx context.Context, ids []gosln.ID, propTypes gosln.PropTypeMap) (
+	nodes map[gosln.ID]*gosln.Node, err error) {
+	idStrs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id.IsValid() {
+			idStrs = append(idStrs, id.String())
+		}
+	}
+	var fetched []*gosln.Node
+	if len(idStrs) > 0 {
+		fetched, err = read(ctx, s, func(run runner) ([]*gosln.Node, error) {
+			return s.queryNodes(ctx, run, matchNodesByIDs, map[string]any{"ids": idStrs})
+		})
+	} else {
+		// Check ctx and whether s is closed.
+		_, err = read(ctx, s, func(runner) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	}
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	nodes = make(map[gosln.ID]*gosln.Node, len(fetched))
+	for _, node := range fetched {
+		if node.Deleted {
+			continue
+		}
+		nodes[node.ID], err = exportNode(node, propTypes)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return nodes, nil
+}
+
+func (s *neo4jSLN) GetLinkByID(ctx context.Context, id gosln.ID, propTypes gosln.PropTypeMap) (
+	link *gosln.Link, err error) {
+	link, err = read(ctx, s, func(run runner) (*gosln.Link, error) {
+		return s.getLink(ctx, run, id)
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	} else if link == nil {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	link, err = exportLink(link, propTypes, nil, nil)
+	return link, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) GetAllNodes(ctx context.Context, propTypes gosln.PropTypeMap, cond gosln.NodeMatchCond) (
+	nodes []*gosln.Node, err error) {
+	nodes, err = s.matchNodes(ctx, cond)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	sortNodes(nodes)
+	for i := range nodes {
+		nodes[i], err = exportNode(nodes[i], propTypes)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return nodes, nil
+}
+
+func (s *neo4jSLN) GetAllLinks(ctx context.Context, propTypes gosln.PropTypeMap, cond gosln.LinkMatchCond) (
+	links []*gosln.Link, err error) {
+	links, err = s.matchLinks(ctx, cond)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	sortLinks(links)
+	for i := range links {
+		links[i], err = exportLink(links[i], propTypes, nil, nil)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return links, nil
+}
+
+func (s *neo4jSLN) GetNodesPage(
+	ctx context.Context,
+	propTypes gosln.PropTypeMap,
+	cond gosln.NodeMatchCond,
+	page gosln.Page,
+) (nodes []*gosln.Node, total int, err error) {
+	matched, err := s.matchNodes(ctx, cond)
+	if err != nil {
+		return nil, 0, errors.AutoWrap(err)
+	}
+	total = len(matched)
+	// Page on the raw nodes, as the sort key
+	// may be absent from propTypes.
+	matched = gosln.PageNodes(matched, page)
+	nodes = make([]*gosln.Node, len(matched))
+	for i, node := range matched {
+		nodes[i], err = exportNode(node, propTypes)
+		if err != nil {
+			return nil, 0, errors.AutoWrap(err)
+		}
+	}
+	return nodes, total, nil
+}
+
+func (s *neo4jSLN) GetLinksPage(
+	ctx context.Context,
+	propTypes gosln.PropTypeMap,
+	cond gosln.LinkMatchCond,
+	page gosln.Page,
+) (links []*gosln.Link, total int, err error) {
+	matched, err := s.matchLinks(ctx, cond)
+	if err != nil {
+		return nil, 0, errors.AutoWrap(err)
+	}
+	total = len(matched)
+	matched = gosln.PageLinks(matched, page)
+	links = make([]*gosln.Link, len(matched))
+	for i, link := range matched {
+		links[i], err = exportLink(link, propTypes, nil, nil)
+		if err != nil {
+			return nil, 0, errors.AutoWrap(err)
+		}
+	}
+	return links, total, nil
+}
+
+func (s *neo4jSLN) GetNeighborhood(ctx context.Context, id gosln.ID, opts gosln.NeighborhoodOptions) (
+	neighborhood *gosln.Neighborhood, err error) {
+	center, links, err := s.getNodeAndIncidentLinks(ctx, id, opts)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	neighborhood = &gosln.Neighborhood{Links: make([]*gosln.Link, len(links))}
+	neighborhood.Center, err = exportNode(center, opts.CenterPropTypes)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	neighbors := make(map[gosln.ID]*gosln.Node)
+	for i, link := range links {
+		otherNode := neighborhood.Center
+		if other := otherEnd(link, id); other.ID != id {
+			otherNode = neighbors[other.ID]
+			if otherNode == nil {
+				otherNode, err = exportNode(other, opts.NeighborPropTypes)
+				if err != nil {
+					return nil, errors.AutoWrap(err)
+				}
+				neighbors[other.ID] = otherNode
+				neighborhood.Neighbors = append(neighborhood.Neighbors, otherNode)
+			}
+		}
+		from, to := neighborhood.Center, otherNode
+		if link.From.ID != id {
+			from, to = to, from
+		}
+		neighborhood.Links[i], err = exportLink(link, opts.LinkPropTypes, from, to)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	sortNodes(neighborhood.Neighbors)
+	return neighborhood, nil
+}
+
+func (s *neo4jSLN) GetLinksByNode(
+	ctx context.Context,
+	id gosln.ID,
+	dir gosln.Direction,
+	propTypes gosln.PropTypeMap,
+	cond gosln.LinkMatchCond,
+) (links []*gosln.Link, err error) {
+	_, links, err = s.getNodeAndIncidentLinks(
+		ctx, id, gosln.NeighborhoodOptions{Direction: dir, LinkCond: cond})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	for i := range links {
+		links[i], err = exportLink(links[i], propTypes, nil, nil)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+	}
+	return links, nil
+}
+
+func (s *neo4jSLN) GetNodeWithGroupedNeighbors(
+	ctx context.Context,
+	id gosln.ID,
+	linkTypes gosln.TypeSet,
+	opts gosln.NeighborhoodOptions,
+) (groupedNode *gosln.GroupedNode, err error) {
+	center, links, err := s.getNodeAndIncidentLinks(ctx, id, opts)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	groupedNode = &gosln.GroupedNode{Neighbors: make(map[gosln.Type][]*gosln.Node)}
+	groupedNode.Center, err = exportNode(center, opts.CenterPropTypes)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	if linkTypes != nil {
+		linkTypes.Range(func(x gosln.Type) (cont bool) {
+			groupedNode.Neighbors[x] = make([]*gosln.Node, 0)
+			return true
+		})
+	}
+	neighbors := make(map[gosln.ID]*gosln.Node)
+	grouped := make(map[gosln.Type]map[gosln.ID]struct{})
+	for _, link := range links {
+		other := otherEnd(link, id)
+		if other.ID == id || linkTypes != nil && !linkTypes.ContainsItem(link.Type) {
+			continue
+		}
+		group := grouped[link.Type]
+		if group == nil {
+			group = make(map[gosln.ID]struct{})
+			grouped[link.Type] = group
+		} else if _, ok := group[other.ID]; ok {
+			continue
+		}
+		group[other.ID] = struct{}{}
+		node := neighbors[other.ID]
+		if node == nil {
+			node, err = exportNode(other, opts.NeighborPropTypes)
+			if err != nil {
+				return nil, errors.AutoWrap(err)
+			}
+			neighbors[other.ID] = node
+		}
+		groupedNode.Neighbors[link.Type] = append(groupedNode.Neighbors[link.Type], node)
+	}
+	for _, nodes := range groupedNode.Neighbors {
+		sortNodes(nodes)
+	}
+	return groupedNode, nil
+}
+
+func (s *neo4jSLN) DistinctPropValues(ctx context.Context, t gosln.Type, name gosln.PropName, pt gosln.PropType) (
+	values []any, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	} else if !pt.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidPropTypeError(pt))
+	}
+	nodes, err := read(ctx, s, func(run runner) ([]*gosln.Node, error) {
+		return s.queryNodes(ctx, run, matchLiveNodesOfType(t), nil)
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	sortNodes(nodes)
+	seen := make(map[any]struct{})
+	values = make([]any, 0)
+	for _, node := range nodes {
+		v, present := node.Props.Get(name)
+		if !present {
+			continue
+		}
+		v, err = propconv.ConvertValue(name, v, pt)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		key := propconv.DistinctKey(v)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func (s *neo4jSLN) DegreeDistribution(ctx context.Context, direction gosln.Direction, cond gosln.LinkMatchCond) (
+	dist map[int]int, err error) {
+	g, err := s.loadGraph(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	if !direction.IsValid() {
+		direction = gosln.DirBoth
+	}
+	degrees := make(map[gosln.ID]int, len(g.nodes))
+	for _, link := range g.links {
+		if link.From.Deleted || link.To.Deleted || !cond.Match(link) {
+			continue
+		}
+		if direction != gosln.DirIncoming {
+			degrees[link.From.ID]++
+		}
+		if direction != gosln.DirOutgoing {
+			degrees[link.To.ID]++
+		}
+	}
+	dist = make(map[int]int)
+	for _, node := range g.nodes {
+		if !node.Deleted {
+			dist[degrees[node.ID]]++
+		}
+	}
+	return dist, nil
+}
+
+func (s *neo4jSLN) AggregateNumeric(ctx context.Context, t gosln.Type, name gosln.PropName) (
+	min, max, sum float64, count int, err error) {
+	var nodes []*gosln.Node
+	if t.IsValid() {
+		nodes, err = read(ctx, s, func(run runner) ([]*gosln.Node, error) {
+			return s.queryNodes(ctx, run, matchLiveNodesOfType(t), nil)
+		})
+	} else {
+		// No node is of an invalid type.
+		// Check ctx and whether s is closed.
+		_, err = read(ctx, s, func(runner) (struct{}, error) {
+			return struct{}{}, nil
+		})
+	}
+	if err != nil {
+		return 0, 0, 0, 0, errors.AutoWrap(err)
+	}
+	// Add the values in ascending order of the node IDs
+	// so that the sum is deterministic.
+	sortNodes(nodes)
+	for _, node := range nodes {
+		v, present := node.Props.Get(name)
+		if !present {
+			continue
+		}
+		if !gosln.PropTypeOf(v).IsRealNumber() {
+			return 0, 0, 0, 0, errors.AutoWrap(gosln.NewPropTypeError(
+				name, v, reflect.TypeOf(float64(0))))
+		}
+		x := propconv.RealNumberToFloat64(reflect.ValueOf(v))
+		if count == 0 || x < min {
+			min = x
+		}
+		if count == 0 || x > max {
+			max = x
+		}
+		sum += x
+		count++
+	}
+	return
+}
+
+// graph consists of all nodes and links in an SLN,
+// with all properties on them.
+type graph struct {
+	nodes []*gosln.Node
+	links []*gosln.Link
+}
+
+// loadGraph loads all nodes (including the soft-removed nodes)
+// and links in s in a single read transaction.
+func (s *neo4jSLN) loadGraph(ctx context.Context) (g graph, err error) {
+	return read(ctx, s, func(run runner) (g graph, err error) {
+		g.nodes, err = s.queryNodes(ctx, run, matchAllNodes, nil)
+		if err != nil {
+			return
+		}
+		g.links, err = s.queryLinks(ctx, run, matchAllLinks, nil)
+		return
+	})
+}
+
+// getNode returns the node with the specified ID
+// with all properties on it.
+//
+// If there is no such node, it returns (nil, nil).
+func (s *neo4jSLN) getNode(ctx context.Context, run runner, id gosln.ID) (*gosln.Node, error) {
+	if !id.IsValid() {
+		return nil, nil
+	}
+	nodes, err := s.queryNodes(ctx, run, matchNodeByID, map[string]any{"id": id.String()})
+	if err != nil || len(nodes) == 0 {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+// getLink returns the link with the specified ID
+// with all properties on it and its From and To nodes.
+//
+// If there is no such link, it returns (nil, nil).
+func (s *neo4jSLN) getLink(ctx context.Context, run runner, id gosln.ID) (*gosln.Link, error) {
+	if !id.IsValid() {
+		return nil, nil
+	}
+	links, err := s.queryLinks(ctx, run, matchLinkByID, map[string]any{"id": id.String()})
+	if err != nil || len(links) == 0 {
+		return nil, err
+	}
+	return links[0], nil
+}
+
+// matchNodes returns all nodes that satisfy cond
+// with all properties on them.
+func (s *neo4jSLN) matchNodes(ctx context.Context, cond gosln.NodeMatchCond) (nodes []*gosln.Node, err error) {
+	return read(ctx, s, func(run runner) ([]*gosln.Node, error) {
+		return s.queryMatchedNodes(ctx, run, cond)
+	})
+}
+
+// countMatchedNodes returns the number of nodes that satisfy cond,
+// but counts at most limit nodes if limit is positive.
+//
+// It translates cond into Cypher by function buildWhere
+// to count the nodes in the database if possible.
+// Otherwise, it fetches all nodes and evaluates cond on the client side.
+func (s *neo4jSLN) countMatchedNodes(ctx context.Context, cond gosln.NodeMatchCond, limit int) (
+	n int, err error) {
+	return read(ctx, s, func(run runner) (int, error) {
+		where, params, err := buildWhere("n", cond)
+		if err == nil {
+			cypher := "MATCH (n) WHERE n.slnID IS NOT NULL AND (" + where + ")"
+			if limit > 0 {
+				cypher += " WITH n LIMIT $limit"
+				params["limit"] = int64(limit)
+			}
+			v, err := single(ctx, run, cypher+" RETURN count(n)", params)
+			if err != nil {
+				return 0, err
+			}
+			count, _ := v.(int64)
+			return int(count), nil
+		} else if !errors.Is(err, errCondNotTranslatable) {
+			return 0, err
+		}
+		nodes, err := s.queryNodes(ctx, run, matchAllNodes, nil)
+		if err != nil {
+			return 0, err
+		}
+		var count int
+		for _, node := range nodes {
+			if cond.Match(node) {
+				count++
+				if count == limit {
+					break
+				}
+			}
+		}
+		return count, nil
+	})
+}
+
+// queryMatchedNodes queries the nodes that satisfy cond
+// with all properties on them.
+//
+// It translates cond into Cypher by function buildWhere
+// to filter the nodes in the database if possible.
+// Otherwise, it fetches all nodes and evaluates cond on the client side.
+func (s *neo4jSLN) queryMatchedNodes(ctx context.Context, run runner, cond gosln.NodeMatchCond) (
+	nodes []*gosln.Node, err error) {
+	where, params, err := buildWhere("n", cond)
+	if err == nil {
+		return s.queryNodes(ctx, run, "MATCH (n) WHERE n.slnID IS NOT NULL AND ("+where+") RETURN n", params)
+	} else if !errors.Is(err, errCondNotTranslatable) {
+		return nil, err
+	}
+	nodes, err = s.queryNodes(ctx, run, matchAllNodes, nil)
+	if err != nil {
+		return nil, err
+	}
+	matched := nodes[:0]
+	for _, node := range nodes {
+		if cond.Match(node) {
+			matched = append(matched, node)
+		}
+	}
+	return matched, nil
+}
+
+// matchLinks returns all links that satisfy cond
+// with all properties on them and their From and To nodes.
+func (s *neo4jSLN) matchLinks(ctx context.Context, cond gosln.LinkMatchCond) (links []*gosln.Link, err error) {
+	links, err = read(ctx, s, func(run runner) ([]*gosln.Link, error) {
+		return s.queryLinks(ctx, run, matchAllLinks, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	matched := links[:0]
+	for _, link := range links {
+		if cond.Match(link) {
+			matched = append(matched, link)
+		}
+	}
+	return matched, nil
+}
+
+// getNodeAndIncidentLinks returns the node with the specified ID,
+// together with the links that start from or point to it
+// in the direction specified by opts
+// and satisfy the link conditions in opts,
+// in ascending order of the link IDs.
+//
+// The links whose other end has been soft-removed are skipped.
+//
+// It reports a *gosln.NodeNotExistError if the node does not exist
+// or has been soft-removed.
+func (s *neo4jSLN) getNodeAndIncidentLinks(ctx context.Context, id gosln.ID, opts gosln.NeighborhoodOptions) (
+	node *gosln.Node, links []*gosln.Link, err error) {
+	dir := opts.Direction
+	if !dir.IsValid() {
+		dir = gosln.DirBoth
+	}
+	type result struct {
+		node  *gosln.Node
+		links []*gosln.Link
+	}
+	r, err := read(ctx, s, func(run runner) (r result, err error) {
+		r.node, err = s.getNode(ctx, run, id)
+		if err != nil || r.node == nil || r.node.Deleted {
+			return
+		}
+		r.links, err = s.queryLinks(ctx, run, matchIncidentLinks, map[string]any{"id": id.String()})
+		return
+	})
+	if err != nil {
+		return nil, nil, err
+	} else if r.node == nil || r.node.Deleted {
+		return nil, nil, gosln.NewNodeNotExistError(id)
+	}
+	links = r.links[:0]
+	for _, link := range r.links {
+		if dir == gosln.DirOutgoing && link.From.ID != id ||
+			dir == gosln.DirIncoming && link.To.ID != id ||
+			otherEnd(link, id).Deleted ||
+			!opts.LinkCond.Match(link) {
+			continue
+		}
+		links = append(links, link)
+	}
+	sortLinks(links)
+	return r.node, links, nil
+}
+
+// otherEnd returns the node at the other end of link than
+// the node with the specified ID.
+//
+// If link starts from and points to the node, it returns that node.
+func otherEnd(link *gosln.Link, id gosln.ID) *gosln.Node {
+	if link.To.ID == id {
+		return link.From
+	}
+	return link.To
+}
+
+// queryTypes runs the Cypher query that returns type names
+// in the first column, and returns the types in ascending order.
+func queryTypes(ctx context.Context, run runner, cypher string) (types []gosln.Type, err error) {
+	records, err := collect(ctx, run, cypher, nil)
+	if err != nil {
+		return nil, err
+	}
+	types = make([]gosln.Type, 0, len(records))
+	for _, record := range records {
+		name, _ := record.Values[0].(string)
+		t, err := gosln.NewType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Compare(types[j]) < 0
+	})
+	return types, nil
+}
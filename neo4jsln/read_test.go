@@ -0,0 +1,184 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+)
+
+func TestGetNodeByID(t *testing.T) {
+	ctx := context.Background()
+	id := gosln.NewID(gosln.MustNewType("Person"), gosln.DateOfYearMonthDay(2023, 1, 2), 3)
+	age := gosln.MustNewPropName("age")
+	score := gosln.MustNewPropName("score")
+	nick := gosln.MustNewPropName("nick")
+	stored := map[string]any{
+		slnIDPropName: id.String(),
+		"age":         int64(7),
+		"score":       2.5,
+		"nick":        []byte("x"),
+	}
+	deleted := map[string]any{slnIDPropName: id.String(), slnDeletedPropName: true}
+
+	coerced := gosln.NewPropTypeMap(3)
+	coerced.Set(age, gosln.PTInt8)
+	coerced.Set(score, gosln.PTFloat32)
+	coerced.Set(nick, gosln.PTString)
+	wantCoerced := gosln.NewPropMap(3)
+	wantCoerced.Set(age, int8(7))
+	wantCoerced.Set(score, float32(2.5))
+	wantCoerced.Set(nick, "x")
+	onlyAge := gosln.NewPropTypeMap(1)
+	onlyAge.Set(age, gosln.PTFloat64)
+	wantOnlyAge := gosln.NewPropMap(1)
+	wantOnlyAge.Set(age, float64(7))
+	ageString := gosln.NewPropTypeMap(1)
+	ageString.Set(age, gosln.PTString)
+
+	var nne *gosln.NodeNotExistError
+	var pte *gosln.PropTypeError
+	testCases := []struct {
+		name      string
+		records   []map[string]any
+		propTypes gosln.PropTypeMap
+		want      gosln.PropMap
+		wantErr   any // A pointer to the type of the error, or nil.
+	}{
+		{"empty match", nil, coerced, nil, &nne},
+		{"soft-removed", []map[string]any{deleted}, coerced, nil, &nne},
+		{"coerced", []map[string]any{stored}, coerced, wantCoerced, nil},
+		{"discard others", []map[string]any{stored}, onlyAge, wantOnlyAge, nil},
+		{"nil propTypes", []map[string]any{stored}, nil, gosln.NewPropMap(0), nil},
+		{"not convertible", []map[string]any{stored}, ageString, nil, &pte},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := &fakeSession{run: nodeRecords(tc.records...)}
+			s := newFakeSLN(fs, 0, 0)
+			defer func() {
+				if err := s.Close(); err != nil {
+					t.Error("close -", err)
+				}
+			}()
+			node, err := s.GetNodeByID(ctx, id, tc.propTypes)
+			if len(fs.queries) != 1 || fs.queries[0] != matchNodeByID {
+				t.Errorf("got queries %q; want [%q]", fs.queries, matchNodeByID)
+			}
+			if tc.wantErr != nil {
+				if !errors.As(err, tc.wantErr) {
+					t.Errorf("got error %v; want %T", err, tc.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if node.ID != id || node.Type != id.Type() {
+				t.Errorf("got node %v of type %v; want %v of type %v", node.ID, node.Type, id, id.Type())
+			}
+			if !gosln.EqualPropMaps(node.Props, tc.want) {
+				t.Errorf("got properties %v; want %v", node.Props, tc.want)
+			}
+		})
+	}
+}
+
+func TestNumNodeAndExistsAtLeast(t *testing.T) {
+	ctx := context.Background()
+	const NumNode = 3
+	person := gosln.MustNewType("Person")
+	props := make([]map[string]any, NumNode)
+	for i := range props {
+		props[i] = map[string]any{
+			slnIDPropName: gosln.NewID(person, gosln.DateOfYearMonthDay(2023, 1, 2), int64(i+1)).String(),
+			"age":         int64(20 + i),
+		}
+	}
+	allNodes := nodeRecords(props...)
+	// run counts all nodes in the database, subject to $limit,
+	// regardless of the conditions.
+	run := func(cypher string, params map[string]any) ([]*neo4j.Record, error) {
+		if cypher == matchAllNodes {
+			return allNodes(cypher, params)
+		}
+		n := int64(NumNode)
+		if limit, ok := params["limit"].(int64); ok && limit < n {
+			n = limit
+		}
+		return []*neo4j.Record{{Keys: []string{"count(n)"}, Values: []any{n}}}, nil
+	}
+	gt := gosln.NewPropMatchClause(0, 0, 0)
+	gt.GreaterThan().Set(gosln.MustNewPropName("age"), int64(20))
+	gtClause := gosln.NewNodeMatchClause()
+	gtClause.SetPropMatchClause(gt)
+	clientSide := gosln.NodeMatchCond{gtClause}
+
+	const CountAll = "MATCH (n) WHERE n.slnID IS NOT NULL AND (n.slnDeleted IS NULL) RETURN count(n)"
+	const CountLimit = "MATCH (n) WHERE n.slnID IS NOT NULL AND (n.slnDeleted IS NULL) " +
+		"WITH n LIMIT $limit RETURN count(n)"
+	testCases := []struct {
+		name      string
+		cond      gosln.NodeMatchCond
+		k         int // Non-positive for NumNode.
+		want      int // For ExistsAtLeast, 1 for true and 0 for false.
+		wantQuery string
+	}{
+		{"NumNode", nil, 0, NumNode, CountAll},
+		{"NumNode client-side", clientSide, 0, NumNode - 1, matchAllNodes},
+		{"ExistsAtLeast 2", nil, 2, 1, CountLimit},
+		{"ExistsAtLeast 4", nil, NumNode + 1, 0, CountLimit},
+		{"ExistsAtLeast 2 client-side", clientSide, 2, 1, matchAllNodes},
+		{"ExistsAtLeast 3 client-side", clientSide, 3, 0, matchAllNodes},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fs := &fakeSession{run: run}
+			s := newFakeSLN(fs, 0, 0)
+			defer func() {
+				if err := s.Close(); err != nil {
+					t.Error("close -", err)
+				}
+			}()
+			var got int
+			var err error
+			if tc.k <= 0 {
+				got, err = s.NumNode(ctx, tc.cond)
+			} else {
+				var ok bool
+				ok, err = s.ExistsAtLeast(ctx, tc.cond, tc.k)
+				if ok {
+					got = 1
+				}
+			}
+			if err != nil {
+				t.Fatal(err)
+			} else if got != tc.want {
+				t.Errorf("got %d; want %d", got, tc.want)
+			}
+			if len(fs.queries) != 1 || fs.queries[0] != tc.wantQuery {
+				t.Errorf("got queries %q; want [%q]", fs.queries, tc.wantQuery)
+			}
+		})
+	}
+}
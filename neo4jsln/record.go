@@ -0,0 +1,351 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/donyori/gogo/errors"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/propconv"
+)
+
+// Cypher queries to fetch all nodes and links.
+const (
+	matchAllNodes = "MATCH (n) WHERE n.slnID IS NOT NULL RETURN n"
+	matchAllLinks = "MATCH (a)-[r]->(b) WHERE r.slnID IS NOT NULL RETURN r, a, b"
+)
+
+// queryNodes runs the Cypher query that returns Neo4j nodes
+// in the first column, and returns the corresponding semantic nodes
+// with all properties on them.
+func (s *neo4jSLN) queryNodes(ctx context.Context, run runner, cypher string, params map[string]any) (
+	nodes []*gosln.Node, err error) {
+	records, err := collect(ctx, run, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	nodes = make([]*gosln.Node, len(records))
+	for i, record := range records {
+		nodes[i], err = s.nodeOf(record.Values[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// queryLinks runs the Cypher query that returns Neo4j relationships
+// in the first column and their start and end nodes
+// in the second and third columns,
+// and returns the corresponding semantic links
+// with all properties on them and their From and To nodes.
+func (s *neo4jSLN) queryLinks(ctx context.Context, run runner, cypher string, params map[string]any) (
+	links []*gosln.Link, err error) {
+	records, err := collect(ctx, run, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	links = make([]*gosln.Link, len(records))
+	for i, record := range records {
+		links[i], err = s.linkOf(record.Values[0], record.Values[1], record.Values[2])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// collect runs the Cypher query and returns all records.
+func collect(ctx context.Context, run runner, cypher string, params map[string]any) (
+	records []*neo4j.Record, err error) {
+	result, err := run.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Collect(ctx)
+}
+
+// single runs the Cypher query that returns exactly one record
+// and returns the value in its first column.
+func single(ctx context.Context, run runner, cypher string, params map[string]any) (v any, err error) {
+	result, err := run.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	record, err := result.Single(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return record.Values[0], nil
+}
+
+// nodeOf returns the semantic node corresponding to the Neo4j node v
+// with all properties on it.
+func (s *neo4jSLN) nodeOf(v any) (node *gosln.Node, err error) {
+	n, ok := v.(neo4j.Node)
+	if !ok {
+		return nil, errors.AutoNew(fmt.Sprintf("want a Neo4j node, got %T", v))
+	}
+	node = &gosln.Node{NL: gosln.NL{SLN: s}}
+	node.ID, node.Props, err = idAndPropsOf(n.Props)
+	if err != nil {
+		return nil, err
+	}
+	node.Type = node.ID.Type()
+	node.Deleted, _ = n.Props[slnDeletedPropName].(bool)
+	return node, nil
+}
+
+// linkOf returns the semantic link corresponding to
+// the Neo4j relationship v, starting from the Neo4j node from
+// and pointing to the Neo4j node to,
+// with all properties on the link and its From and To nodes.
+func (s *neo4jSLN) linkOf(v, from, to any) (link *gosln.Link, err error) {
+	r, ok := v.(neo4j.Relationship)
+	if !ok {
+		return nil, errors.AutoNew(fmt.Sprintf("want a Neo4j relationship, got %T", v))
+	}
+	link = &gosln.Link{NL: gosln.NL{SLN: s}}
+	link.ID, link.Props, err = idAndPropsOf(r.Props)
+	if err != nil {
+		return nil, err
+	}
+	link.Type = link.ID.Type()
+	link.From, err = s.nodeOf(from)
+	if err != nil {
+		return nil, err
+	}
+	if link.To, err = s.nodeOf(to); err != nil {
+		return nil, err
+	} else if link.To.ID == link.From.ID {
+		link.To = link.From
+	}
+	return link, nil
+}
+
+// idAndPropsOf returns the SLN ID and the properties
+// recorded in the properties of a Neo4j node or relationship.
+//
+// The properties with invalid names, including the reserved names
+// beginning with "sln", are discarded.
+// The property values are converted by function propFromNeo4j.
+func idAndPropsOf(m map[string]any) (id gosln.ID, props gosln.PropMap, err error) {
+	idStr, ok := m[slnIDPropName].(string)
+	if !ok {
+		return gosln.ID{}, nil, errors.AutoNew("property " + slnIDPropName + " is absent or not a string")
+	}
+	id, err = gosln.ParseID(idStr)
+	if err != nil {
+		return gosln.ID{}, nil, err
+	}
+	props = gosln.NewPropMap(len(m))
+	for k, v := range m {
+		if !gosln.IsValidPropNameString(k) {
+			continue
+		}
+		name := gosln.MustNewPropName(k)
+		value, ok := propFromNeo4j(v)
+		if !ok {
+			return gosln.ID{}, nil, errors.AutoWrap(gosln.NewInvalidPropValueError(name, v))
+		}
+		props.Set(name, value)
+	}
+	return id, props, nil
+}
+
+// propFromNeo4j converts the property value v returned by the Neo4j driver
+// back to the property value stored by the client,
+// reversing the conversion of function paramValue.
+//
+// It converts the Neo4j dates to gosln.Date by function dateFromNeo4j,
+// and the Neo4j local date-times to time.Time in UTC,
+// as paramValue stores time.Time as local date-times in UTC.
+// It converts the Neo4j lists of Booleans, integers, floats, and strings
+// to []bool, []int64, []float64, and []string by function listFromNeo4j.
+// The other values are returned as they are.
+//
+// It returns the converted value and true if the value is
+// a valid property value (see gosln.PropValue) after conversion.
+// Otherwise (e.g., v is a map, a point, a duration,
+// or another Neo4j temporal value), it returns (nil, false).
+func propFromNeo4j(v any) (value any, ok bool) {
+	if date, ok := dateFromNeo4j(v); ok {
+		return date, true
+	}
+	switch x := v.(type) {
+	case neo4j.LocalDateTime:
+		t := x.Time()
+		v = time.Date(t.Year(), t.Month(), t.Day(),
+			t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	case []any:
+		return listFromNeo4j(x)
+	}
+	if !gosln.PropTypeOf(v).IsValid() {
+		return nil, false
+	}
+	return v, true
+}
+
+// listFromNeo4j converts the Neo4j list returned by the Neo4j driver
+// to a slice of scalars (see method IsSlice of gosln.PropType).
+//
+// The type of the slice is determined by the first element.
+// In particular, an empty list is converted to an empty []string,
+// as Neo4j does not record the element type of an empty list.
+//
+// It returns the slice and true if the elements are of the same type
+// bool, int64, float64, or string.
+// Otherwise, it returns (nil, false).
+func listFromNeo4j(list []any) (value any, ok bool) {
+	if len(list) == 0 {
+		return []string{}, true
+	}
+	switch list[0].(type) {
+	case bool:
+		return sliceFromNeo4j[bool](list)
+	case int64:
+		return sliceFromNeo4j[int64](list)
+	case float64:
+		return sliceFromNeo4j[float64](list)
+	case string:
+		return sliceFromNeo4j[string](list)
+	}
+	return nil, false
+}
+
+// sliceFromNeo4j converts the Neo4j list to []E.
+//
+// It returns the slice and true if all elements are of type E.
+// Otherwise, it returns (nil, false).
+func sliceFromNeo4j[E bool | int64 | float64 | string](list []any) (value any, ok bool) {
+	s := make([]E, len(list))
+	for i := range list {
+		s[i], ok = list[i].(E)
+		if !ok {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+// dateFromNeo4j converts the Neo4j date v (of type neo4j.Date,
+// i.e., dbtype.Date) returned by the Neo4j driver to gosln.Date.
+//
+// It returns the date and true if v is a Neo4j date.
+// Otherwise, it returns (gosln.Date{}, false).
+func dateFromNeo4j(v any) (date gosln.Date, ok bool) {
+	d, ok := v.(neo4j.Date)
+	if !ok {
+		return gosln.Date{}, false
+	}
+	year, month, day := d.Time().Date()
+	return gosln.DateOfYearMonthDay(year, month, day), true
+}
+
+// exportNode returns a copy of node with the properties
+// converted according to propTypes.
+func exportNode(node *gosln.Node, propTypes gosln.PropTypeMap) (*gosln.Node, error) {
+	props, err := propconv.Convert(node.Props, propTypes)
+	if err != nil {
+		return nil, err
+	}
+	return &gosln.Node{
+		NL: gosln.NL{
+			SLN:   node.SLN,
+			ID:    node.ID,
+			Type:  node.Type,
+			Props: props,
+		},
+		Deleted: node.Deleted,
+	}, nil
+}
+
+// exportLink returns a copy of link with the properties
+// converted according to propTypes.
+//
+// from and to are set to the fields From and To of the returned link.
+// If from is nil, a node without properties is used instead, as is to.
+func exportLink(link *gosln.Link, propTypes gosln.PropTypeMap, from, to *gosln.Node) (*gosln.Link, error) {
+	props, err := propconv.Convert(link.Props, propTypes)
+	if err != nil {
+		return nil, err
+	}
+	return withEndpoints(link, props, from, to), nil
+}
+
+// withEndpoints returns a copy of link with the specified properties
+// and the specified From and To nodes.
+//
+// If from is nil, a node without properties is used instead, as is to.
+func withEndpoints(link *gosln.Link, props gosln.PropMap, from, to *gosln.Node) *gosln.Link {
+	r := &gosln.Link{
+		NL: gosln.NL{
+			SLN:   link.SLN,
+			ID:    link.ID,
+			Type:  link.Type,
+			Props: props,
+		},
+		From: from,
+		To:   to,
+	}
+	if r.From == nil {
+		r.From = endpointNode(link.From)
+	}
+	if r.To == nil {
+		if link.To == link.From {
+			r.To = r.From
+		} else {
+			r.To = endpointNode(link.To)
+		}
+	}
+	return r
+}
+
+// endpointNode returns a copy of node without properties
+// as the end of a link.
+func endpointNode(node *gosln.Node) *gosln.Node {
+	return &gosln.Node{
+		NL: gosln.NL{
+			SLN:  node.SLN,
+			ID:   node.ID,
+			Type: node.Type,
+		},
+		Deleted: node.Deleted,
+	}
+}
+
+// sortNodes sorts the nodes in ascending order of their IDs.
+func sortNodes(nodes []*gosln.Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID.Compare(nodes[j].ID) < 0
+	})
+}
+
+// sortLinks sorts the links in ascending order of their IDs.
+func sortLinks(links []*gosln.Link) {
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].ID.Compare(links[j].ID) < 0
+	})
+}
@@ -0,0 +1,114 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+
+	"github.com/donyori/gosln"
+)
+
+func TestDateFromNeo4j(t *testing.T) {
+	testCases := []struct {
+		v      any
+		wantD  gosln.Date
+		wantOK bool
+	}{
+		{neo4j.DateOf(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)), gosln.DateOfYearMonthDay(2023, 1, 2), true},
+		{dbtype.Date(time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)), gosln.DateOfYearMonthDay(2020, 2, 29), true},
+		{neo4j.DateOf(time.Date(1990, 12, 31, 23, 59, 59, 0, time.FixedZone("UTC+8", 8*60*60))), gosln.DateOfYearMonthDay(1990, 12, 31), true},
+		{nil, gosln.Date{}, false},
+		{gosln.DateOfYearMonthDay(2023, 1, 2), gosln.Date{}, false},
+		{time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), gosln.Date{}, false},
+		{neo4j.LocalDateTimeOf(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)), gosln.Date{}, false},
+		{"2023-01-02", gosln.Date{}, false},
+	}
+
+	for i, tc := range testCases {
+		d, ok := dateFromNeo4j(tc.v)
+		if d != tc.wantD || ok != tc.wantOK {
+			t.Errorf("case %d: got (%v, %t); want (%v, %t)", i, d, ok, tc.wantD, tc.wantOK)
+		}
+	}
+}
+
+func TestPropFromNeo4j(t *testing.T) {
+	tm := time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC)
+	testCases := []struct {
+		v      any
+		want   any
+		wantOK bool
+	}{
+		{true, true, true},
+		{int64(-1), int64(-1), true},
+		{1.5, 1.5, true},
+		{"abc", "abc", true},
+		{[]byte("abc"), []byte("abc"), true},
+		{neo4j.DateOf(tm), gosln.DateOfYearMonthDay(2023, 1, 2), true},
+		{paramValue(tm), tm, true},
+		{paramValue(tm.In(time.FixedZone("UTC+8", 8*60*60))), tm, true},
+		{tm, tm, true}, // zoned date-time
+		{nil, nil, false},
+		{[]any{true, false}, []bool{true, false}, true},
+		{[]any{int64(1), int64(-2)}, []int64{1, -2}, true},
+		{[]any{1.5}, []float64{1.5}, true},
+		{[]any{"a", "b"}, []string{"a", "b"}, true},
+		{[]any{}, []string{}, true},
+		{[]any{int64(1), 1.5}, nil, false},
+		{[]any{"a", nil}, nil, false},
+		{[]any{[]any{"a"}}, nil, false},
+		{map[string]any{"a": int64(1)}, nil, false},
+		{neo4j.LocalTimeOf(tm), nil, false},
+		{neo4j.DurationOf(1, 2, 3, 4), nil, false},
+		{neo4j.Point2D{X: 1, Y: 2, SpatialRefId: 7203}, nil, false},
+	}
+
+	for i, tc := range testCases {
+		v, ok := propFromNeo4j(tc.v)
+		if ok != tc.wantOK {
+			t.Errorf("case %d: got ok %t; want %t", i, ok, tc.wantOK)
+			continue
+		}
+		switch want := tc.want.(type) {
+		case []byte:
+			if b, isBytes := v.([]byte); !isBytes || !bytes.Equal(b, want) {
+				t.Errorf("case %d: got %#v; want %#v", i, v, want)
+			}
+		case time.Time:
+			if got, isTime := v.(time.Time); !isTime || !got.Equal(want) ||
+				got.Location() != time.UTC {
+				t.Errorf("case %d: got %#v; want %#v", i, v, want)
+			}
+		case []bool, []int64, []float64, []string:
+			if !reflect.DeepEqual(v, want) {
+				t.Errorf("case %d: got %#v; want %#v", i, v, want)
+			}
+		default:
+			if v != tc.want {
+				t.Errorf("case %d: got %#v; want %#v", i, v, tc.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"sync"
+
+	"github.com/donyori/gogo/errors"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/notify"
+)
+
+// neo4jTx is an implementation of interface gosln.Tx
+// backed by a Neo4j explicit transaction.
+type neo4jTx struct {
+	mu   sync.Mutex
+	done bool
+
+	s    *neo4jSLN
+	sess session
+	tx   neo4j.ExplicitTransaction
+
+	// events are the events emitted by the operations in the transaction,
+	// pushed to the notifier of s on commit.
+	events []notify.Event
+}
+
+var _ gosln.Tx = (*neo4jTx)(nil)
+
+func (s *neo4jSLN) BeginTx(ctx context.Context) (tx gosln.Tx, err error) {
+	err = s.rLock(ctx)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	defer s.mu.RUnlock()
+	sess := s.newSession(ctx, neo4j.AccessModeWrite)
+	explicitTx, err := sess.BeginTransaction(ctx)
+	if err != nil {
+		_ = sess.Close(ctx) // ignore error
+		return nil, errors.AutoWrap(err)
+	}
+	return &neo4jTx{s: s, sess: sess, tx: explicitTx}, nil
+}
+
+func (tx *neo4jTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errors.AutoWrap(gosln.ErrTxDone)
+	}
+	tx.done = true
+	ctx := context.Background()
+	if tx.s.Closed() {
+		_ = tx.tx.Rollback(ctx) // ignore error
+		_ = tx.sess.Close(ctx)  // ignore error
+		return errors.AutoWrap(gosln.ErrSLNClosed)
+	}
+	err := tx.tx.Commit(ctx)
+	closeSession(ctx, tx.sess, &err)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	tx.s.notifier.Push(tx.events...)
+	tx.events = nil
+	return nil
+}
+
+func (tx *neo4jTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return errors.AutoWrap(gosln.ErrTxDone)
+	}
+	tx.done = true
+	tx.events = nil
+	ctx := context.Background()
+	err := tx.tx.Rollback(ctx)
+	closeSession(ctx, tx.sess, &err)
+	return errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		node, err = w.createNode(t, props)
+		return
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) UpsertNode(ctx context.Context, t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+	node *gosln.Node, created bool, err error) {
+	var r upsertResult
+	err = tx.do(ctx, func(w *writer) (err error) {
+		r, err = w.upsertNode(t, keyName, props)
+		return
+	})
+	if err != nil {
+		return nil, false, errors.AutoWrap(err)
+	}
+	return r.node, r.created, nil
+}
+
+func (tx *neo4jTx) CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		link, err = w.createLink(t, from, to, props)
+		return
+	})
+	return link, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) RemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	return errors.AutoWrap(tx.do(ctx, func(w *writer) error {
+		return w.removeNodeByID(id)
+	}))
+}
+
+func (tx *neo4jTx) RemoveNodesByCond(ctx context.Context, cond gosln.NodeMatchCond) (removed int, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		removed, err = w.removeNodesByCond(cond)
+		return
+	})
+	return removed, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) SoftRemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	return errors.AutoWrap(tx.do(ctx, func(w *writer) error {
+		return w.softRemoveNodeByID(id)
+	}))
+}
+
+func (tx *neo4jTx) RestoreNodeByID(ctx context.Context, id gosln.ID) error {
+	return errors.AutoWrap(tx.do(ctx, func(w *writer) error {
+		return w.restoreNodeByID(id)
+	}))
+}
+
+func (tx *neo4jTx) RemoveLinkByID(ctx context.Context, id gosln.ID) error {
+	return errors.AutoWrap(tx.do(ctx, func(w *writer) error {
+		return w.removeLinkByID(id)
+	}))
+}
+
+func (tx *neo4jTx) RemoveLinksByCond(ctx context.Context, cond gosln.LinkMatchCond) (removed int, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		removed, err = w.removeLinksByCond(cond)
+		return
+	})
+	return removed, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) SetNodeProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		node, err = w.updateNode(id, setNodeProps, id, props, nil)
+		return
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) SetLinkProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		link, err = w.updateLink(id, setLinkProps, id, props, nil)
+		return
+	})
+	return link, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) MutateNodeProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	node *gosln.Node, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		set, remove := mutateArgs(pma)
+		node, err = w.updateNode(id, mutateNodeProps, gosln.ID{}, set, remove)
+		return
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (tx *neo4jTx) MutateLinkProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	link *gosln.Link, err error) {
+	err = tx.do(ctx, func(w *writer) (err error) {
+		set, remove := mutateArgs(pma)
+		link, err = w.updateLink(id, mutateLinkProps, gosln.ID{}, set, remove)
+		return
+	})
+	return link, errors.AutoWrap(err)
+}
+
+// do runs op with a writer on the explicit transaction
+// and records the events emitted by op.
+//
+// It reports gosln.ErrTxDone if the transaction has ended.
+func (tx *neo4jTx) do(ctx context.Context, op func(w *writer) error) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return gosln.ErrTxDone
+	}
+	err := tx.s.rLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.s.mu.RUnlock()
+	w := &writer{ctx: ctx, s: tx.s, run: tx.tx}
+	err = op(w)
+	if err != nil {
+		return err
+	}
+	tx.events = append(tx.events, w.events...)
+	return nil
+}
@@ -0,0 +1,760 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/internal/notify"
+)
+
+// Cypher queries used by the mutating operations.
+const (
+	allocSerials = "MERGE (c:" + serialLabel + " {type: $type}) " +
+		"ON CREATE SET c.serial = 0 SET c.serial = c.serial + $n RETURN c.serial"
+	mergeLinkConstraint = "MERGE (c:" + linkConstraintLabel + " {type: $type}) " +
+		"SET c.from = $from, c.to = $to"
+	deleteLinkConstraint = "MATCH (c:" + linkConstraintLabel + " {type: $type}) DELETE c"
+	matchLinkConstraint  = "MATCH (c:" + linkConstraintLabel + " {type: $type}) RETURN c.from, c.to"
+	mergeNodeProps       = "MATCH (n {slnID: $id}) SET n += $props RETURN n"
+	matchLinkIDsOfNodes  = "MATCH (n)-[r]-() WHERE n.slnID IN $ids AND r.slnID IS NOT NULL " +
+		"RETURN DISTINCT r.slnID"
+	deleteNodes = "MATCH (n) WHERE n.slnID IN $ids " +
+		"WITH n, n.slnID AS id DETACH DELETE n RETURN id"
+	softRemoveNode = "MATCH (n {slnID: $id}) WHERE n.slnDeleted IS NULL " +
+		"SET n.slnDeleted = true RETURN n.slnID"
+	restoreNode = "MATCH (n {slnID: $id}) " +
+		"WITH n, n.slnDeleted IS NOT NULL AS deleted REMOVE n.slnDeleted RETURN deleted"
+	lockNode = "MATCH (n {slnID: $id}) WHERE n.slnDeleted IS NULL " +
+		"SET n.slnLock = true REMOVE n.slnLock RETURN n"
+	deleteLinks = "MATCH ()-[r]->() WHERE r.slnID IN $ids " +
+		"WITH r, r.slnID AS id DELETE r RETURN id"
+	setNodeProps    = "MATCH (n {slnID: $id}) WHERE n.slnDeleted IS NULL SET n = $props RETURN n"
+	mutateNodeProps = "MATCH (n {slnID: $id}) WHERE n.slnDeleted IS NULL SET n += $props RETURN n"
+	setLinkProps    = "MATCH (a)-[r {slnID: $id}]->(b) SET r = $props RETURN r, a, b"
+	mutateLinkProps = "MATCH (a)-[r {slnID: $id}]->(b) SET r += $props RETURN r, a, b"
+)
+
+// writer implements the mutating operations in a Neo4j transaction.
+//
+// It records the events to be pushed to the notifier
+// after the transaction is committed.
+type writer struct {
+	ctx    context.Context
+	s      *neo4jSLN
+	run    runner
+	events []notify.Event
+}
+
+// upsertResult is the result of the method upsertNode of writer.
+type upsertResult struct {
+	node    *gosln.Node
+	created bool
+}
+
+func (s *neo4jSLN) RegisterLinkConstraint(
+	ctx context.Context,
+	linkType gosln.Type,
+	fromTypes, toTypes gosln.TypeSet,
+) error {
+	_, err := write(ctx, s, func(w *writer) (struct{}, error) {
+		return struct{}{}, w.registerLinkConstraint(linkType, fromTypes, toTypes)
+	})
+	return errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	node, err = write(ctx, s, func(w *writer) (*gosln.Node, error) {
+		return w.createNode(t, props)
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) UpsertNode(ctx context.Context, t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+	node *gosln.Node, created bool, err error) {
+	r, err := write(ctx, s, func(w *writer) (upsertResult, error) {
+		return w.upsertNode(t, keyName, props)
+	})
+	if err != nil {
+		return nil, false, errors.AutoWrap(err)
+	}
+	return r.node, r.created, nil
+}
+
+func (s *neo4jSLN) CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	link, err = write(ctx, s, func(w *writer) (*gosln.Link, error) {
+		return w.createLink(t, from, to, props)
+	})
+	return link, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	_, err := write(ctx, s, func(w *writer) (struct{}, error) {
+		return struct{}{}, w.removeNodeByID(id)
+	})
+	return errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RemoveNodesByCond(ctx context.Context, cond gosln.NodeMatchCond) (removed int, err error) {
+	removed, err = write(ctx, s, func(w *writer) (int, error) {
+		return w.removeNodesByCond(cond)
+	})
+	return removed, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) SoftRemoveNodeByID(ctx context.Context, id gosln.ID) error {
+	_, err := write(ctx, s, func(w *writer) (struct{}, error) {
+		return struct{}{}, w.softRemoveNodeByID(id)
+	})
+	return errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RestoreNodeByID(ctx context.Context, id gosln.ID) error {
+	_, err := write(ctx, s, func(w *writer) (struct{}, error) {
+		return struct{}{}, w.restoreNodeByID(id)
+	})
+	return errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RemoveLinkByID(ctx context.Context, id gosln.ID) error {
+	_, err := write(ctx, s, func(w *writer) (struct{}, error) {
+		return struct{}{}, w.removeLinkByID(id)
+	})
+	return errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RemoveLinksByCond(ctx context.Context, cond gosln.LinkMatchCond) (removed int, err error) {
+	removed, err = write(ctx, s, func(w *writer) (int, error) {
+		return w.removeLinksByCond(cond)
+	})
+	return removed, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) RenameType(ctx context.Context, oldType, newType gosln.Type) (
+	idMap map[gosln.ID]gosln.ID, err error) {
+	idMap, err = write(ctx, s, func(w *writer) (map[gosln.ID]gosln.ID, error) {
+		return w.renameType(oldType, newType)
+	})
+	return idMap, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) SetNodeProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	node *gosln.Node, err error) {
+	node, err = write(ctx, s, func(w *writer) (*gosln.Node, error) {
+		return w.updateNode(id, setNodeProps, id, props, nil)
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) SetLinkProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	link, err = write(ctx, s, func(w *writer) (*gosln.Link, error) {
+		return w.updateLink(id, setLinkProps, id, props, nil)
+	})
+	return link, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) MutateNodeProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	node *gosln.Node, err error) {
+	node, err = write(ctx, s, func(w *writer) (*gosln.Node, error) {
+		set, remove := mutateArgs(pma)
+		return w.updateNode(id, mutateNodeProps, gosln.ID{}, set, remove)
+	})
+	return node, errors.AutoWrap(err)
+}
+
+// UpdateNode locks the node in a Neo4j write transaction
+// before calling fn, so no other transaction can modify the node
+// until the mutation is committed.
+// fn is called again if the transaction is retried.
+func (s *neo4jSLN) UpdateNode(
+	ctx context.Context,
+	id gosln.ID,
+	propTypes gosln.PropTypeMap,
+	fn func(current gosln.PropMap) (gosln.PropMutateArg, error),
+) (node *gosln.Node, err error) {
+	if fn == nil {
+		return nil, errors.AutoNew("update function is nil")
+	}
+	node, err = write(ctx, s, func(w *writer) (*gosln.Node, error) {
+		return w.updateNodeByFunc(id, propTypes, fn)
+	})
+	return node, errors.AutoWrap(err)
+}
+
+func (s *neo4jSLN) MutateLinkProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+	link *gosln.Link, err error) {
+	link, err = write(ctx, s, func(w *writer) (*gosln.Link, error) {
+		set, remove := mutateArgs(pma)
+		return w.updateLink(id, mutateLinkProps, gosln.ID{}, set, remove)
+	})
+	return link, errors.AutoWrap(err)
+}
+
+// emit records ev to be pushed to the notifier after committing.
+func (w *writer) emit(ev notify.Event) {
+	w.events = append(w.events, ev)
+}
+
+// newIDs returns n new IDs of type t.
+//
+// The nodes and links share the serials,
+// so their IDs never collide even if they are of the same type.
+func (w *writer) newIDs(t gosln.Type, n int) ([]gosln.ID, error) {
+	v, err := single(w.ctx, w.run, allocSerials, map[string]any{
+		"type": t.String(),
+		"n":    int64(n),
+	})
+	if err != nil {
+		return nil, err
+	}
+	last, ok := v.(int64)
+	if !ok {
+		return nil, errors.AutoNew("serial is not an integer")
+	}
+	ids := make([]gosln.ID, n)
+	date := gosln.NowDate()
+	for i := range ids {
+		ids[i] = gosln.NewID(t, date, last-int64(n-1-i))
+	}
+	return ids, nil
+}
+
+// createNode creates a new node of type t with the specified properties.
+func (w *writer) createNode(t gosln.Type, props gosln.PropMap) (node *gosln.Node, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	ids, err := w.newIDs(t, 1)
+	if err != nil {
+		return nil, err
+	}
+	params, err := propsParams(ids[0], props, nil)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := w.s.queryNodes(w.ctx, w.run, "CREATE (n:"+label(t)+") SET n = $props RETURN n", params)
+	if err != nil {
+		return nil, err
+	}
+	node = nodes[0]
+	w.emit(func(obs gosln.Observer) {
+		obs.OnNodeCreated(node)
+	})
+	return node, nil
+}
+
+// upsertNode creates or updates a node of type t
+// as described in the method UpsertNode of gosln.SLN.
+func (w *writer) upsertNode(t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+	r upsertResult, err error) {
+	if !t.IsValid() {
+		return r, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	var key any
+	var present bool
+	if props != nil {
+		key, present = props.Get(keyName)
+	}
+	if !present {
+		return r, errors.AutoWrap(gosln.NewInvalidPropNameError(keyName.String()))
+	}
+	candidates, err := w.s.queryNodes(w.ctx, w.run, "MATCH (n:"+label(t)+") "+
+		"WHERE n.slnID IS NOT NULL AND n.slnDeleted IS NULL AND n[$name] = $value RETURN n",
+		map[string]any{
+			"name":  keyName.String(),
+			"value": paramValue(key),
+		})
+	if err != nil {
+		return r, err
+	}
+	// Neo4j considers an integer equal to a floating-point number
+	// with the same value, while the key must be of the same type.
+	matched := candidates[:0]
+	for _, node := range candidates {
+		if v, _ := node.Props.Get(keyName); sameNumberKind(v, key) {
+			matched = append(matched, node)
+		}
+	}
+	candidates = matched
+	if len(candidates) == 0 {
+		r.node, err = w.createNode(t, props)
+		r.created = err == nil
+		return r, err
+	}
+	sortNodes(candidates)
+	r.node, err = w.updateNode(candidates[0].ID, mergeNodeProps, gosln.ID{}, props, nil)
+	return r, err
+}
+
+// registerLinkConstraint records the constraint on the endpoint types
+// of the links of type linkType in a Neo4j node labeled "SLNLinkConstraint",
+// or removes the record if both fromTypes and toTypes are nil or empty.
+func (w *writer) registerLinkConstraint(linkType gosln.Type, fromTypes, toTypes gosln.TypeSet) error {
+	if !linkType.IsValid() {
+		return errors.AutoWrap(gosln.NewInvalidTypeError(linkType.String()))
+	}
+	from, to := typeStrings(fromTypes), typeStrings(toTypes)
+	params := map[string]any{"type": linkType.String()}
+	cypher := deleteLinkConstraint
+	if from != nil || to != nil {
+		params["from"], params["to"] = from, to
+		cypher = mergeLinkConstraint
+	}
+	_, err := collect(w.ctx, w.run, cypher, params)
+	return err
+}
+
+// checkLinkConstraint reports a *gosln.LinkConstraintError
+// if the link of type t starting from the node with ID "from"
+// and pointing to the node with ID "to" violates
+// the constraint registered for t.
+func (w *writer) checkLinkConstraint(t gosln.Type, from, to gosln.ID) error {
+	records, err := collect(w.ctx, w.run, matchLinkConstraint, map[string]any{"type": t.String()})
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	if !typeAllowed(records[0].Values[0], from.Type()) ||
+		!typeAllowed(records[0].Values[1], to.Type()) {
+		return errors.AutoWrap(gosln.NewLinkConstraintError(t, from, to))
+	}
+	return nil
+}
+
+// createLink creates a new link of type t with the specified properties,
+// starting from the node with ID "from" and
+// pointing to the node with ID "to".
+func (w *writer) createLink(t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+	link *gosln.Link, err error) {
+	if !t.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+	}
+	for _, id := range []gosln.ID{from, to} {
+		node, err := w.s.getNode(w.ctx, w.run, id)
+		if err != nil {
+			return nil, err
+		} else if node == nil || node.Deleted {
+			return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+		}
+	}
+	err = w.checkLinkConstraint(t, from, to)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := w.newIDs(t, 1)
+	if err != nil {
+		return nil, err
+	}
+	params, err := propsParams(ids[0], props, nil)
+	if err != nil {
+		return nil, err
+	}
+	params["from"], params["to"] = from.String(), to.String()
+	links, err := w.s.queryLinks(w.ctx, w.run, "MATCH (a {slnID: $from}), (b {slnID: $to}) "+
+		"CREATE (a)-[r:"+label(t)+"]->(b) SET r = $props RETURN r, a, b", params)
+	if err != nil {
+		return nil, err
+	}
+	link = withEndpoints(links[0], links[0].Props, nil, nil)
+	w.emit(func(obs gosln.Observer) {
+		obs.OnLinkCreated(link)
+	})
+	return link, nil
+}
+
+// removeNodeByID removes the node with the specified ID
+// and all associated links.
+func (w *writer) removeNodeByID(id gosln.ID) error {
+	if !id.IsValid() {
+		return nil
+	}
+	_, err := w.removeNodes([]string{id.String()})
+	return err
+}
+
+// removeNodesByCond removes all nodes that satisfy cond
+// and all associated links.
+func (w *writer) removeNodesByCond(cond gosln.NodeMatchCond) (removed int, err error) {
+	if cond == nil {
+		return 0, errors.AutoNew("node match condition is nil")
+	}
+	nodes, err := w.s.queryMatchedNodes(w.ctx, w.run, cond)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for _, node := range nodes {
+		ids = append(ids, node.ID.String())
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return w.removeNodes(ids)
+}
+
+// removeNodes removes the nodes with the specified IDs
+// and all associated links.
+//
+// It returns the number of nodes removed.
+func (w *writer) removeNodes(ids []string) (removed int, err error) {
+	linkIDs, err := queryIDs(w.ctx, w.run, matchLinkIDsOfNodes, map[string]any{"ids": ids})
+	if err != nil {
+		return 0, err
+	}
+	nodeIDs, err := queryIDs(w.ctx, w.run, deleteNodes, map[string]any{"ids": ids})
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range linkIDs {
+		id := id
+		w.emit(func(obs gosln.Observer) {
+			obs.OnLinkRemoved(id)
+		})
+	}
+	for _, id := range nodeIDs {
+		id := id
+		w.emit(func(obs gosln.Observer) {
+			obs.OnNodeRemoved(id)
+		})
+	}
+	return len(nodeIDs), nil
+}
+
+// softRemoveNodeByID marks the node with the specified ID as deleted.
+func (w *writer) softRemoveNodeByID(id gosln.ID) error {
+	if !id.IsValid() {
+		return nil
+	}
+	ids, err := queryIDs(w.ctx, w.run, softRemoveNode, map[string]any{"id": id.String()})
+	if err != nil {
+		return err
+	} else if len(ids) > 0 {
+		w.emit(func(obs gosln.Observer) {
+			obs.OnNodeSoftRemoved(id)
+		})
+	}
+	return nil
+}
+
+// restoreNodeByID restores the node with the specified ID
+// that has been soft-removed.
+func (w *writer) restoreNodeByID(id gosln.ID) error {
+	if !id.IsValid() {
+		return errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	records, err := collect(w.ctx, w.run, restoreNode, map[string]any{"id": id.String()})
+	if err != nil {
+		return err
+	} else if len(records) == 0 {
+		return errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	if deleted, _ := records[0].Values[0].(bool); deleted {
+		w.emit(func(obs gosln.Observer) {
+			obs.OnNodeRestored(id)
+		})
+	}
+	return nil
+}
+
+// removeLinkByID removes the link with the specified ID.
+func (w *writer) removeLinkByID(id gosln.ID) error {
+	if !id.IsValid() {
+		return nil
+	}
+	_, err := w.removeLinks([]string{id.String()})
+	return err
+}
+
+// removeLinksByCond removes all links that satisfy cond.
+func (w *writer) removeLinksByCond(cond gosln.LinkMatchCond) (removed int, err error) {
+	if cond == nil {
+		return 0, errors.AutoNew("link match condition is nil")
+	}
+	links, err := w.s.queryLinks(w.ctx, w.run, matchAllLinks, nil)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	for _, link := range links {
+		if cond.Match(link) {
+			ids = append(ids, link.ID.String())
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return w.removeLinks(ids)
+}
+
+// removeLinks removes the links with the specified IDs.
+//
+// It returns the number of links removed.
+func (w *writer) removeLinks(ids []string) (removed int, err error) {
+	linkIDs, err := queryIDs(w.ctx, w.run, deleteLinks, map[string]any{"ids": ids})
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range linkIDs {
+		id := id
+		w.emit(func(obs gosln.Observer) {
+			obs.OnLinkRemoved(id)
+		})
+	}
+	return len(linkIDs), nil
+}
+
+// renameType renames the type oldType to newType
+// as described in the method RenameType of gosln.SLN.
+func (w *writer) renameType(oldType, newType gosln.Type) (idMap map[gosln.ID]gosln.ID, err error) {
+	for _, t := range []gosln.Type{oldType, newType} {
+		if !t.IsValid() {
+			return nil, errors.AutoWrap(gosln.NewInvalidTypeError(t.String()))
+		}
+	}
+	oldLabel, newLabel := label(oldType), label(newType)
+	oldNodeIDs, err := queryIDs(w.ctx, w.run,
+		"MATCH (n:"+oldLabel+") WHERE n.slnID IS NOT NULL RETURN n.slnID", nil)
+	if err != nil {
+		return nil, err
+	}
+	oldLinkIDs, err := queryIDs(w.ctx, w.run,
+		"MATCH ()-[r:"+oldLabel+"]->() WHERE r.slnID IS NOT NULL RETURN r.slnID", nil)
+	if err != nil {
+		return nil, err
+	}
+	idMap = make(map[gosln.ID]gosln.ID, len(oldNodeIDs)+len(oldLinkIDs))
+	if len(oldNodeIDs) == 0 && len(oldLinkIDs) == 0 {
+		return idMap, nil
+	}
+	v, err := single(w.ctx, w.run, "OPTIONAL MATCH (n:"+newLabel+") WHERE n.slnID IS NOT NULL "+
+		"WITH count(n) AS numNode OPTIONAL MATCH ()-[r:"+newLabel+"]->() WHERE r.slnID IS NOT NULL "+
+		"RETURN numNode + count(r) > 0", nil)
+	if err != nil {
+		return nil, err
+	} else if used, _ := v.(bool); used {
+		return nil, errors.AutoNew("type " + newType.String() + " is already used")
+	}
+
+	gosln.SortIDs(oldNodeIDs)
+	gosln.SortIDs(oldLinkIDs)
+	newIDs, err := w.newIDs(newType, len(oldNodeIDs)+len(oldLinkIDs))
+	if err != nil {
+		return nil, err
+	}
+	nodePairs := make([]map[string]any, len(oldNodeIDs))
+	for i, oldID := range oldNodeIDs {
+		idMap[oldID] = newIDs[i]
+		nodePairs[i] = map[string]any{"old": oldID.String(), "new": newIDs[i].String()}
+	}
+	linkPairs := make([]map[string]any, len(oldLinkIDs))
+	for i, oldID := range oldLinkIDs {
+		newID := newIDs[len(oldNodeIDs)+i]
+		idMap[oldID] = newID
+		linkPairs[i] = map[string]any{"old": oldID.String(), "new": newID.String()}
+	}
+	if len(nodePairs) > 0 {
+		err = exec(w.ctx, w.run, "UNWIND $pairs AS p MATCH (n {slnID: p.old}) "+
+			"REMOVE n:"+oldLabel+" SET n:"+newLabel+", n.slnID = p.new",
+			map[string]any{"pairs": nodePairs})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(linkPairs) > 0 {
+		// Neo4j cannot change the type of a relationship,
+		// so recreate the relationships.
+		err = exec(w.ctx, w.run, "UNWIND $pairs AS p MATCH (a)-[r {slnID: p.old}]->(b) "+
+			"CREATE (a)-[r2:"+newLabel+"]->(b) SET r2 = properties(r), r2.slnID = p.new DELETE r",
+			map[string]any{"pairs": linkPairs})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m := make(map[gosln.ID]gosln.ID, len(idMap))
+	for k, v := range idMap {
+		m[k] = v
+	}
+	w.emit(func(obs gosln.Observer) {
+		obs.OnTypeRenamed(oldType, newType, m)
+	})
+	return idMap, nil
+}
+
+// updateNode runs the Cypher query that updates the properties
+// on the node with the specified ID and returns the node.
+//
+// The query takes the parameters "id" and "props",
+// where "props" is made from idProp, set, and remove
+// by function propsParams.
+//
+// updateNode reports a *gosln.NodeNotExistError
+// if the query returns no node.
+func (w *writer) updateNode(id gosln.ID, cypher string, idProp gosln.ID, set gosln.PropMap, remove gosln.PropNameSet) (
+	node *gosln.Node, err error) {
+	if !id.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	params, err := propsParams(idProp, set, remove)
+	if err != nil {
+		return nil, err
+	}
+	params["id"] = id.String()
+	nodes, err := w.s.queryNodes(w.ctx, w.run, cypher, params)
+	if err != nil {
+		return nil, err
+	} else if len(nodes) == 0 {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	node = nodes[0]
+	w.emit(func(obs gosln.Observer) {
+		obs.OnNodeUpdated(node)
+	})
+	return node, nil
+}
+
+// updateNodeByFunc locks the node with the specified ID,
+// calls fn with its current properties converted according to propTypes,
+// and mutates the properties by the PropMutateArg returned by fn.
+//
+// It returns the node after updating with the properties
+// converted according to propTypes.
+func (w *writer) updateNodeByFunc(
+	id gosln.ID,
+	propTypes gosln.PropTypeMap,
+	fn func(current gosln.PropMap) (gosln.PropMutateArg, error),
+) (node *gosln.Node, err error) {
+	if !id.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	nodes, err := w.s.queryNodes(w.ctx, w.run, lockNode, map[string]any{"id": id.String()})
+	if err != nil {
+		return nil, err
+	} else if len(nodes) == 0 {
+		return nil, errors.AutoWrap(gosln.NewNodeNotExistError(id))
+	}
+	node, err = exportNode(nodes[0], propTypes)
+	if err != nil {
+		return nil, err
+	}
+	pma, err := fn(node.Props)
+	if err != nil {
+		return nil, err
+	} else if pma == nil || pma.IsEmpty() {
+		return node, nil
+	}
+	node, err = w.updateNode(id, mutateNodeProps, gosln.ID{}, pma.ToBeSet(), pma.ToBeRemoved())
+	if err != nil {
+		return nil, err
+	}
+	return exportNode(node, propTypes)
+}
+
+// updateLink runs the Cypher query that updates the properties
+// on the link with the specified ID and returns the link.
+//
+// The query takes the parameters "id" and "props",
+// where "props" is made from idProp, set, and remove
+// by function propsParams.
+//
+// updateLink reports a *gosln.LinkNotExistError
+// if the query returns no link.
+func (w *writer) updateLink(id gosln.ID, cypher string, idProp gosln.ID, set gosln.PropMap, remove gosln.PropNameSet) (
+	link *gosln.Link, err error) {
+	if !id.IsValid() {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	params, err := propsParams(idProp, set, remove)
+	if err != nil {
+		return nil, err
+	}
+	params["id"] = id.String()
+	links, err := w.s.queryLinks(w.ctx, w.run, cypher, params)
+	if err != nil {
+		return nil, err
+	} else if len(links) == 0 {
+		return nil, errors.AutoWrap(gosln.NewLinkNotExistError(id))
+	}
+	link = withEndpoints(links[0], links[0].Props, nil, nil)
+	w.emit(func(obs gosln.Observer) {
+		obs.OnLinkUpdated(link)
+	})
+	return link, nil
+}
+
+// propsParams returns a parameter map whose parameter "props"
+// is made by function makeParameterMap from id, props, and remove.
+//
+// The parameter "props" is never nil.
+func propsParams(id gosln.ID, props gosln.PropMap, remove gosln.PropNameSet) (
+	params map[string]any, err error) {
+	params, err = makeParameterMap("props", id, props, remove)
+	if err != nil {
+		return nil, err
+	}
+	if params["props"] == nil {
+		params["props"] = map[string]any{}
+	}
+	return params, nil
+}
+
+// mutateArgs returns the properties to be set and
+// the property names to be removed in pma.
+//
+// If pma is nil, it returns (nil, nil).
+func mutateArgs(pma gosln.PropMutateArg) (set gosln.PropMap, remove gosln.PropNameSet) {
+	if pma == nil {
+		return nil, nil
+	}
+	return pma.ToBeSet(), pma.ToBeRemoved()
+}
+
+// queryIDs runs the Cypher query that returns SLN IDs
+// in the first column, and returns the IDs.
+func queryIDs(ctx context.Context, run runner, cypher string, params map[string]any) (
+	ids []gosln.ID, err error) {
+	records, err := collect(ctx, run, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	ids = make([]gosln.ID, len(records))
+	for i, record := range records {
+		idStr, _ := record.Values[0].(string)
+		ids[i], err = gosln.ParseID(idStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// exec runs the Cypher query and discards its result.
+func exec(ctx context.Context, run runner, cypher string, params map[string]any) error {
+	result, err := run.Run(ctx, cypher, params)
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume(ctx)
+	return err
+}
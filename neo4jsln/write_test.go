@@ -0,0 +1,86 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package neo4jsln
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/donyori/gosln"
+)
+
+func TestUpsertNode_NumberKind(t *testing.T) {
+	ctx := context.Background()
+	person := gosln.MustNewType("Person")
+	existingID := gosln.NewID(person, gosln.DateOfYearMonthDay(2023, 1, 2), 1)
+	key := gosln.MustNewPropName("key")
+	testCases := []struct {
+		name        string
+		stored, key any
+		wantCreated bool
+	}{
+		{"int64 and float64", 1.0, int64(1), true},
+		{"float64 and int64", int64(1), 1.0, true},
+		{"int64 and int", int64(1), 1, false},
+		{"float64 and float64", 1.0, 1.0, false},
+		{"int64 list and float64 list", []int64{1}, []float64{1}, true},
+		{"int64 list and int list", []int64{1}, []int{1}, false},
+		{"empty list", []string{}, []int64{}, false},
+		{"string", "a", "a", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := map[string]any{slnIDPropName: existingID.String(), "key": tc.stored}
+			// The fake database does not evaluate the conditions,
+			// as if Neo4j considered the stored key equal to tc.key.
+			fs := &fakeSession{run: func(cypher string, params map[string]any) ([]*neo4j.Record, error) {
+				switch {
+				case cypher == allocSerials:
+					return []*neo4j.Record{{Keys: []string{"c.serial"}, Values: []any{int64(2)}}}, nil
+				case strings.HasPrefix(cypher, "CREATE "):
+					return nodeRecords(params["props"].(map[string]any))(cypher, params)
+				}
+				return nodeRecords(existing)(cypher, params)
+			}}
+			s := newFakeSLN(fs, 0, 0)
+			defer func() {
+				if err := s.Close(); err != nil {
+					t.Error("close -", err)
+				}
+			}()
+			props := gosln.NewPropMap(1)
+			props.Set(key, tc.key)
+			r, err := write(ctx, s, func(w *writer) (upsertResult, error) {
+				return w.upsertNode(person, key, props)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.created != tc.wantCreated {
+				t.Errorf("got created %t; want %t", r.created, tc.wantCreated)
+			}
+			if gotExisting := r.node.ID == existingID; gotExisting == tc.wantCreated {
+				t.Errorf("got node %v; want existing node %t", r.node.ID, !tc.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import "github.com/donyori/gogo/container/mapping"
+
+// NodeToMap converts the semantic node to a map
+// for consumers that prefer untyped data, such as templates and JSON APIs.
+//
+// The returned map has the following shape:
+//
+//	{"id": <ID string>, "type": <type string>, "props": <map[string]any>}
+//
+// where the keys of "props" are the property names.
+// If raw is false, the property values are rendered as strings
+// via the function FormatPropValue.
+// Otherwise, they are the original values,
+// with []byte values copied.
+//
+// If node is nil, NodeToMap returns nil.
+func NodeToMap(node *Node, raw bool) map[string]any {
+	if node == nil {
+		return nil
+	}
+	var props map[string]any
+	if raw {
+		props = PropMapToGoMap(node.Props)
+	} else {
+		props = formatPropMap(node.Props)
+	}
+	if props == nil {
+		props = make(map[string]any)
+	}
+	return map[string]any{
+		"id":    node.ID.String(),
+		"type":  node.Type.String(),
+		"props": props,
+	}
+}
+
+// NodesToMaps converts the semantic nodes to maps
+// by calling the function NodeToMap on each node.
+//
+// The i-th map corresponds to the i-th node.
+// In particular, the map corresponding to a nil node is nil.
+//
+// If nodes is nil, NodesToMaps returns nil.
+func NodesToMaps(nodes []*Node, raw bool) []map[string]any {
+	if nodes == nil {
+		return nil
+	}
+	maps := make([]map[string]any, len(nodes))
+	for i, node := range nodes {
+		maps[i] = NodeToMap(node, raw)
+	}
+	return maps
+}
+
+// formatPropMap converts pm to a map from the property names
+// to the property values rendered via function FormatPropValue.
+//
+// It returns an empty map if pm is nil.
+func formatPropMap(pm PropMap) map[string]any {
+	if pm == nil {
+		return make(map[string]any)
+	}
+	m := make(map[string]any, pm.Len())
+	pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		m[x.Key.String()] = FormatPropValue(x.Value)
+		return true
+	})
+	return m
+}
@@ -0,0 +1,103 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestNodeToMap(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	name := gosln.MustNewPropName("name")
+	avatar := gosln.MustNewPropName("avatar")
+	birthday := gosln.MustNewPropName("birthday")
+	age := gosln.MustNewPropName("age")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	id := gosln.NewID(person, date, 1)
+	props := gosln.NewPropMap(4)
+	props.Set(name, "Alice")
+	props.Set(avatar, []byte("hi"))
+	props.Set(birthday, date)
+	props.Set(age, 30)
+	node := &gosln.Node{NL: gosln.NL{ID: id, Type: person, Props: props}}
+
+	testCases := []struct {
+		raw  bool
+		want map[string]any
+	}{
+		{false, map[string]any{
+			"name":     "Alice",
+			"avatar":   "aGk=",
+			"birthday": "2023-071",
+			"age":      "30",
+		}},
+		{true, map[string]any{
+			"name":     "Alice",
+			"avatar":   []byte("hi"),
+			"birthday": date,
+			"age":      30,
+		}},
+	}
+
+	for _, tc := range testCases {
+		name := "formatted"
+		if tc.raw {
+			name = "raw"
+		}
+		t.Run(name, func(t *testing.T) {
+			m := gosln.NodeToMap(node, tc.raw)
+			if len(m) != 3 {
+				t.Errorf("got %d keys; want 3", len(m))
+			}
+			if m["id"] != id.String() || m["type"] != person.String() {
+				t.Errorf("got id %v, type %v; want %v, %v", m["id"], m["type"], id, person)
+			}
+			gotProps, ok := m["props"].(map[string]any)
+			if !ok {
+				t.Fatalf("got props of type %T; want map[string]any", m["props"])
+			}
+			if len(gotProps) != len(tc.want) {
+				t.Errorf("got %d props; want %d", len(gotProps), len(tc.want))
+			}
+			for k, want := range tc.want {
+				got := gotProps[k]
+				if wantBytes, ok := want.([]byte); ok {
+					gotBytes, ok := got.([]byte)
+					if !ok || !bytes.Equal(gotBytes, wantBytes) {
+						t.Errorf("%s - got %#v; want %#v", k, got, want)
+					}
+				} else if got != want {
+					t.Errorf("%s - got %#v; want %#v", k, got, want)
+				}
+			}
+		})
+	}
+
+	if m := gosln.NodeToMap(nil, false); m != nil {
+		t.Errorf("nil node - got %v; want nil", m)
+	}
+	maps := gosln.NodesToMaps([]*gosln.Node{node, nil}, false)
+	if len(maps) != 2 || maps[0]["id"] != id.String() || maps[1] != nil {
+		t.Errorf("NodesToMaps - got %v", maps)
+	}
+}
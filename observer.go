@@ -0,0 +1,103 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+// Observer is notified of the changes to nodes and links in an SLN.
+//
+// It is registered by the method RegisterObserver of SLN.
+//
+// The SLN notifies the observer after the change is applied,
+// outside its critical section, in the order in which
+// the changes are applied.
+// The nodes and links passed to the observer are copies of
+// those in the SLN, but may be shared with other observers,
+// so the observer should not modify them.
+//
+// The implementation of SLN decides which goroutine
+// calls the methods of the observer (see its documentation),
+// so the methods should return quickly
+// and must be safe to call from a goroutine other than the one
+// that changed the SLN.
+//
+// To implement only some of the methods, embed NopObserver.
+type Observer interface {
+	// OnNodeCreated is called after a node is created.
+	OnNodeCreated(node *Node)
+
+	// OnNodeUpdated is called after the properties on a node are updated.
+	//
+	// node has all the properties after updating.
+	OnNodeUpdated(node *Node)
+
+	// OnNodeRemoved is called after a node is removed.
+	//
+	// The links associated with the node are reported
+	// by OnLinkRemoved before OnNodeRemoved.
+	OnNodeRemoved(id ID)
+
+	// OnNodeSoftRemoved is called after a node is soft-removed.
+	OnNodeSoftRemoved(id ID)
+
+	// OnNodeRestored is called after a soft-removed node is restored.
+	OnNodeRestored(id ID)
+
+	// OnLinkCreated is called after a link is created.
+	OnLinkCreated(link *Link)
+
+	// OnLinkUpdated is called after the properties on a link are updated.
+	//
+	// link has all the properties after updating.
+	OnLinkUpdated(link *Link)
+
+	// OnLinkRemoved is called after a link is removed.
+	OnLinkRemoved(id ID)
+
+	// OnTypeRenamed is called after the type oldType is renamed to newType.
+	//
+	// idMap is the mapping from the old IDs to the new IDs
+	// of the nodes and links affected.
+	OnTypeRenamed(oldType, newType Type, idMap map[ID]ID)
+}
+
+// NopObserver is an implementation of interface Observer
+// whose methods do nothing.
+//
+// It can be embedded in a struct to implement
+// only some of the methods of Observer.
+type NopObserver struct{}
+
+var _ Observer = NopObserver{}
+
+func (NopObserver) OnNodeCreated(*Node) {}
+
+func (NopObserver) OnNodeUpdated(*Node) {}
+
+func (NopObserver) OnNodeRemoved(ID) {}
+
+func (NopObserver) OnNodeSoftRemoved(ID) {}
+
+func (NopObserver) OnNodeRestored(ID) {}
+
+func (NopObserver) OnLinkCreated(*Link) {}
+
+func (NopObserver) OnLinkUpdated(*Link) {}
+
+func (NopObserver) OnLinkRemoved(ID) {}
+
+func (NopObserver) OnTypeRenamed(Type, Type, map[ID]ID) {}
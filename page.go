@@ -0,0 +1,222 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Page specifies a page of the query results,
+// used by the methods GetNodesPage and GetLinksPage of ReadOnlySLN.
+//
+// The results are sorted first,
+// and then the Limit results starting from Offset are taken.
+type Page struct {
+	// Offset is the number of results to skip.
+	//
+	// A negative Offset is treated as 0.
+	// If Offset is beyond the number of results, the page is empty.
+	Offset int
+
+	// Limit is the maximum number of results in the page.
+	//
+	// A non-positive Limit means no limit.
+	Limit int
+
+	// SortKey is the name of the property by which the results are sorted.
+	//
+	// If SortKey is invalid (such as zero-value),
+	// the results are sorted by their IDs.
+	//
+	// Otherwise, the results are sorted by the values of the property,
+	// and the results with the same value are sorted by their IDs.
+	// The values are compared as follows:
+	// real numbers are compared numerically,
+	// byte strings ([]byte and string) are compared lexicographically,
+	// and time.Time and Date values are compared chronologically.
+	// The values of different kinds are ordered as
+	// real numbers, byte strings, time.Time values, and Date values.
+	// The results without the property or with other values
+	// (such as booleans and NaN) are placed last.
+	SortKey PropName
+
+	// Descending indicates whether to sort the results in descending order.
+	//
+	// The results without the property specified by SortKey
+	// or with incomparable values are always placed last,
+	// and the results with the same value are always
+	// sorted by their IDs in ascending order.
+	Descending bool
+}
+
+// Bounds returns the bounds of the page in the results
+// with the specified total number,
+// that is, the page is results[start:end].
+//
+// It guarantees 0 <= start <= end <= total,
+// provided that total is non-negative.
+func (p Page) Bounds(total int) (start, end int) {
+	start = p.Offset
+	if start < 0 {
+		start = 0
+	} else if start > total {
+		start = total
+	}
+	end = total
+	if p.Limit > 0 && p.Limit < end-start {
+		end = start + p.Limit
+	}
+	return
+}
+
+// PageNodes sorts nodes as specified by page
+// and returns the nodes in the page.
+//
+// It sorts nodes in place,
+// and the returned slice shares the underlying array with nodes.
+func PageNodes(nodes []*Node, page Page) []*Node {
+	sortForPage(nodes, func(x *Node) *NL {
+		return &x.NL
+	}, page)
+	start, end := page.Bounds(len(nodes))
+	return nodes[start:end]
+}
+
+// PageLinks sorts links as specified by page
+// and returns the links in the page.
+//
+// It sorts links in place,
+// and the returned slice shares the underlying array with links.
+func PageLinks(links []*Link, page Page) []*Link {
+	sortForPage(links, func(x *Link) *NL {
+		return &x.NL
+	}, page)
+	start, end := page.Bounds(len(links))
+	return links[start:end]
+}
+
+// sortForPage sorts the nodes or links in s as specified by page.
+//
+// nl returns the NL of a node or link in s.
+func sortForPage[T any](s []T, nl func(x T) *NL, page Page) {
+	sort.Slice(s, func(i, j int) bool {
+		return lessForPage(nl(s[i]), nl(s[j]), page)
+	})
+}
+
+// lessForPage reports whether a is placed before b
+// as specified by page.
+func lessForPage(a, b *NL, page Page) bool {
+	if !page.SortKey.IsValid() {
+		if page.Descending {
+			return a.ID.Compare(b.ID) > 0
+		}
+		return a.ID.Compare(b.ID) < 0
+	}
+	var x, y any
+	if a.Props != nil {
+		x, _ = a.Props.Get(page.SortKey)
+	}
+	if b.Props != nil {
+		y, _ = b.Props.Get(page.SortKey)
+	}
+	switch r := comparePageSortValues(x, y); {
+	case r == pageSortIncomparable:
+		return false
+	case r == -pageSortIncomparable:
+		return true
+	case r != 0 && page.Descending:
+		return r > 0
+	case r != 0:
+		return r < 0
+	}
+	return a.ID.Compare(b.ID) < 0
+}
+
+// pageSortIncomparable is returned by function comparePageSortValues
+// if a is placed after b regardless of the sort direction.
+// Its negative counterpart indicates that a is placed before b
+// regardless of the sort direction.
+const pageSortIncomparable = 2
+
+// comparePageSortValues compares the property values a and b
+// for sorting as specified by the field SortKey of Page.
+//
+// It returns -1, 0, or +1 if a is less than, equal to, or greater than b,
+// respectively, in the same kind.
+// If a and b are of different kinds,
+// it returns pageSortIncomparable if a is placed after b,
+// and -pageSortIncomparable if a is placed before b.
+// If neither a nor b is comparable (including absent), it returns 0.
+func comparePageSortValues(a, b any) int {
+	aRank, bRank := pageSortRank(a), pageSortRank(b)
+	switch {
+	case aRank < bRank:
+		return -pageSortIncomparable
+	case aRank > bRank:
+		return pageSortIncomparable
+	case aRank == pageSortRankOther:
+		return 0
+	case aRank == pageSortRankByteString:
+		return strings.Compare(byteStringToString(a), byteStringToString(b))
+	}
+	r, _ := compareOrderedPropValues(a, b)
+	return r
+}
+
+// Ranks of the kinds of property values for sorting.
+const (
+	pageSortRankRealNumber = iota
+	pageSortRankByteString
+	pageSortRankTime
+	pageSortRankDate
+	pageSortRankOther
+)
+
+// pageSortRank returns the rank of the kind of the property value v
+// for sorting.
+func pageSortRank(v any) int {
+	pt := PropTypeOf(v)
+	switch {
+	case pt.IsRealNumber():
+		if _, ok := compareOrderedPropValues(v, v); ok {
+			return pageSortRankRealNumber
+		}
+	case pt.IsByteString():
+		return pageSortRankByteString
+	case pt == PTTime:
+		return pageSortRankTime
+	case pt == PTDate:
+		return pageSortRankDate
+	}
+	return pageSortRankOther
+}
+
+// byteStringToString converts the byte string v to a string.
+//
+// The caller should guarantee that v is a byte string.
+func byteStringToString(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	return string(rv.Bytes())
+}
@@ -0,0 +1,95 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/donyori/gosln"
+)
+
+func TestPage_Bounds(t *testing.T) {
+	testCases := []struct {
+		page               gosln.Page
+		total              int
+		wantStart, wantEnd int
+	}{
+		{gosln.Page{}, 5, 0, 5},
+		{gosln.Page{Offset: 2}, 5, 2, 5},
+		{gosln.Page{Offset: 2, Limit: 2}, 5, 2, 4},
+		{gosln.Page{Offset: 4, Limit: 3}, 5, 4, 5},
+		{gosln.Page{Offset: 6, Limit: 3}, 5, 5, 5},
+		{gosln.Page{Offset: -1, Limit: -1}, 5, 0, 5},
+		{gosln.Page{Limit: 3}, 0, 0, 0},
+	}
+	for _, tc := range testCases {
+		start, end := tc.page.Bounds(tc.total)
+		if start != tc.wantStart || end != tc.wantEnd {
+			t.Errorf("%+v, total %d - got [%d:%d]; want [%d:%d]",
+				tc.page, tc.total, start, end, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func TestPageNodes(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	score := gosln.MustNewPropName("score")
+	values := []any{3, "b", 1.5, nil, uint8(7), math.NaN(), "a", 3}
+	newNodes := func() []*gosln.Node {
+		nodes := make([]*gosln.Node, len(values))
+		for i, v := range values {
+			props := gosln.NewPropMap(1)
+			if v != nil {
+				props.Set(score, v)
+			}
+			nodes[i] = &gosln.Node{NL: gosln.NL{
+				ID:    gosln.NewID(person, gosln.NowDate(), int64(i)),
+				Type:  person,
+				Props: props,
+			}}
+		}
+		return nodes
+	}
+
+	testCases := []struct {
+		name string
+		page gosln.Page
+		want []int64 // serials of the nodes in the page
+	}{
+		{"by ID", gosln.Page{Offset: 1, Limit: 3}, []int64{1, 2, 3}},
+		{"by ID desc", gosln.Page{Limit: 2, Descending: true}, []int64{7, 6}},
+		{"by key", gosln.Page{SortKey: score}, []int64{2, 0, 7, 4, 6, 1, 3, 5}},
+		{"by key desc", gosln.Page{SortKey: score, Descending: true}, []int64{4, 0, 7, 2, 1, 6, 3, 5}},
+		{"beyond", gosln.Page{Offset: 10, SortKey: score}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := gosln.PageNodes(newNodes(), tc.page)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d nodes; want %d", len(got), len(tc.want))
+			}
+			for i, node := range got {
+				if serial, _ := node.ID.Serial(); serial != tc.want[i] {
+					t.Errorf("got node %d at %d; want %d", serial, i, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -19,7 +19,10 @@
 package gosln
 
 import (
+	"bytes"
+	"math"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/donyori/gogo/constraints"
@@ -38,11 +41,16 @@ import (
 //   - Built-in complex numbers: complex64, complex128.
 //   - Byte strings: []byte, string.
 //   - Temporal: time.Time, gosln.Date.
+//   - Slices of scalars: []bool, []int, []int64, []float64, []string.
+//
+// A slice of scalars holds multiple values of a property,
+// such as the tags of a node.
 type PropValue interface {
 	bool |
 		constraints.PredeclaredNumeric |
 		constraints.PredeclaredByteString |
-		time.Time | Date
+		time.Time | Date |
+		[]bool | []int | []int64 | []float64 | []string
 }
 
 // PropMap is a property name-value map,
@@ -75,6 +83,10 @@ type PropMap interface {
 
 // NewPropMap creates a new PropMap.
 //
+// The returned map is not safe for concurrent use by multiple goroutines
+// if any of them modifies the map.
+// To share a PropMap among goroutines, use NewConcurrentPropMap instead.
+//
 // The method Range of the map accesses properties in random order.
 // The access order in two calls to Range may be different.
 //
@@ -93,46 +105,269 @@ func NewPropMap(capacity int) PropMap {
 		func(value any) bool {
 			return PropTypeOf(value).IsValid()
 		},
-		func(value any) error {
-			return NewInvalidPropValueError(value)
+		func(key PropName, value any) error {
+			return NewInvalidPropValueError(key, value)
 		},
 	)
 }
 
-// mutExclPropMap is an implementation of interface PropMap.
+// NewConcurrentPropMap creates a new PropMap
+// that is safe for concurrent use by multiple goroutines.
+//
+// The map validates the properties in the same way as
+// the map returned by NewPropMap, and guards its content
+// with a sync.RWMutex.
+// Each method call is atomic.
+// The method Range holds the read lock while calling its handler,
+// and the method Filter holds the write lock while calling its filter,
+// so the handler and filter must not call any method of the map,
+// or they deadlock.
+//
+// The method Range of the map accesses properties in random order.
+// The access order in two calls to Range may be different.
+//
+// capacity asks to allocate enough space to hold
+// the specified number of properties.
+// If capacity is negative, it is ignored.
+func NewConcurrentPropMap(capacity int) PropMap {
+	return &concurrentMap[PropName, any]{m: NewPropMap(capacity)}
+}
+
+// FilteredPropMap returns a new PropMap containing
+// the properties in pm that satisfy keep.
+//
+// It does not modify pm.
+// The []byte values and the slices of scalars are copied so that
+// the returned PropMap does not share them with pm.
+// If keep is nil, all properties in pm are kept.
+// If pm is nil, it returns an empty PropMap.
+func FilteredPropMap(pm PropMap, keep func(name PropName, value any) bool) PropMap {
+	r := NewPropMap(0)
+	if pm != nil {
+		pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+			if keep == nil || keep(x.Key, x.Value) {
+				r.Set(x.Key, copyPropValue(x.Value))
+			}
+			return true
+		})
+	}
+	return r
+}
+
+// ClonePropMap returns a copy of pm.
+//
+// It allocates a new PropMap and copies every property in pm to it.
+// The []byte values and the slices of scalars are copied so that
+// the returned PropMap does not share them with pm.
+// The time.Time and Date values are immutable and copied by value.
+//
+// If pm is nil, ClonePropMap returns nil.
+func ClonePropMap(pm PropMap) PropMap {
+	if pm == nil {
+		return nil
+	}
+	c := NewPropMap(pm.Len())
+	pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		c.Set(x.Key, copyPropValue(x.Value))
+		return true
+	})
+	return c
+}
+
+// MergePropMaps returns a new PropMap holding the union of
+// the properties in base and overlay.
+//
+// If a property name is in both base and overlay,
+// the value in overlay wins.
+// The []byte values and the slices of scalars are copied so that
+// the returned PropMap does not share them with base or overlay.
+//
+// It does not modify base and overlay.
+// A nil PropMap is treated as an empty PropMap.
+// In particular, if both base and overlay are nil,
+// it returns an empty PropMap.
+func MergePropMaps(base, overlay PropMap) PropMap {
+	var n int
+	if base != nil {
+		n = base.Len()
+	}
+	if overlay != nil && overlay.Len() > n {
+		n = overlay.Len()
+	}
+	r := NewPropMap(n)
+	for _, pm := range [...]PropMap{base, overlay} {
+		if pm != nil {
+			pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+				r.Set(x.Key, copyPropValue(x.Value))
+				return true
+			})
+		}
+	}
+	return r
+}
+
+// EqualPropMaps reports whether a and b hold the same properties,
+// that is, the same property names with equal values.
+//
+// The []byte values are compared with bytes.Equal,
+// the time.Time values are compared with the method Equal of time.Time,
+// and the slices of scalars are compared element by element.
+// The other values are compared with operator ==.
+// Values of different types are not equal.
+//
+// A nil PropMap is treated as an empty PropMap.
+// In particular, a nil PropMap and an empty PropMap are equal.
+func EqualPropMaps(a, b PropMap) bool {
+	var aLen, bLen int
+	if a != nil {
+		aLen = a.Len()
+	}
+	if b != nil {
+		bLen = b.Len()
+	}
+	if aLen != bLen {
+		return false
+	} else if aLen == 0 {
+		return true
+	}
+	ok := true
+	a.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		var value any
+		value, ok = b.Get(x.Key)
+		ok = ok && equalPropValues(x.Value, value)
+		return ok
+	})
+	return ok
+}
+
+// PropMapToGoMap converts pm to a Go map
+// from the property name strings to the property values.
+//
+// The []byte values and the slices of scalars are copied so that
+// the returned map does not share them with pm.
+//
+// If pm is nil, PropMapToGoMap returns nil.
+func PropMapToGoMap(pm PropMap) map[string]any {
+	if pm == nil {
+		return nil
+	}
+	m := make(map[string]any, pm.Len())
+	pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		m[x.Key.String()] = copyPropValue(x.Value)
+		return true
+	})
+	return m
+}
+
+// PropMapFromGoMap converts the Go map m,
+// from the property name strings to the property values,
+// to a new PropMap.
+//
+// The []byte values and the slices of scalars are copied so that
+// the returned PropMap does not share them with m.
+// If m is nil, it returns an empty PropMap.
+//
+// If any key of m is an invalid property name,
+// it reports a *InvalidPropNameError.
+// If any value of m does not conform to PropValue,
+// it reports a *InvalidPropValueError.
+// (To test the type of err, use function errors.As.)
+func PropMapFromGoMap(m map[string]any) (PropMap, error) {
+	pm := NewPropMap(len(m))
+	for k, v := range m {
+		name, err := NewPropName(k)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		} else if !PropTypeOf(v).IsValid() {
+			return nil, errors.AutoWrap(NewInvalidPropValueError(name, v))
+		}
+		pm.Set(name, copyPropValue(v))
+	}
+	return pm, nil
+}
+
+// ValidatePropMap checks the properties in pm against
+// the property types declared in schema,
+// to detect type mismatches on the client side
+// before sending pm to an SLN (e.g., by the method SetNodeProperties).
+//
+// The properties in pm absent from schema are not checked.
+// If requireAll is true, every property declared in schema
+// must be present in pm.
+// A nil pm is treated as empty.
+// If schema is nil, ValidatePropMap returns nil.
+//
+// ValidatePropMap reports a *PropTypeError if the type of
+// any property value in pm cannot convert to the type
+// declared in schema (i.e., PropTypeOf(value).IsConvertibleTo
+// returns false).
+// (To test whether err is *PropTypeError, use function errors.As.)
+//
+// ValidatePropMap reports a *PropNotExistError if requireAll is true
+// and any property declared in schema is absent from pm.
+// (To test whether err is *PropNotExistError, use function errors.As.)
+//
+// The properties are checked in ascending order of their names,
+// and the error of the first failed property is returned.
+func ValidatePropMap(pm PropMap, schema PropTypeMap, requireAll bool) error {
+	if schema == nil {
+		return nil
+	}
+	for _, name := range sortedPropMapNames[PropType](schema, nil) {
+		var value any
+		var present bool
+		if pm != nil {
+			value, present = pm.Get(name)
+		}
+		if !present {
+			if requireAll {
+				return errors.AutoWrap(NewPropNotExistError(name))
+			}
+			continue
+		}
+		pt, _ := schema.Get(name)
+		if !PropTypeOf(value).IsConvertibleTo(pt) {
+			return errors.AutoWrap(NewPropTypeError(name, value, pt.GoType()))
+		}
+	}
+	return nil
+}
+
+// mutExclMap is a map from property names to values of type V.
+// In particular, *mutExclMap[any] is an implementation of interface PropMap.
 //
 // It can associate with one or more collections
 // that have the method Remove(...PropName).
 // When a property is put into this map,
-// mutExclPropMap removes the property name from these collections.
+// mutExclMap removes the property name from these collections.
 //
 // The client must call its method init to initialize
-// the mutExclPropMap before use.
-type mutExclPropMap struct {
-	m PropMap
+// the mutExclMap before use.
+type mutExclMap[V any] struct {
+	m mapping.Map[PropName, V]
 	r []interface{ Remove(...PropName) }
 }
 
-// init initializes the mutExclPropMap
-// with the specified capacity and collections.
+// init initializes the mutExclMap
+// with the specified underlying map and collections.
 //
-// capacity asks to allocate enough space to hold
-// the specified number of properties.
-// If capacity is negative, it is ignored.
+// m is the underlying map to hold the properties,
+// which must be non-nil and should be empty.
+// For a PropMap, use the function NewPropMap to create it.
 //
 // collection is a list of collections associated with this map.
 // When a property is put into this map,
-// mutExclPropMap removes the property name from these collections.
-func (mepm *mutExclPropMap) init(capacity int,
+// mutExclMap removes the property name from these collections.
+func (mepm *mutExclMap[V]) init(m mapping.Map[PropName, V],
 	collection ...interface{ Remove(...PropName) }) {
-	mepm.m = NewPropMap(capacity)
+	mepm.m = m
 	if len(collection) > 0 {
 		mepm.r = make([]interface{ Remove(...PropName) }, len(collection))
 		copy(mepm.r, collection)
 	}
 }
 
-func (mepm *mutExclPropMap) Len() int {
+func (mepm *mutExclMap[V]) Len() int {
 	mepm.checkInit()
 	return mepm.m.Len()
 }
@@ -144,89 +379,89 @@ func (mepm *mutExclPropMap) Len() int {
 // Its parameter handler is a function to deal with the property
 // with the specified name and value in the map and
 // report whether to continue to access the next property.
-func (mepm *mutExclPropMap) Range(
-	handler func(x mapping.Entry[PropName, any]) (cont bool)) {
+func (mepm *mutExclMap[V]) Range(
+	handler func(x mapping.Entry[PropName, V]) (cont bool)) {
 	mepm.checkInit()
 	mepm.m.Range(handler)
 }
 
-func (mepm *mutExclPropMap) Filter(
-	filter func(x mapping.Entry[PropName, any]) (keep bool)) {
+func (mepm *mutExclMap[V]) Filter(
+	filter func(x mapping.Entry[PropName, V]) (keep bool)) {
 	mepm.checkInit()
 	mepm.m.Filter(filter)
 }
 
-func (mepm *mutExclPropMap) Get(key PropName) (value any, present bool) {
+func (mepm *mutExclMap[V]) Get(key PropName) (value V, present bool) {
 	mepm.checkInit()
 	return mepm.m.Get(key)
 }
 
-func (mepm *mutExclPropMap) Set(key PropName, value any) {
+func (mepm *mutExclMap[V]) Set(key PropName, value V) {
 	mepm.checkInit()
 	mepm.m.Set(key, value)
 	mepm.removeFromOthers(key)
 }
 
-func (mepm *mutExclPropMap) GetAndSet(key PropName, value any) (
-	previous any, present bool) {
+func (mepm *mutExclMap[V]) GetAndSet(key PropName, value V) (
+	previous V, present bool) {
 	mepm.checkInit()
 	previous, present = mepm.m.GetAndSet(key, value)
 	mepm.removeFromOthers(key)
 	return
 }
 
-func (mepm *mutExclPropMap) SetMap(m mapping.Map[PropName, any]) {
+func (mepm *mutExclMap[V]) SetMap(m mapping.Map[PropName, V]) {
 	mepm.checkInit()
 	if m == nil || m.Len() == 0 {
 		return
 	}
 	mepm.m.SetMap(m)
-	m.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+	m.Range(func(x mapping.Entry[PropName, V]) (cont bool) {
 		mepm.removeFromOthers(x.Key)
 		return true
 	})
 }
 
-func (mepm *mutExclPropMap) GetAndSetMap(m mapping.Map[PropName, any]) (
-	previous mapping.Map[PropName, any]) {
+func (mepm *mutExclMap[V]) GetAndSetMap(m mapping.Map[PropName, V]) (
+	previous mapping.Map[PropName, V]) {
 	mepm.checkInit()
 	if m == nil || m.Len() == 0 {
 		return
 	}
 	previous = mepm.m.GetAndSetMap(m)
-	m.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+	m.Range(func(x mapping.Entry[PropName, V]) (cont bool) {
 		mepm.removeFromOthers(x.Key)
 		return true
 	})
 	return
 }
 
-func (mepm *mutExclPropMap) Remove(key ...PropName) {
+func (mepm *mutExclMap[V]) Remove(key ...PropName) {
 	mepm.checkInit()
 	mepm.m.Remove(key...)
 }
 
-func (mepm *mutExclPropMap) GetAndRemove(key PropName) (
-	previous any, present bool) {
+func (mepm *mutExclMap[V]) GetAndRemove(key PropName) (
+	previous V, present bool) {
 	mepm.checkInit()
 	return mepm.m.GetAndRemove(key)
 }
 
-func (mepm *mutExclPropMap) Clear() {
+func (mepm *mutExclMap[V]) Clear() {
 	mepm.checkInit()
 	mepm.m.Clear()
 }
 
 // checkInit checks whether mepm is initialized.
 // If not, it panics.
-func (mepm *mutExclPropMap) checkInit() {
+func (mepm *mutExclMap[V]) checkInit() {
 	if mepm.m == nil {
 		panic(errors.AutoMsgCustom("not initialized before use", -1, 1))
 	}
 }
 
 // removeFromOthers removes name from collections in mepm.r.
-func (mepm *mutExclPropMap) removeFromOthers(name ...PropName) {
+func (mepm *mutExclMap[V]) removeFromOthers(name ...PropName) {
 	if len(name) > 0 {
 		for _, r := range mepm.r {
 			r.Remove(name...)
@@ -234,6 +469,88 @@ func (mepm *mutExclPropMap) removeFromOthers(name ...PropName) {
 	}
 }
 
+// concurrentMap is a wrapper of mapping.Map
+// that is safe for concurrent use by multiple goroutines.
+// In particular, *concurrentMap[PropName, any] is
+// an implementation of interface PropMap.
+type concurrentMap[Key comparable, Value any] struct {
+	mu sync.RWMutex
+	m  mapping.Map[Key, Value]
+}
+
+func (cm *concurrentMap[Key, Value]) Len() int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.m.Len()
+}
+
+func (cm *concurrentMap[Key, Value]) Range(
+	handler func(x mapping.Entry[Key, Value]) (cont bool)) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	cm.m.Range(handler)
+}
+
+func (cm *concurrentMap[Key, Value]) Filter(
+	filter func(x mapping.Entry[Key, Value]) (keep bool)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.m.Filter(filter)
+}
+
+func (cm *concurrentMap[Key, Value]) Get(key Key) (value Value, present bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.m.Get(key)
+}
+
+func (cm *concurrentMap[Key, Value]) Set(key Key, value Value) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.m.Set(key, value)
+}
+
+func (cm *concurrentMap[Key, Value]) GetAndSet(key Key, value Value) (
+	previous Value, present bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.m.GetAndSet(key, value)
+}
+
+func (cm *concurrentMap[Key, Value]) SetMap(m mapping.Map[Key, Value]) {
+	m = copyMap(m) // avoid deadlock if m is cm or locks cm in its methods
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.m.SetMap(m)
+}
+
+func (cm *concurrentMap[Key, Value]) GetAndSetMap(m mapping.Map[Key, Value]) (
+	previous mapping.Map[Key, Value]) {
+	m = copyMap(m) // avoid deadlock if m is cm or locks cm in its methods
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.m.GetAndSetMap(m)
+}
+
+func (cm *concurrentMap[Key, Value]) Remove(key ...Key) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.m.Remove(key...)
+}
+
+func (cm *concurrentMap[Key, Value]) GetAndRemove(key Key) (
+	previous Value, present bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.m.GetAndRemove(key)
+}
+
+func (cm *concurrentMap[Key, Value]) Clear() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.m.Clear()
+}
+
 // PropMapGet obtains the property with the specified name from pm.
 //
 // If the property does not exist, it reports a *PropNotExistError.
@@ -244,14 +561,73 @@ func (mepm *mutExclPropMap) removeFromOthers(name ...PropName) {
 // In particular, time.Time and gosln.Date are convertible to each other
 // in this function.
 // The conversion uses the function DateOf and the method GoTime of gosln.Date.
+//
+// If V is []byte or a slice of scalars (e.g., []string),
+// the returned value is a copy of the property value,
+// so modifying it does not affect the property in pm.
+// (Returning the stored slice directly would let the caller
+// modify the backing array inside pm.)
 func PropMapGet[V PropValue](pm PropMap, name PropName) (value V, err error) {
+	value, err = propMapGet[V](pm, name, AllowLossy)
+	return value, errors.AutoWrap(err)
+}
+
+// PropMapGetBytes obtains the property with the specified name
+// from pm as a []byte.
+//
+// It is equivalent to PropMapGet[[]byte](pm, name).
+// The returned slice is a copy of the property value,
+// so modifying it does not affect the property in pm.
+//
+// If the property does not exist, it reports a *PropNotExistError.
+// If the type of the property is not convertible to []byte,
+// it reports a *PropTypeError.
+// (To test the type of err, use function errors.As.)
+func PropMapGetBytes(pm PropMap, name PropName) (value []byte, err error) {
+	value, err = propMapGet[[]byte](pm, name, AllowLossy)
+	return value, errors.AutoWrap(err)
+}
+
+// PropMapGetWithPolicy is like PropMapGet,
+// but converts the property value to V under the specified coercion policy.
+//
+// If the property does not exist, it reports a *PropNotExistError.
+// If the type of the property is not V and not convertible to V
+// under the policy, it reports a *PropTypeError.
+// If the policy is RejectLossy and the conversion would
+// lose precision (e.g., 3.5 to int), it reports a *LossyConversionError.
+// (To test the type of err, use function errors.As.)
+//
+// If policy is invalid (such as zero-value), AllowLossy is used,
+// which is the behavior of PropMapGet.
+func PropMapGetWithPolicy[V PropValue](
+	pm PropMap,
+	name PropName,
+	policy CoercionPolicy,
+) (value V, err error) {
+	if !policy.IsValid() {
+		policy = AllowLossy
+	}
+	value, err = propMapGet[V](pm, name, policy)
+	return value, errors.AutoWrap(err)
+}
+
+// propMapGet is the implementation of
+// the functions PropMapGet and PropMapGetWithPolicy.
+//
+// The caller should guarantee that policy is valid.
+func propMapGet[V PropValue](
+	pm PropMap,
+	name PropName,
+	policy CoercionPolicy,
+) (value V, err error) {
 	if pm == nil {
-		err = errors.AutoWrap(NewPropNotExistError(name))
+		err = NewPropNotExistError(name)
 		return
 	}
 	prop, present := pm.Get(name)
 	if !present {
-		err = errors.AutoWrap(NewPropNotExistError(name))
+		err = NewPropNotExistError(name)
 		return
 	}
 	propV := reflect.ValueOf(prop)
@@ -261,18 +637,89 @@ func PropMapGet[V PropValue](pm PropMap, name PropName) (value V, err error) {
 	switch {
 	case propType == vType || propType.AssignableTo(vType):
 		v.Set(propV)
+		// Copy the []byte value or the slice so that the caller cannot
+		// modify the property in pm through the returned value.
+		value = copyPropValue(value).(V)
 	case propType.ConvertibleTo(vType):
+		if policy != AllowLossy {
+			propPT, vPT := PropTypeOf(prop), propTypeOfMap[vType]
+			if propPT.IsNumeric() || vPT.IsNumeric() {
+				if policy == StrictNumeric || !propPT.IsNumeric() || !vPT.IsNumeric() {
+					err = NewPropTypeError(name, prop, vType)
+					return
+				}
+				converted := propV.Convert(vType)
+				if isLossyConversion(propV, converted) {
+					err = NewLossyConversionError(name, prop, vType)
+					return
+				}
+				v.Set(converted)
+				return
+			}
+		}
 		v.Set(propV.Convert(vType))
 	case propType == PTTime.GoType() && vType == PTDate.GoType():
 		v.Set(reflect.ValueOf(DateOf(prop.(time.Time))))
 	case propType == PTDate.GoType() && vType == PTTime.GoType():
 		v.Set(reflect.ValueOf(prop.(Date).GoTime()))
 	default:
-		err = errors.AutoWrap(NewPropTypeError(name, prop, vType))
+		err = NewPropTypeError(name, prop, vType)
 	}
 	return
 }
 
+// isLossyConversion reports whether the numeric value from
+// loses precision or changes sign when converted to the numeric value to.
+func isLossyConversion(from, to reflect.Value) bool {
+	switch {
+	case isIntKind(from.Kind()) && isUintKind(to.Kind()):
+		if from.Int() < 0 {
+			return true
+		}
+	case isUintKind(from.Kind()) && isIntKind(to.Kind()):
+		if to.Int() < 0 {
+			return true
+		}
+	case isFloatKind(from.Kind()):
+		f := from.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return !isFloatKind(to.Kind())
+		} else if f < 0 && isUintKind(to.Kind()) {
+			return true
+		}
+	}
+	back := to.Convert(from.Type())
+	switch from.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return !floatEqualOrBothNaN(back.Float(), from.Float())
+	case reflect.Complex64, reflect.Complex128:
+		b, f := back.Complex(), from.Complex()
+		return !floatEqualOrBothNaN(real(b), real(f)) ||
+			!floatEqualOrBothNaN(imag(b), imag(f))
+	}
+	return back.Interface() != from.Interface()
+}
+
+// isIntKind reports whether k is a signed integer kind.
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+// isUintKind reports whether k is an unsigned integer kind.
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// isFloatKind reports whether k is a floating-point kind.
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// floatEqualOrBothNaN reports whether a and b are equal or both NaN.
+func floatEqualOrBothNaN(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b)
+}
+
 // PropMapSet sets a property with the specified name and value to pm.
 //
 // If pm is nil, it reports an error.
@@ -288,3 +735,105 @@ func PropMapSet[V PropValue](pm PropMap, name PropName, value V) error {
 	pm.Set(name, value)
 	return nil
 }
+
+// equalPropValues reports whether the property values a and b are equal.
+//
+// The []byte values are compared with bytes.Equal,
+// the time.Time values are compared with the method Equal of time.Time,
+// and the slices of scalars are compared element by element
+// with operator ==, where a nil slice equals an empty slice.
+// The other values are compared with operator ==.
+// Values of different types are not equal.
+func equalPropValues(a, b any) bool {
+	switch x := a.(type) {
+	case []byte:
+		y, ok := b.([]byte)
+		return ok && bytes.Equal(x, y)
+	case time.Time:
+		y, ok := b.(time.Time)
+		return ok && x.Equal(y)
+	case []bool:
+		y, ok := b.([]bool)
+		return ok && equalSlices(x, y)
+	case []int:
+		y, ok := b.([]int)
+		return ok && equalSlices(x, y)
+	case []int64:
+		y, ok := b.([]int64)
+		return ok && equalSlices(x, y)
+	case []float64:
+		y, ok := b.([]float64)
+		return ok && equalSlices(x, y)
+	case []string:
+		y, ok := b.([]string)
+		return ok && equalSlices(x, y)
+	}
+	if PropTypeOf(b).IsSlice() {
+		return false
+	}
+	return a == b
+}
+
+// equalSlices reports whether the slices a and b have the same length
+// and equal elements (compared with operator ==).
+func equalSlices[E comparable](a, b []E) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// copyPropValue returns a copy of v if v is a non-nil []byte
+// or a non-nil slice of scalars (see method IsSlice of PropType).
+// Otherwise, it returns v itself.
+func copyPropValue(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		return copySlice(x)
+	case []bool:
+		return copySlice(x)
+	case []int:
+		return copySlice(x)
+	case []int64:
+		return copySlice(x)
+	case []float64:
+		return copySlice(x)
+	case []string:
+		return copySlice(x)
+	}
+	return v
+}
+
+// copySlice returns a copy of s.
+//
+// If s is nil, it returns nil.
+func copySlice[E any](s []E) []E {
+	if s == nil {
+		return nil
+	}
+	return append(make([]E, 0, len(s)), s...)
+}
+
+// copyMap returns a mapping.GoMap holding the entries of m.
+//
+// concurrentMap copies the map to be set before acquiring its lock,
+// so that it never holds its lock while waiting for that of m,
+// which may be another concurrentMap setting from it concurrently.
+//
+// If m is nil, copyMap returns nil.
+func copyMap[Key comparable, Value any](m mapping.Map[Key, Value]) mapping.Map[Key, Value] {
+	if m == nil {
+		return nil
+	}
+	gm := make(mapping.GoMap[Key, Value], m.Len())
+	m.Range(func(x mapping.Entry[Key, Value]) (cont bool) {
+		gm[x.Key] = x.Value
+		return true
+	})
+	return &gm
+}
@@ -0,0 +1,128 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+)
+
+// EncodePropMap serializes pm with encoding/gob.
+//
+// It encodes the number of properties followed by
+// the name, type, and value of each property,
+// where the type is obtained by the function PropTypeOf.
+// Hence, the concrete value types
+// (e.g., []byte versus string, and time.Time versus Date)
+// are preserved when decoding with the function DecodePropMap.
+// In particular, Date values are encoded as their string representations.
+//
+// A nil PropMap is encoded as an empty PropMap.
+func EncodePropMap(pm PropMap) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	var n int
+	if pm != nil {
+		n = pm.Len()
+	}
+	err := enc.Encode(n)
+	if err != nil || n == 0 {
+		return buf.Bytes(), errors.AutoWrap(err)
+	}
+	pm.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		err = enc.Encode(x.Key.String())
+		if err == nil {
+			err = enc.Encode(PropTypeOf(x.Value))
+		}
+		if err == nil {
+			if d, ok := x.Value.(Date); ok {
+				// Date has no exported fields, so encode its string instead.
+				err = enc.Encode(d.String())
+			} else {
+				err = enc.Encode(x.Value)
+			}
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodePropMap deserializes a PropMap from data
+// encoded by the function EncodePropMap.
+//
+// If any property name in data is invalid,
+// it reports a *InvalidPropNameError.
+// If any property type in data is invalid,
+// it reports a *InvalidPropTypeError.
+// (To test the type of err, use function errors.As.)
+func DecodePropMap(data []byte) (PropMap, error) {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	var n int
+	err := dec.Decode(&n)
+	if err != nil {
+		return nil, errors.AutoWrap(err)
+	} else if n < 0 {
+		return nil, errors.AutoNew("number of properties is negative")
+	}
+	pm := NewPropMap(n)
+	for i := 0; i < n; i++ {
+		var s string
+		var pt PropType
+		err = dec.Decode(&s)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		name, err := NewPropName(s)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		err = dec.Decode(&pt)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		} else if !pt.IsValid() {
+			return nil, errors.AutoWrap(NewInvalidPropTypeError(pt))
+		}
+		if pt == PTDate {
+			var d Date
+			err = dec.Decode(&s)
+			if err == nil {
+				d, err = ParseDate(s)
+			}
+			if err != nil {
+				return nil, errors.AutoWrap(err)
+			}
+			pm.Set(name, d)
+			continue
+		}
+		v := reflect.New(pt.GoType())
+		err = dec.DecodeValue(v)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		}
+		pm.Set(name, v.Elem().Interface())
+	}
+	return pm, nil
+}
@@ -0,0 +1,109 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestEncodeAndDecodePropMap(t *testing.T) {
+	values := []any{
+		true,
+		0,
+		int8(-8),
+		int16(16),
+		int32(-32),
+		int64(64),
+		uint(0),
+		uint8(8),
+		uint16(16),
+		uint32(32),
+		uint64(64),
+		uintptr(1),
+		float32(3.5),
+		-2.25,
+		complex64(1 + 2i),
+		complex(3, -4),
+		[]byte("bytes"),
+		"bytes",
+		time.Date(2023, time.March, 12, 8, 30, 0, 1, time.FixedZone("UTC+8", 8*3600)),
+		gosln.DateOfYearMonthDay(2023, time.March, 12),
+	}
+	pm := gosln.NewPropMap(len(values))
+	for i, v := range values {
+		pm.Set(gosln.MustNewPropName(string(rune('a'+i))), v)
+	}
+
+	testCases := []struct {
+		name string
+		pm   gosln.PropMap
+	}{
+		{"nil", nil},
+		{"empty", gosln.NewPropMap(0)},
+		{"all types", pm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := gosln.EncodePropMap(tc.pm)
+			if err != nil {
+				t.Fatal("encode -", err)
+			}
+			got, err := gosln.DecodePropMap(data)
+			if err != nil {
+				t.Fatal("decode -", err)
+			} else if !gosln.EqualPropMaps(got, tc.pm) {
+				t.Fatalf("got %v; want %v", gosln.PropMapToGoMap(got), gosln.PropMapToGoMap(tc.pm))
+			}
+			if tc.pm != pm {
+				return
+			}
+			for i, v := range values {
+				name := gosln.MustNewPropName(string(rune('a' + i)))
+				if x, _ := got.Get(name); gosln.PropTypeOf(x) != gosln.PropTypeOf(v) {
+					t.Errorf("property %v - got type %v; want %v",
+						name, gosln.PropTypeOf(x), gosln.PropTypeOf(v))
+				}
+			}
+		})
+	}
+}
+
+func TestDecodePropMap_Error(t *testing.T) {
+	if _, err := gosln.DecodePropMap([]byte("not gob")); err == nil {
+		t.Error("malformed data - got nil error")
+	}
+	pm := gosln.NewPropMap(1)
+	pm.Set(gosln.MustNewPropName("a"), 1)
+	data, err := gosln.EncodePropMap(pm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = gosln.DecodePropMap(data[:len(data)-1]); err == nil {
+		t.Error("truncated data - got nil error")
+	}
+	var e *gosln.InvalidPropNameError
+	if errors.As(err, &e) {
+		t.Errorf("truncated data - got *InvalidPropNameError %v", err)
+	}
+}
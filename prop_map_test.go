@@ -21,9 +21,14 @@ package gosln_test
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/donyori/gogo/container/mapping"
+
 	"github.com/donyori/gosln"
 )
 
@@ -244,3 +249,579 @@ func TestPropMapGet_TimeAndDate(t *testing.T) {
 		}
 	})
 }
+
+func TestNewConcurrentPropMap(t *testing.T) {
+	const NumGoroutines, NumProps = 8, 100
+	names := make([]gosln.PropName, NumProps)
+	for i := range names {
+		names[i] = gosln.MustNewPropName(fmt.Sprintf("p%d", i))
+	}
+	pm := gosln.NewConcurrentPropMap(NumProps)
+
+	var wg sync.WaitGroup
+	wg.Add(NumGoroutines)
+	for g := 0; g < NumGoroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+			for i, name := range names {
+				if i%NumGoroutines == g {
+					pm.Set(name, i)
+				}
+				pm.Get(names[(i+g)%NumProps])
+				pm.Len()
+				pm.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
+					return x.Value != nil
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := pm.Len(); n != NumProps {
+		t.Fatalf("got Len %d; want %d", n, NumProps)
+	}
+	for i, name := range names {
+		if v, present := pm.Get(name); !present || v != i {
+			t.Errorf("got %v %v (present: %t); want %d", name, v, present, i)
+		}
+	}
+
+	pm.SetMap(pm)
+	if prev := pm.GetAndSetMap(pm); prev == nil || prev.Len() != NumProps {
+		t.Errorf("GetAndSetMap itself - got previous %v", prev)
+	}
+	if n := pm.Len(); n != NumProps {
+		t.Errorf("after setting itself - got Len %d; want %d", n, NumProps)
+	}
+	pm.Filter(func(x mapping.Entry[gosln.PropName, any]) (keep bool) {
+		return x.Value.(int)%2 == 0
+	})
+	if n := pm.Len(); n != NumProps/2 {
+		t.Errorf("after Filter - got Len %d; want %d", n, NumProps/2)
+	}
+
+	defer func() {
+		e := recover()
+		err, ok := e.(error)
+		var target *gosln.InvalidPropValueError
+		if !ok || !errors.As(err, &target) {
+			t.Errorf("invalid value - got panic %v; want *InvalidPropValueError", e)
+		}
+	}()
+	pm.Set(names[0], struct{}{})
+}
+
+func TestNewConcurrentPropMap_SetMapEachOther(t *testing.T) {
+	const NumRounds, NumProps = 100, 100
+	a := gosln.NewConcurrentPropMap(NumProps * 2)
+	b := gosln.NewConcurrentPropMap(NumProps * 2)
+	want := make(map[string]any, NumProps*2)
+	for i := 0; i < NumProps; i++ {
+		a.Set(gosln.MustNewPropName(fmt.Sprintf("a%d", i)), i)
+		b.Set(gosln.MustNewPropName(fmt.Sprintf("b%d", i)), i)
+		want[fmt.Sprintf("a%d", i)], want[fmt.Sprintf("b%d", i)] = i, i
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < NumRounds; i++ {
+				a.SetMap(b)
+				a.GetAndSetMap(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < NumRounds; i++ {
+				b.SetMap(a)
+				b.GetAndSetMap(a)
+			}
+		}()
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout; deadlock suspected")
+	}
+
+	for _, pm := range []gosln.PropMap{a, b} {
+		if got := gosln.PropMapToGoMap(pm); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v; want %v", got, want)
+		}
+	}
+}
+
+func TestFilteredPropMap(t *testing.T) {
+	a, b, c := gosln.MustNewPropName("a"), gosln.MustNewPropName("b"), gosln.MustNewPropName("c")
+	src := gosln.NewPropMap(3)
+	src.Set(a, 1)
+	src.Set(b, []byte("bytes"))
+	src.Set(c, "str")
+
+	r := gosln.FilteredPropMap(src, func(name gosln.PropName, value any) bool {
+		return name != c
+	})
+	if r.Len() != 2 {
+		t.Errorf("got Len %d; want 2", r.Len())
+	}
+	if v, present := r.Get(a); !present || v != 1 {
+		t.Errorf("got a %v (present: %t); want 1 (present: true)", v, present)
+	}
+	if _, present := r.Get(c); present {
+		t.Error("c is present in the result")
+	}
+	if src.Len() != 3 {
+		t.Errorf("source modified, got Len %d; want 3", src.Len())
+	}
+	v, _ := r.Get(b)
+	bs, ok := v.([]byte)
+	if !ok || string(bs) != "bytes" {
+		t.Fatalf("got b %v; want %v", v, []byte("bytes"))
+	}
+	bs[0] = 'B'
+	if v, _ = src.Get(b); string(v.([]byte)) != "bytes" {
+		t.Errorf("source bytes modified through the result, got %q", v)
+	}
+
+	if r = gosln.FilteredPropMap(nil, nil); r == nil || r.Len() != 0 {
+		t.Errorf("nil source - got %v; want an empty map", r)
+	}
+}
+
+func TestClonePropMap(t *testing.T) {
+	a, b, c := gosln.MustNewPropName("a"), gosln.MustNewPropName("b"), gosln.MustNewPropName("c")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	src := gosln.NewPropMap(3)
+	src.Set(a, 1)
+	src.Set(b, []byte("bytes"))
+	src.Set(c, date)
+
+	r := gosln.ClonePropMap(src)
+	if r.Len() != 3 {
+		t.Errorf("got Len %d; want 3", r.Len())
+	}
+	if v, present := r.Get(a); !present || v != 1 {
+		t.Errorf("got a %v (present: %t); want 1 (present: true)", v, present)
+	}
+	if v, present := r.Get(c); !present || v != date {
+		t.Errorf("got c %v (present: %t); want %v (present: true)", v, present, date)
+	}
+	v, _ := r.Get(b)
+	bs, ok := v.([]byte)
+	if !ok || string(bs) != "bytes" {
+		t.Fatalf("got b %v; want %v", v, []byte("bytes"))
+	}
+	bs[0] = 'B'
+	if v, _ = src.Get(b); string(v.([]byte)) != "bytes" {
+		t.Errorf("source bytes modified through the clone, got %q", v)
+	}
+	r.Remove(a)
+	if _, present := src.Get(a); !present {
+		t.Error("source modified through the clone, a is absent")
+	}
+
+	if r = gosln.ClonePropMap(nil); r != nil {
+		t.Errorf("nil source - got %v; want nil", r)
+	}
+}
+
+func TestMergePropMaps(t *testing.T) {
+	a, b, c := gosln.MustNewPropName("a"), gosln.MustNewPropName("b"), gosln.MustNewPropName("c")
+	base := gosln.NewPropMap(2)
+	base.Set(a, 1)
+	base.Set(b, []byte("base"))
+	overlay := gosln.NewPropMap(2)
+	overlay.Set(b, []byte("overlay"))
+	overlay.Set(c, "str")
+
+	r := gosln.MergePropMaps(base, overlay)
+	want := gosln.NewPropMap(3)
+	want.Set(a, 1)
+	want.Set(b, []byte("overlay"))
+	want.Set(c, "str")
+	if !gosln.EqualPropMaps(r, want) {
+		t.Errorf("got %v; want %v", gosln.PropMapToGoMap(r), gosln.PropMapToGoMap(want))
+	}
+	if base.Len() != 2 || overlay.Len() != 2 {
+		t.Errorf("sources modified, got Len %d and %d; want 2 and 2", base.Len(), overlay.Len())
+	}
+	v, _ := r.Get(b)
+	v.([]byte)[0] = 'O'
+	if v, _ = overlay.Get(b); string(v.([]byte)) != "overlay" {
+		t.Errorf("overlay bytes modified through the result, got %q", v)
+	}
+
+	if r = gosln.MergePropMaps(nil, overlay); !gosln.EqualPropMaps(r, overlay) {
+		t.Errorf("nil base - got %v; want %v", gosln.PropMapToGoMap(r), gosln.PropMapToGoMap(overlay))
+	}
+	if r = gosln.MergePropMaps(base, nil); !gosln.EqualPropMaps(r, base) {
+		t.Errorf("nil overlay - got %v; want %v", gosln.PropMapToGoMap(r), gosln.PropMapToGoMap(base))
+	}
+	if r = gosln.MergePropMaps(nil, nil); r == nil || r.Len() != 0 {
+		t.Errorf("both nil - got %v; want an empty PropMap", r)
+	}
+}
+
+func TestEqualPropMaps(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	tm := time.Date(2023, time.March, 12, 8, 0, 0, 0, time.UTC)
+	newPropMap := func(kv ...any) gosln.PropMap {
+		pm := gosln.NewPropMap(len(kv) / 2)
+		for i := 0; i < len(kv); i += 2 {
+			pm.Set(kv[i].(gosln.PropName), kv[i+1])
+		}
+		return pm
+	}
+
+	testCases := []struct {
+		name string
+		x, y gosln.PropMap
+		want bool
+	}{
+		{"nil and nil", nil, nil, true},
+		{"nil and empty", nil, newPropMap(), true},
+		{"nil and non-empty", nil, newPropMap(a, 1), false},
+		{"same", newPropMap(a, 1, b, "x"), newPropMap(b, "x", a, 1), true},
+		{"different values", newPropMap(a, 1), newPropMap(a, 2), false},
+		{"different types", newPropMap(a, 1), newPropMap(a, int64(1)), false},
+		{"different names", newPropMap(a, 1), newPropMap(b, 1), false},
+		{"different lengths", newPropMap(a, 1), newPropMap(a, 1, b, 1), false},
+		{"bytes equal", newPropMap(a, []byte("abc")), newPropMap(a, []byte("abc")), true},
+		{"bytes not equal", newPropMap(a, []byte("abc")), newPropMap(a, []byte("abd")), false},
+		{"bytes and string", newPropMap(a, []byte("abc")), newPropMap(a, "abc"), false},
+		{"string and bytes", newPropMap(a, "abc"), newPropMap(a, []byte("abc")), false},
+		{"time equal", newPropMap(a, tm), newPropMap(a, tm.In(time.FixedZone("UTC+8", 8*3600))), true},
+		{"time not equal", newPropMap(a, tm), newPropMap(a, tm.Add(time.Second)), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := gosln.EqualPropMaps(tc.x, tc.y); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+			if got := gosln.EqualPropMaps(tc.y, tc.x); got != tc.want {
+				t.Errorf("reversed - got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropMapToGoMapAndFromGoMap(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	pm := gosln.NewPropMap(2)
+	pm.Set(a, 1)
+	pm.Set(b, []byte("bytes"))
+
+	m := gosln.PropMapToGoMap(pm)
+	if len(m) != 2 || m["a"] != 1 {
+		t.Fatalf("got %v; want map[a:1 b:[98 121 116 101 115]]", m)
+	}
+	bs, ok := m["b"].([]byte)
+	if !ok || string(bs) != "bytes" {
+		t.Fatalf("got b %v; want %v", m["b"], []byte("bytes"))
+	}
+	bs[0] = 'B'
+	if v, _ := pm.Get(b); string(v.([]byte)) != "bytes" {
+		t.Errorf("source bytes modified through the Go map, got %q", v)
+	}
+	if m = gosln.PropMapToGoMap(nil); m != nil {
+		t.Errorf("nil PropMap - got %v; want nil", m)
+	}
+
+	back, err := gosln.PropMapFromGoMap(map[string]any{"a": 1, "b": []byte("bytes")})
+	if err != nil {
+		t.Fatal(err)
+	} else if !gosln.EqualPropMaps(back, pm) {
+		t.Errorf("got %v; want %v", gosln.PropMapToGoMap(back), gosln.PropMapToGoMap(pm))
+	}
+	if back, err = gosln.PropMapFromGoMap(nil); err != nil || back == nil || back.Len() != 0 {
+		t.Errorf("nil Go map - got %v, %v; want an empty PropMap, nil", back, err)
+	}
+	_, err = gosln.PropMapFromGoMap(map[string]any{"1a": 1})
+	var pnErr *gosln.InvalidPropNameError
+	if !errors.As(err, &pnErr) {
+		t.Errorf("invalid name - got error %v; want *InvalidPropNameError", err)
+	}
+	_, err = gosln.PropMapFromGoMap(map[string]any{"a": struct{}{}})
+	var pvErr *gosln.InvalidPropValueError
+	if !errors.As(err, &pvErr) {
+		t.Errorf("invalid value - got error %v; want *InvalidPropValueError", err)
+	} else if pvErr.PropName().String() != "a" {
+		t.Errorf("invalid value - got property name %q; want %q", pvErr.PropName(), "a")
+	}
+}
+
+func TestValidatePropMap(t *testing.T) {
+	age, name, tags := gosln.MustNewPropName("age"), gosln.MustNewPropName("name"), gosln.MustNewPropName("tags")
+	schema := gosln.NewPropTypeMap(3)
+	schema.Set(age, gosln.PTInt64)
+	schema.Set(name, gosln.PTString)
+	schema.Set(tags, gosln.PTStringSlice)
+
+	newPM := func(kv ...any) gosln.PropMap {
+		pm := gosln.NewPropMap(len(kv) / 2)
+		for i := 0; i < len(kv); i += 2 {
+			pm.Set(kv[i].(gosln.PropName), kv[i+1])
+		}
+		return pm
+	}
+
+	const (
+		NoError int8 = iota
+		TypeError
+		NotExistError
+	)
+	testCases := []struct {
+		desc       string
+		pm         gosln.PropMap
+		schema     gosln.PropTypeMap
+		requireAll bool
+		want       int8
+		wantName   gosln.PropName
+	}{
+		{"all matched", newPM(age, int64(3), name, "x", tags, []string{"a"}), schema, true, NoError, gosln.PropName{}},
+		{"convertible", newPM(age, int8(3), name, "x"), schema, false, NoError, gosln.PropName{}},
+		{"extra property", newPM(gosln.MustNewPropName("other"), true), schema, false, NoError, gosln.PropName{}},
+		{"nil PropMap", nil, schema, false, NoError, gosln.PropName{}},
+		{"nil schema", newPM(age, "3"), nil, true, NoError, gosln.PropName{}},
+		{"wrong type", newPM(age, int64(3), name, true), schema, false, TypeError, name},
+		{"wrong slice type", newPM(tags, []bool{true}), schema, false, TypeError, tags},
+		{"missing required", newPM(age, int64(3), tags, []string{}), schema, true, NotExistError, name},
+		{"nil PropMap required", nil, schema, true, NotExistError, age},
+		{"first in name order", newPM(age, "3", name, true), schema, false, TypeError, age},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := gosln.ValidatePropMap(tc.pm, tc.schema, tc.requireAll)
+			var typeErr *gosln.PropTypeError
+			var notExistErr *gosln.PropNotExistError
+			switch tc.want {
+			case NoError:
+				if err != nil {
+					t.Errorf("got %v; want nil", err)
+				}
+			case TypeError:
+				if !errors.As(err, &typeErr) {
+					t.Errorf("got %v; want *PropTypeError", err)
+				} else if typeErr.PropName() != tc.wantName {
+					t.Errorf("got property name %q; want %q", typeErr.PropName(), tc.wantName)
+				}
+			case NotExistError:
+				if !errors.As(err, &notExistErr) {
+					t.Errorf("got %v; want *PropNotExistError", err)
+				} else if notExistErr.PropName() != tc.wantName {
+					t.Errorf("got property name %q; want %q", notExistErr.PropName(), tc.wantName)
+				}
+			}
+		})
+	}
+}
+
+func TestPropMap_InvalidPropValueErrorName(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	src := gosln.NewPropMap(2)
+	src.Set(a, 1)
+	bad := mapping.GoMap[gosln.PropName, any]{b: struct{}{}}
+
+	testCases := []struct {
+		name string
+		set  func(pm gosln.PropMap)
+	}{
+		{"Set", func(pm gosln.PropMap) { pm.Set(b, struct{}{}) }},
+		{"GetAndSet", func(pm gosln.PropMap) { pm.GetAndSet(b, struct{}{}) }},
+		{"SetMap", func(pm gosln.PropMap) { pm.SetMap(&bad) }},
+		{"GetAndSetMap", func(pm gosln.PropMap) { pm.GetAndSetMap(&bad) }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				e := recover()
+				err, ok := e.(error)
+				var target *gosln.InvalidPropValueError
+				if !ok || !errors.As(err, &target) {
+					t.Fatalf("got panic %v; want *InvalidPropValueError", e)
+				} else if target.PropName() != b {
+					t.Errorf("got property name %q; want %q", target.PropName(), b)
+				} else if msg := target.Error(); !strings.Contains(msg, `"b"`) {
+					t.Errorf("got message %q; want it to contain the property name", msg)
+				}
+			}()
+			tc.set(gosln.ClonePropMap(src))
+		})
+	}
+
+	err := gosln.NewInvalidPropValueError(gosln.PropName{}, struct{}{})
+	if err.PropName().IsValid() {
+		t.Errorf("unknown name - got property name %q; want zero-value", err.PropName())
+	} else if msg := err.Error(); !strings.HasPrefix(msg, "property value (type: ") {
+		t.Errorf("unknown name - got message %q", msg)
+	}
+}
+
+func TestPropMapGetBytes(t *testing.T) {
+	b, str := gosln.MustNewPropName("b"), gosln.MustNewPropName("str")
+	pm := gosln.NewPropMap(2)
+	pm.Set(b, []byte("bytes"))
+	pm.Set(str, "string")
+
+	getters := []struct {
+		name string
+		get  func(pm gosln.PropMap, name gosln.PropName) ([]byte, error)
+	}{
+		{"PropMapGetBytes", gosln.PropMapGetBytes},
+		{"PropMapGet", gosln.PropMapGet[[]byte]},
+	}
+	for _, g := range getters {
+		t.Run(g.name, func(t *testing.T) {
+			got, err := g.get(pm, b)
+			if err != nil {
+				t.Fatal(err)
+			} else if string(got) != "bytes" {
+				t.Fatalf("got %q; want %q", got, "bytes")
+			}
+			got[0] = 'B'
+			if v, _ := pm.Get(b); string(v.([]byte)) != "bytes" {
+				t.Errorf("property modified through the returned value, got %q", v)
+			}
+			if got, err = g.get(pm, str); err != nil || string(got) != "string" {
+				t.Errorf("from string - got %q, %v; want %q, nil", got, err, "string")
+			}
+			_, err = g.get(pm, gosln.MustNewPropName("absent"))
+			var e *gosln.PropNotExistError
+			if !errors.As(err, &e) {
+				t.Errorf("absent - got error %v; want *PropNotExistError", err)
+			}
+		})
+	}
+}
+
+func TestPropMapGet_Slice(t *testing.T) {
+	tags, ints := gosln.MustNewPropName("tags"), gosln.MustNewPropName("ints")
+	pm := gosln.NewPropMap(2)
+	pm.Set(tags, []string{"a", "b"})
+	pm.Set(ints, []int{1, 2})
+
+	got, err := gosln.PropMapGet[[]string](pm, tags)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("got %q; want [a b]", got)
+	}
+	got[0] = "A"
+	if v, _ := pm.Get(tags); v.([]string)[0] != "a" {
+		t.Errorf("property modified through the returned value, got %q", v)
+	}
+	_, err = gosln.PropMapGet[[]int64](pm, ints)
+	var e *gosln.PropTypeError
+	if !errors.As(err, &e) {
+		t.Errorf("[]int to []int64 - got error %v; want *PropTypeError", err)
+	}
+
+	c := gosln.ClonePropMap(pm)
+	if !gosln.EqualPropMaps(c, pm) {
+		t.Errorf("clone - got %v; want %v", gosln.PropMapToGoMap(c), gosln.PropMapToGoMap(pm))
+	}
+	v, _ := c.Get(ints)
+	v.([]int)[0] = 100
+	if v, _ = pm.Get(ints); v.([]int)[0] != 1 {
+		t.Errorf("source modified through the clone, got %v", v)
+	}
+	if gosln.EqualPropMaps(c, pm) {
+		t.Error("got equal after modifying the clone")
+	}
+
+	other := gosln.NewPropMap(2)
+	other.Set(tags, []string{"a", "b"})
+	other.Set(ints, []int64{1, 2})
+	if gosln.EqualPropMaps(other, pm) {
+		t.Error("[]int64 and []int - got equal")
+	}
+	other.Set(ints, []int{1, 2})
+	if !gosln.EqualPropMaps(other, pm) {
+		t.Error("got not equal")
+	}
+}
+
+func TestPropMapGetWithPolicy(t *testing.T) {
+	name := gosln.MustNewPropName("value")
+	getInt := func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error) {
+		return gosln.PropMapGetWithPolicy[int](pm, name, policy)
+	}
+	getUint := func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error) {
+		return gosln.PropMapGetWithPolicy[uint](pm, name, policy)
+	}
+	getFloat64 := func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error) {
+		return gosln.PropMapGetWithPolicy[float64](pm, name, policy)
+	}
+	getFloat32 := func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error) {
+		return gosln.PropMapGetWithPolicy[float32](pm, name, policy)
+	}
+	getString := func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error) {
+		return gosln.PropMapGetWithPolicy[string](pm, name, policy)
+	}
+
+	const (
+		NoError int8 = iota
+		LossyConversionError
+		PropTypeError
+	)
+
+	testCases := []struct {
+		value   any
+		policy  gosln.CoercionPolicy
+		get     func(pm gosln.PropMap, policy gosln.CoercionPolicy) (any, error)
+		want    any
+		wantErr int8
+	}{
+		{3.0, gosln.AllowLossy, getInt, 3, NoError},
+		{3.0, gosln.RejectLossy, getInt, 3, NoError},
+		{3.0, gosln.StrictNumeric, getInt, nil, PropTypeError},
+		{3.5, gosln.AllowLossy, getInt, 3, NoError},
+		{3.5, gosln.RejectLossy, getInt, nil, LossyConversionError},
+		{3.5, 0, getInt, 3, NoError},
+		{3, gosln.AllowLossy, getFloat64, 3.0, NoError},
+		{3, gosln.RejectLossy, getFloat64, 3.0, NoError},
+		{3, gosln.StrictNumeric, getFloat64, nil, PropTypeError},
+		{3, gosln.StrictNumeric, getInt, 3, NoError},
+		{-1, gosln.RejectLossy, getUint, nil, LossyConversionError},
+		{uint(7), gosln.RejectLossy, getInt, 7, NoError},
+		{0.1, gosln.RejectLossy, getFloat32, nil, LossyConversionError},
+		{0.5, gosln.RejectLossy, getFloat32, float32(0.5), NoError},
+		{65, gosln.AllowLossy, getString, "A", NoError},
+		{65, gosln.RejectLossy, getString, nil, PropTypeError},
+		{[]byte("A"), gosln.StrictNumeric, getString, "A", NoError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%T(%v)&policy=%v", tc.value, tc.value, tc.policy), func(t *testing.T) {
+			pm := gosln.NewPropMap(1)
+			pm.Set(name, tc.value)
+			got, err := tc.get(pm, tc.policy)
+			switch tc.wantErr {
+			case NoError:
+				if err != nil {
+					t.Errorf("got error %v; want nil", err)
+				} else if got != tc.want {
+					t.Errorf("got %#v; want %#v", got, tc.want)
+				}
+			case LossyConversionError:
+				var target *gosln.LossyConversionError
+				if !errors.As(err, &target) {
+					t.Errorf("got error %v; want of type %T", err, target)
+				}
+			case PropTypeError:
+				var target *gosln.PropTypeError
+				if !errors.As(err, &target) {
+					t.Errorf("got error %v; want of type %T", err, target)
+				}
+			}
+		})
+	}
+}
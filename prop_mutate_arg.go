@@ -39,11 +39,52 @@ type PropMutateArg interface {
 	//
 	// The PropNameSet is always non-nil, but may be empty.
 	ToBeRemoved() PropNameSet
+
+	// IsEmpty reports whether both ToBeSet and ToBeRemoved are empty,
+	// that is, the mutation changes nothing.
+	IsEmpty() bool
+
+	// Clone returns a copy of the PropMutateArg.
+	//
+	// The copy is independent of the PropMutateArg:
+	// modifying one does not affect the other.
+	// The components of the copy are mutually exclusive as well.
+	// The []byte values and the slices of scalars in ToBeSet are copied.
+	Clone() PropMutateArg
+}
+
+// ApplyMutate returns a new PropMap holding the properties in pm
+// mutated by pma, as the method MutateNodeProperties of SLN does,
+// that is, the properties in ToBeSet of pma are set (added and replaced),
+// and the properties named in ToBeRemoved of pma are removed.
+//
+// It helps the client preview the result of a mutation before persisting it
+// (e.g., comparing it with pm by function EqualPropMaps
+// to decide whether the mutation is necessary).
+//
+// It does not modify pm and pma.
+// The []byte values and the slices of scalars are copied so that
+// the returned PropMap does not share them with pm or pma.
+// A nil pm is treated as an empty PropMap,
+// and a nil pma is treated as a PropMutateArg that changes nothing.
+// The returned PropMap is always non-nil.
+func ApplyMutate(pm PropMap, pma PropMutateArg) PropMap {
+	if pma == nil {
+		return MergePropMaps(pm, nil)
+	}
+	// ToBeSet and ToBeRemoved are mutually exclusive,
+	// so removing the properties after merging is safe.
+	r := MergePropMaps(pm, pma.ToBeSet())
+	pma.ToBeRemoved().Range(func(x PropName) (cont bool) {
+		r.Remove(x)
+		return true
+	})
+	return r
 }
 
 // propMutateArgImpl is an implementation of interface PropMutateArg.
 type propMutateArgImpl struct {
-	set    *mutExclPropMap     // Properties to set (add and replace).
+	set    *mutExclMap[any]    // Properties to set (add and replace).
 	remove *mutExclPropNameSet // Names of the properties to remove.
 }
 
@@ -55,10 +96,10 @@ type propMutateArgImpl struct {
 // If setCap is negative, it is ignored, as is removeCap.
 func NewPropMutateArg(setCap, removeCap int) PropMutateArg {
 	pma := &propMutateArgImpl{
-		set:    new(mutExclPropMap),
+		set:    new(mutExclMap[any]),
 		remove: new(mutExclPropNameSet),
 	}
-	pma.set.init(setCap, pma.remove)
+	pma.set.init(NewPropMap(setCap), pma.remove)
 	pma.remove.init(removeCap, pma.set)
 	return pma
 }
@@ -70,3 +111,18 @@ func (pma *propMutateArgImpl) ToBeSet() PropMap {
 func (pma *propMutateArgImpl) ToBeRemoved() PropNameSet {
 	return pma.remove
 }
+
+func (pma *propMutateArgImpl) IsEmpty() bool {
+	return pma.set.Len() == 0 && pma.remove.Len() == 0
+}
+
+func (pma *propMutateArgImpl) Clone() PropMutateArg {
+	c := &propMutateArgImpl{
+		set:    new(mutExclMap[any]),
+		remove: new(mutExclPropNameSet),
+	}
+	c.set.init(ClonePropMap(pma.set), c.remove)
+	c.remove.init(pma.remove.Len(), c.set)
+	c.remove.s.Union(pma.remove.s)
+	return c
+}
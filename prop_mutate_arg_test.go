@@ -0,0 +1,140 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"testing"
+
+	"github.com/donyori/gosln"
+)
+
+func TestPropMutateArg_IsEmpty(t *testing.T) {
+	a := gosln.MustNewPropName("a")
+	pma := gosln.NewPropMutateArg(1, 1)
+	if !pma.IsEmpty() {
+		t.Error("new PropMutateArg - got false; want true")
+	}
+	pma.ToBeSet().Set(a, 1)
+	if pma.IsEmpty() {
+		t.Error("after ToBeSet().Set - got true; want false")
+	}
+	pma.ToBeRemoved().Add(a) // a is removed from ToBeSet
+	if pma.IsEmpty() {
+		t.Error("after ToBeRemoved().Add - got true; want false")
+	}
+	pma.ToBeRemoved().Clear()
+	if !pma.IsEmpty() {
+		t.Error("after ToBeRemoved().Clear - got false; want true")
+	}
+}
+
+func TestPropMutateArg_Clone(t *testing.T) {
+	a, b, c := gosln.MustNewPropName("a"), gosln.MustNewPropName("b"), gosln.MustNewPropName("c")
+	pma := gosln.NewPropMutateArg(2, 1)
+	pma.ToBeSet().Set(a, []byte("bytes"))
+	pma.ToBeSet().Set(b, 2)
+	pma.ToBeRemoved().Add(c)
+
+	clone := pma.Clone()
+	if !gosln.EqualPropMaps(clone.ToBeSet(), pma.ToBeSet()) {
+		t.Errorf("got ToBeSet %v; want %v",
+			gosln.PropMapToGoMap(clone.ToBeSet()), gosln.PropMapToGoMap(pma.ToBeSet()))
+	}
+	if removed := clone.ToBeRemoved(); removed.Len() != 1 || !removed.ContainsItem(c) {
+		t.Errorf("got ToBeRemoved %v; want [c]", removed.ToSortedSlice())
+	}
+
+	// The clone is independent of the original.
+	v, _ := clone.ToBeSet().Get(a)
+	v.([]byte)[0] = 'B'
+	if v, _ = pma.ToBeSet().Get(a); string(v.([]byte)) != "bytes" {
+		t.Errorf("original bytes modified through the clone, got %q", v)
+	}
+	clone.ToBeRemoved().Add(b)
+	if _, present := pma.ToBeSet().Get(b); !present {
+		t.Error("original ToBeSet modified through the clone")
+	} else if pma.ToBeRemoved().ContainsItem(b) {
+		t.Error("original ToBeRemoved modified through the clone")
+	}
+
+	// The components of the clone are mutually exclusive.
+	if _, present := clone.ToBeSet().Get(b); present {
+		t.Error("b still in ToBeSet of the clone after adding it to ToBeRemoved")
+	}
+	clone.ToBeSet().Set(c, 3)
+	if clone.ToBeRemoved().ContainsItem(c) {
+		t.Error("c still in ToBeRemoved of the clone after setting it in ToBeSet")
+	} else if !pma.ToBeRemoved().ContainsItem(c) {
+		t.Error("c removed from the original ToBeRemoved through the clone")
+	}
+}
+
+func TestApplyMutate(t *testing.T) {
+	a, b, c := gosln.MustNewPropName("a"), gosln.MustNewPropName("b"), gosln.MustNewPropName("c")
+	newPM := func(kv ...any) gosln.PropMap {
+		pm := gosln.NewPropMap(len(kv) / 2)
+		for i := 0; i < len(kv); i += 2 {
+			pm.Set(kv[i].(gosln.PropName), kv[i+1])
+		}
+		return pm
+	}
+	pma := gosln.NewPropMutateArg(2, 1)
+	pma.ToBeSet().Set(a, 10)
+	pma.ToBeSet().Set(c, []byte("c"))
+	pma.ToBeRemoved().Add(b)
+
+	testCases := []struct {
+		name string
+		pm   gosln.PropMap
+		pma  gosln.PropMutateArg
+		want gosln.PropMap
+	}{
+		{"set and remove", newPM(a, 1, b, 2), pma, newPM(a, 10, c, []byte("c"))},
+		{"nil PropMap", nil, pma, newPM(a, 10, c, []byte("c"))},
+		{"nil PropMutateArg", newPM(a, 1, b, 2), nil, newPM(a, 1, b, 2)},
+		{"empty PropMutateArg", newPM(a, 1), gosln.NewPropMutateArg(0, 0), newPM(a, 1)},
+		{"both nil", nil, nil, newPM()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var before gosln.PropMap
+			if tc.pm != nil {
+				before = gosln.ClonePropMap(tc.pm)
+			}
+			got := gosln.ApplyMutate(tc.pm, tc.pma)
+			if got == nil {
+				t.Fatal("got nil PropMap")
+			} else if !gosln.EqualPropMaps(got, tc.want) {
+				t.Errorf("got %v; want %v", gosln.PropMapToGoMap(got), gosln.PropMapToGoMap(tc.want))
+			}
+			if tc.pm != nil && !gosln.EqualPropMaps(tc.pm, before) {
+				t.Errorf("input modified, got %v; want %v",
+					gosln.PropMapToGoMap(tc.pm), gosln.PropMapToGoMap(before))
+			}
+		})
+	}
+
+	got := gosln.ApplyMutate(nil, pma)
+	v, _ := got.Get(c)
+	v.([]byte)[0] = 'C'
+	if v, _ = pma.ToBeSet().Get(c); string(v.([]byte)) != "c" {
+		t.Errorf("PropMutateArg bytes modified through the result, got %q", v)
+	}
+}
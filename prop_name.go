@@ -19,6 +19,10 @@
 package gosln
 
 import (
+	"strconv"
+	"strings"
+	"sync"
+
 	"github.com/donyori/gogo/container"
 	"github.com/donyori/gogo/container/set"
 	"github.com/donyori/gogo/errors"
@@ -55,6 +59,33 @@ type PropName struct {
 	name string
 }
 
+// propNamePool is a pool of property name strings.
+//
+// NewPropName looks up the property name in this pool so that
+// the PropName values with the same name share the same backing string,
+// which saves memory when a large number of nodes and links
+// have properties with the same names.
+//
+// Its keys and values are the same strings.
+var propNamePool sync.Map
+
+// internPropName returns the string in propNamePool
+// that is equal to name.
+// If there is no such string, it stores a copy of name
+// into propNamePool and then returns the copy.
+//
+// The caller should guarantee that name is a valid property name.
+func internPropName(name string) string {
+	if v, ok := propNamePool.Load(name); ok {
+		return v.(string)
+	}
+	// Store a copy of name to avoid retaining
+	// a larger string of which name is a substring.
+	c := strings.Clone(name)
+	v, _ := propNamePool.LoadOrStore(c, c)
+	return v.(string)
+}
+
 // NewPropName returns a PropName whose value is propName.
 //
 // A valid property name consists of alphanumeric characters and
@@ -62,9 +93,12 @@ type PropName struct {
 // does not begin with "sln", and is up to 65535 bytes long.
 // If propName is invalid, NewPropName reports a *InvalidPropNameError.
 // (To test whether err is *InvalidPropNameError, use function errors.As.)
+//
+// The PropName values returned by NewPropName with the same name
+// share the same backing string.
 func NewPropName(propName string) (pn PropName, err error) {
 	if IsValidPropNameString(propName) {
-		pn.name = propName
+		pn.name = internPropName(propName)
 	} else {
 		err = errors.AutoWrap(NewInvalidPropNameError(propName))
 	}
@@ -94,6 +128,50 @@ func (pn PropName) IsValid() bool {
 	return pn.name != ""
 }
 
+// Compare returns an integer comparing pn and other
+// by their string values lexicographically.
+// The result is 0 if pn == other, -1 if pn < other, and +1 if pn > other.
+//
+// In particular, an invalid property name is less than
+// any valid property name.
+func (pn PropName) Compare(other PropName) int {
+	return strings.Compare(pn.name, other.name)
+}
+
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes the property name as a JSON string.
+// In particular, an invalid property name is encoded as "".
+func (pn PropName) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(pn.name)), nil
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON string of a valid property name.
+// In particular, null and "" are decoded as the zero-value PropName
+// (i.e., unspecified).
+//
+// It reports an error if data is not a JSON string or null.
+// It reports a *InvalidPropNameError if the property name is invalid.
+// (To test whether the error is *InvalidPropNameError,
+// use function errors.As.)
+func (pn *PropName) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONString(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	} else if s == "" {
+		*pn = PropName{}
+		return nil
+	}
+	name, err := NewPropName(s)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	*pn = name
+	return nil
+}
+
 // PropNameSet is a set of property names, all of which are valid PropName.
 //
 // If an invalid PropName is about to be put into this set,
@@ -114,6 +192,28 @@ func (pn PropName) IsValid() bool {
 //	}
 type PropNameSet interface {
 	set.Set[PropName]
+
+	// ToSortedSlice returns a new slice of the property names in the set,
+	// sorted in ascending order of their string values.
+	ToSortedSlice() []PropName
+
+	// MarshalJSON encodes the set as a JSON array of
+	// the property name strings, sorted as in the method ToSortedSlice.
+	//
+	// It implements the interface encoding/json.Marshaler.
+	MarshalJSON() ([]byte, error)
+
+	// UnmarshalJSON decodes a JSON array of property name strings
+	// and replaces the property names in the set with them.
+	// In particular, null is decoded as an empty set.
+	//
+	// It reports a *InvalidPropNameError if any property name is invalid
+	// (including ""), in which case the set is not modified.
+	// (To test whether the error is *InvalidPropNameError,
+	// use function errors.As.)
+	//
+	// It implements the interface encoding/json.Unmarshaler.
+	UnmarshalJSON(data []byte) error
 }
 
 // NewPropNameSet creates a new PropNameSet.
@@ -133,9 +233,29 @@ func NewPropNameSet(capacity int) PropNameSet {
 		func(x PropName) error {
 			return NewInvalidPropNameError(x.String())
 		},
+		nil,
 	)
 }
 
+// FilteredPropNameSet returns a new PropNameSet containing
+// the property names in pns that satisfy keep.
+//
+// It does not modify pns.
+// If keep is nil, all property names in pns are kept.
+// If pns is nil, it returns an empty PropNameSet.
+func FilteredPropNameSet(pns PropNameSet, keep func(name PropName) bool) PropNameSet {
+	r := NewPropNameSet(0)
+	if pns != nil {
+		pns.Range(func(x PropName) (cont bool) {
+			if keep == nil || keep(x) {
+				r.Add(x)
+			}
+			return true
+		})
+	}
+	return r
+}
+
 // mutExclPropNameSet is an implementation of interface PropNameSet.
 //
 // It can associate with one or more collections
@@ -258,6 +378,26 @@ func (mepns *mutExclPropNameSet) Clear() {
 	mepns.s.Clear()
 }
 
+func (mepns *mutExclPropNameSet) ToSortedSlice() []PropName {
+	mepns.checkInit()
+	return mepns.s.ToSortedSlice()
+}
+
+func (mepns *mutExclPropNameSet) MarshalJSON() ([]byte, error) {
+	mepns.checkInit()
+	return mepns.s.MarshalJSON()
+}
+
+func (mepns *mutExclPropNameSet) UnmarshalJSON(data []byte) error {
+	mepns.checkInit()
+	err := mepns.s.UnmarshalJSON(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	mepns.removeFromOthers(mepns.s.ToSortedSlice()...)
+	return nil
+}
+
 // checkInit checks whether mepns is initialized.
 // If not, it panics.
 func (mepns *mutExclPropNameSet) checkInit() {
@@ -19,7 +19,10 @@
 package gosln_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -53,6 +56,7 @@ func TestIsValidPropNameString(t *testing.T) {
 		{"ab-d", false},
 		{"sln", false},
 		{"slnID", false},
+		{"slnDeleted", false},
 		{"slnType", false},
 		{longestName + "a", false},
 	}
@@ -70,3 +74,147 @@ func TestIsValidPropNameString(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPropName_SameName(t *testing.T) {
+	const Name = "sameName"
+	buf := []byte(Name)
+	pn1 := gosln.MustNewPropName(Name)
+	pn2 := gosln.MustNewPropName(string(buf))
+	buf[0] = 'x' // modify the buffer to ensure that pn2 does not depend on it
+	pn3, err := gosln.NewPropName(Name[:4] + Name[4:])
+	if err != nil {
+		t.Fatal("new property name -", err)
+	}
+	for i, pn := range []gosln.PropName{pn1, pn2, pn3} {
+		if pn.String() != Name {
+			t.Errorf("pn%d got %q; want %q", i+1, pn, Name)
+		}
+	}
+	if pn1 != pn2 || pn1 != pn3 {
+		t.Errorf("got pn1 %q, pn2 %q, pn3 %q; want all equal", pn1, pn2, pn3)
+	}
+}
+
+func TestPropName_Compare(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	testCases := []struct {
+		x, y gosln.PropName
+		want int
+	}{
+		{a, a, 0},
+		{a, b, -1},
+		{b, a, 1},
+		{gosln.PropName{}, a, -1},
+		{a, gosln.PropName{}, 1},
+		{gosln.PropName{}, gosln.PropName{}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("x=%+q&y=%+q", tc.x, tc.y), func(t *testing.T) {
+			if got := tc.x.Compare(tc.y); got != tc.want {
+				t.Errorf("got %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func BenchmarkNewPropName_Repeated(b *testing.B) {
+	names := [][]byte{
+		[]byte("name"),
+		[]byte("age"),
+		[]byte("email"),
+		[]byte("createdAt"),
+	}
+	pns := make([]gosln.PropName, b.N)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Convert the byte slice to a new string each time
+		// to simulate the property names decoded from the storage.
+		pns[i] = gosln.MustNewPropName(string(names[i%len(names)]))
+	}
+	b.StopTimer()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc))/float64(b.N), "retained-B/op")
+	runtime.KeepAlive(pns)
+}
+
+func TestPropNameSet_ToSortedSlice(t *testing.T) {
+	names := []string{"b", "a_2", "c", "a"}
+	want := []string{"a", "a_2", "b", "c"}
+	for _, setType := range []string{"NewPropNameSet", "ToBeRemoved"} {
+		t.Run(setType, func(t *testing.T) {
+			var pns gosln.PropNameSet
+			if setType == "NewPropNameSet" {
+				pns = gosln.NewPropNameSet(len(names))
+			} else {
+				pns = gosln.NewPropMutateArg(0, len(names)).ToBeRemoved()
+			}
+			for _, name := range names {
+				pns.Add(gosln.MustNewPropName(name))
+			}
+			got := pns.ToSortedSlice()
+			if len(got) != len(want) {
+				t.Fatalf("got %v; want %v", got, want)
+			}
+			for i := range got {
+				if got[i].String() != want[i] {
+					t.Fatalf("got %v; want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPropNameSet_JSON(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	pns := gosln.NewPropNameSet(2)
+	pns.Add(b, a)
+	data, err := json.Marshal(pns)
+	if err != nil {
+		t.Fatal("marshal -", err)
+	} else if string(data) != `["a","b"]` {
+		t.Errorf(`got %s; want ["a","b"]`, data)
+	}
+	if data, err = json.Marshal(gosln.NewPropNameSet(0)); err != nil || string(data) != "[]" {
+		t.Errorf("empty set - got %s, %v; want [], <nil>", data, err)
+	}
+
+	back := gosln.NewPropNameSet(0)
+	back.Add(gosln.MustNewPropName("old"))
+	if err = json.Unmarshal([]byte(`["b","a"]`), back); err != nil {
+		t.Fatal("unmarshal -", err)
+	} else if back.Len() != 2 || !back.ContainsSet(pns) {
+		t.Errorf("got %v; want %v", back.ToSortedSlice(), pns.ToSortedSlice())
+	}
+	if err = json.Unmarshal([]byte("null"), back); err != nil || back.Len() != 0 {
+		t.Errorf("null - got %v, %v; want an empty set, <nil>", back.ToSortedSlice(), err)
+	}
+
+	for _, data := range []string{`["a","1b"]`, `["a",""]`} {
+		back.Add(a)
+		err = json.Unmarshal([]byte(data), back)
+		var e *gosln.InvalidPropNameError
+		if !errors.As(err, &e) {
+			t.Errorf("%s - got %v; want *InvalidPropNameError", data, err)
+		} else if back.Len() != 1 || !back.ContainsItem(a) {
+			t.Errorf("%s - set modified, got %v", data, back.ToSortedSlice())
+		}
+	}
+	if err = json.Unmarshal([]byte(`"a"`), back); err == nil {
+		t.Error("non-array - got nil error")
+	}
+
+	// Names unmarshaled into ToBeRemoved are removed from ToBeSet.
+	pma := gosln.NewPropMutateArg(1, 0)
+	pma.ToBeSet().Set(a, 1)
+	if err = json.Unmarshal([]byte(`["a"]`), pma.ToBeRemoved()); err != nil {
+		t.Fatal("unmarshal ToBeRemoved -", err)
+	} else if pma.ToBeSet().Len() != 0 {
+		t.Error("property a still in ToBeSet after unmarshaling ToBeRemoved")
+	}
+}
@@ -19,37 +19,47 @@
 package gosln
 
 import (
+	"encoding/base64"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
 )
 
 // PropType represents the type of property.
 type PropType int8
 
 const (
-	PTBool       PropType = 1 + iota // bool
-	PTInt                            // int
-	PTInt8                           // int8
-	PTInt16                          // int16
-	PTInt32                          // int32
-	PTInt64                          // int64
-	PTUint                           // uint
-	PTUint8                          // uint8
-	PTUint16                         // uint16
-	PTUint32                         // uint32
-	PTUint64                         // uint64
-	PTUintptr                        // uintptr
-	PTFloat32                        // float32
-	PTFloat64                        // float64
-	PTComplex64                      // complex64
-	PTComplex128                     // complex128
-	PTBytes                          // []byte
-	PTString                         // string
-	PTTime                           // time.Time
-	PTDate                           // gosln.Date
-	maxPropType                      // PropType(21)
+	PTBool         PropType = 1 + iota // bool
+	PTInt                              // int
+	PTInt8                             // int8
+	PTInt16                            // int16
+	PTInt32                            // int32
+	PTInt64                            // int64
+	PTUint                             // uint
+	PTUint8                            // uint8
+	PTUint16                           // uint16
+	PTUint32                           // uint32
+	PTUint64                           // uint64
+	PTUintptr                          // uintptr
+	PTFloat32                          // float32
+	PTFloat64                          // float64
+	PTComplex64                        // complex64
+	PTComplex128                       // complex128
+	PTBytes                            // []byte
+	PTString                           // string
+	PTTime                             // time.Time
+	PTDate                             // gosln.Date
+	PTBoolSlice                        // []bool
+	PTIntSlice                         // []int
+	PTInt64Slice                       // []int64
+	PTFloat64Slice                     // []float64
+	PTStringSlice                      // []string
+	maxPropType                        // PropType(26)
 )
 
 // Before running the following command, please make sure the numeric value
@@ -63,6 +73,9 @@ var (
 	// propTypeOfMap is a map from reflect.Type to PropType,
 	//used by PropTypeOf.
 	propTypeOfMap map[reflect.Type]PropType
+	// propTypeOfNameMap is a map from the result of PropType.String
+	// to PropType, used by ParsePropType.
+	propTypeOfNameMap map[string]PropType
 )
 
 func init() {
@@ -86,10 +99,17 @@ func init() {
 	propTypes[PTString-1] = reflect.TypeOf("")
 	propTypes[PTTime-1] = reflect.TypeOf(time.Time{})
 	propTypes[PTDate-1] = reflect.TypeOf(Date{})
+	propTypes[PTBoolSlice-1] = reflect.TypeOf([]bool(nil))
+	propTypes[PTIntSlice-1] = reflect.TypeOf([]int(nil))
+	propTypes[PTInt64Slice-1] = reflect.TypeOf([]int64(nil))
+	propTypes[PTFloat64Slice-1] = reflect.TypeOf([]float64(nil))
+	propTypes[PTStringSlice-1] = reflect.TypeOf([]string(nil))
 
 	propTypeOfMap = make(map[reflect.Type]PropType, len(propTypes))
+	propTypeOfNameMap = make(map[string]PropType, len(propTypes))
 	for i := PropType(1); i < maxPropType; i++ {
 		propTypeOfMap[propTypes[i-1]] = i
+		propTypeOfNameMap[i.String()] = i
 	}
 }
 
@@ -100,6 +120,118 @@ func PropTypeOf(v any) PropType {
 	return propTypeOfMap[reflect.TypeOf(v)]
 }
 
+// ParsePropType returns the property type whose method String returns s,
+// such as "int8", "[]byte", "time.Time", and "gosln.Date".
+//
+// It is the inverse of the method String of the valid property types.
+// If s is not the name of any valid property type,
+// ParsePropType returns 0 and a *InvalidPropTypeError.
+// (To test whether err is *InvalidPropTypeError, use function errors.As.)
+func ParsePropType(s string) (PropType, error) {
+	pt, ok := propTypeOfNameMap[s]
+	if !ok {
+		return 0, errors.AutoWrap(&InvalidPropTypeError{name: s, hasName: true})
+	}
+	return pt, nil
+}
+
+// FormatPropValue returns a string representation of
+// the property value v, which is suitable for display and templating.
+//
+// The representations are as follows:
+//   - bool: "true" or "false".
+//   - Integers: decimal integers.
+//   - Floating-point and complex numbers: the shortest decimal
+//     representations that round-trip, as produced by strconv
+//     with format 'g' and precision -1.
+//   - []byte: standard base64 encoding with padding.
+//   - string: the string itself.
+//   - time.Time: RFC 3339 format with nanoseconds (time.RFC3339Nano).
+//   - gosln.Date: the result of its method String.
+//   - Slices ([]bool, []int, []int64, []float64, and []string):
+//     the representations of the elements, separated by ", "
+//     and enclosed in square brackets,
+//     where the string elements are quoted by strconv.Quote
+//     (e.g., [1, 2, 3] and ["a", "b"]).
+//
+// If v is not a valid property value,
+// FormatPropValue returns fmt.Sprint(v).
+func FormatPropValue(v any) string {
+	switch x := v.(type) {
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.FormatInt(int64(x), 10)
+	case int8:
+		return strconv.FormatInt(int64(x), 10)
+	case int16:
+		return strconv.FormatInt(int64(x), 10)
+	case int32:
+		return strconv.FormatInt(int64(x), 10)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uint:
+		return strconv.FormatUint(uint64(x), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(x), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(x), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(x), 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case uintptr:
+		return strconv.FormatUint(uint64(x), 10)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case complex64:
+		return strconv.FormatComplex(complex128(x), 'g', -1, 64)
+	case complex128:
+		return strconv.FormatComplex(x, 'g', -1, 128)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	case string:
+		return x
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case Date:
+		return x.String()
+	case []bool:
+		return formatSlice(x, strconv.FormatBool)
+	case []int:
+		return formatSlice(x, strconv.Itoa)
+	case []int64:
+		return formatSlice(x, func(i int64) string {
+			return strconv.FormatInt(i, 10)
+		})
+	case []float64:
+		return formatSlice(x, func(f float64) string {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		})
+	case []string:
+		return formatSlice(x, strconv.Quote)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatSlice returns the representation of the slice s
+// used by function FormatPropValue,
+// with the elements formatted by format.
+func formatSlice[E any](s []E, format func(e E) string) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range s {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(format(s[i]))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
 // IsValid reports whether the property type is known.
 func (i PropType) IsValid() bool {
 	return i > 0 && i < maxPropType
@@ -115,6 +247,19 @@ func (i PropType) GoType() reflect.Type {
 	return nil
 }
 
+// ZeroValue returns the zero value of the Go type
+// corresponding to the property type,
+// such as false for PTBool, 0 for PTInt, "" for PTString,
+// []byte(nil) for PTBytes, time.Time{} for PTTime, and Date{} for PTDate.
+//
+// It returns nil if the property type is invalid.
+func (i PropType) ZeroValue() any {
+	if i > 0 && i < maxPropType {
+		return reflect.Zero(propTypes[i-1]).Interface()
+	}
+	return nil
+}
+
 // IsConvertibleTo reports whether the property type i can convert to type t.
 func (i PropType) IsConvertibleTo(t PropType) bool {
 	if i <= 0 || i >= maxPropType || t <= 0 || t >= maxPropType {
@@ -214,6 +359,77 @@ func (i PropType) IsByteString() bool {
 	return false
 }
 
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes the property type as a JSON string of its name,
+// i.e., the result of its method String (e.g., "int8" and "gosln.Date"),
+// so that the encoding does not depend on the numeric values of PropType.
+// In particular, an invalid property type is encoded as "".
+func (i PropType) MarshalJSON() ([]byte, error) {
+	if !i.IsValid() {
+		return []byte(`""`), nil
+	}
+	return []byte(strconv.Quote(i.String())), nil
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON string of the name of a valid property type
+// (see function ParsePropType).
+// In particular, null and "" are decoded as 0 (i.e., unspecified).
+//
+// It reports an error if data is not a JSON string or null.
+// It reports a *InvalidPropTypeError if the name is unknown.
+// (To test whether the error is *InvalidPropTypeError, use function errors.As.)
+func (i *PropType) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONString(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	} else if s == "" {
+		*i = 0
+		return nil
+	}
+	pt, err := ParsePropType(s)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	*i = pt
+	return nil
+}
+
+// IsSlice reports whether the property type is a slice of scalars,
+// including []bool, []int, []int64, []float64, and []string.
+//
+// In particular, []byte is a byte string rather than a slice
+// in terms of property types, so IsSlice returns false for PTBytes.
+func (i PropType) IsSlice() bool {
+	switch i {
+	case PTBoolSlice, PTIntSlice, PTInt64Slice, PTFloat64Slice, PTStringSlice:
+		return true
+	}
+	return false
+}
+
+// ElemType returns the property type of the elements
+// if the property type is a slice of scalars (see method IsSlice).
+//
+// It returns 0 if the property type is not a slice of scalars.
+func (i PropType) ElemType() PropType {
+	switch i {
+	case PTBoolSlice:
+		return PTBool
+	case PTIntSlice:
+		return PTInt
+	case PTInt64Slice:
+		return PTInt64
+	case PTFloat64Slice:
+		return PTFloat64
+	case PTStringSlice:
+		return PTString
+	}
+	return 0
+}
+
 // PropTypeMap is a property name-type map,
 // where the names are valid PropName
 // and the types are valid PropType.
@@ -262,8 +478,66 @@ func NewPropTypeMap(capacity int) PropTypeMap {
 		func(value PropType) bool {
 			return value.IsValid()
 		},
-		func(value PropType) error {
+		func(_ PropName, value PropType) error {
 			return NewInvalidPropTypeError(value)
 		},
 	)
 }
+
+// ClonePropTypeMap returns a copy of ptm.
+//
+// Modifying the returned PropTypeMap does not affect ptm, and vice versa.
+//
+// If ptm is nil, ClonePropTypeMap returns nil.
+func ClonePropTypeMap(ptm PropTypeMap) PropTypeMap {
+	if ptm == nil {
+		return nil
+	} else if vm, ok := ptm.(*validMap[PropName, PropType]); ok {
+		return vm.clone()
+	}
+	c := NewPropTypeMap(ptm.Len())
+	c.SetMap(ptm)
+	return c
+}
+
+// PropTypeMapToGoMap converts ptm to a Go map
+// from the property name strings to the property types.
+//
+// If ptm is nil, PropTypeMapToGoMap returns nil.
+func PropTypeMapToGoMap(ptm PropTypeMap) map[string]PropType {
+	if ptm == nil {
+		return nil
+	}
+	m := make(map[string]PropType, ptm.Len())
+	ptm.Range(func(x mapping.Entry[PropName, PropType]) (cont bool) {
+		m[x.Key.String()] = x.Value
+		return true
+	})
+	return m
+}
+
+// PropTypeMapFromGoMap converts the Go map m,
+// from the property name strings to the property types,
+// to a new PropTypeMap.
+//
+// If m is nil, it returns an empty PropTypeMap.
+//
+// If any key of m is an invalid property name
+// (see function IsValidPropNameString),
+// it reports a *InvalidPropNameError.
+// If any value of m is an invalid property type,
+// it reports a *InvalidPropTypeError.
+// (To test the type of err, use function errors.As.)
+func PropTypeMapFromGoMap(m map[string]PropType) (PropTypeMap, error) {
+	ptm := NewPropTypeMap(len(m))
+	for k, pt := range m {
+		name, err := NewPropName(k)
+		if err != nil {
+			return nil, errors.AutoWrap(err)
+		} else if !pt.IsValid() {
+			return nil, errors.AutoWrap(NewInvalidPropTypeError(pt))
+		}
+		ptm.Set(name, pt)
+	}
+	return ptm, nil
+}
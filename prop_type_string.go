@@ -28,12 +28,17 @@ func _() {
 	_ = x[PTString-18]
 	_ = x[PTTime-19]
 	_ = x[PTDate-20]
-	_ = x[maxPropType-21]
+	_ = x[PTBoolSlice-21]
+	_ = x[PTIntSlice-22]
+	_ = x[PTInt64Slice-23]
+	_ = x[PTFloat64Slice-24]
+	_ = x[PTStringSlice-25]
+	_ = x[maxPropType-26]
 }
 
-const _PropType_name = "boolintint8int16int32int64uintuint8uint16uint32uint64uintptrfloat32float64complex64complex128[]bytestringtime.Timegosln.DatePropType(21)"
+const _PropType_name = "boolintint8int16int32int64uintuint8uint16uint32uint64uintptrfloat32float64complex64complex128[]bytestringtime.Timegosln.Date[]bool[]int[]int64[]float64[]stringPropType(26)"
 
-var _PropType_index = [...]uint8{0, 4, 7, 11, 16, 21, 26, 30, 35, 41, 47, 53, 60, 67, 74, 83, 93, 99, 105, 114, 124, 136}
+var _PropType_index = [...]uint8{0, 4, 7, 11, 16, 21, 26, 30, 35, 41, 47, 53, 60, 67, 74, 83, 93, 99, 105, 114, 124, 130, 135, 142, 151, 159, 171}
 
 func (i PropType) String() string {
 	i -= 1
@@ -19,6 +19,7 @@
 package gosln_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -57,6 +58,14 @@ func TestPropTypeOf(t *testing.T) {
 		{"", gosln.PTString},
 		{time.Time{}, gosln.PTTime},
 		{gosln.Date{}, gosln.PTDate},
+		{[]bool{}, gosln.PTBoolSlice},
+		{[]int{}, gosln.PTIntSlice},
+		{[]int64{}, gosln.PTInt64Slice},
+		{[]float64{}, gosln.PTFloat64Slice},
+		{[]string{}, gosln.PTStringSlice},
+		{[]int8{}, 0},
+		{[]any{}, 0},
+		{[]gosln.Date{}, 0},
 		{MyInt(0), 0},
 		{intPtr, 0},
 		{gosln.Type{}, 0},
@@ -100,8 +109,13 @@ func TestPropType_GoType(t *testing.T) {
 		{gosln.PTString, ""},
 		{gosln.PTTime, time.Time{}},
 		{gosln.PTDate, gosln.Date{}},
-		{21, nil},
-		{22, nil},
+		{gosln.PTBoolSlice, []bool(nil)},
+		{gosln.PTIntSlice, []int(nil)},
+		{gosln.PTInt64Slice, []int64(nil)},
+		{gosln.PTFloat64Slice, []float64(nil)},
+		{gosln.PTStringSlice, []string(nil)},
+		{26, nil},
+		{27, nil},
 	}
 
 	for _, tc := range testCases {
@@ -118,6 +132,187 @@ func TestPropType_GoType(t *testing.T) {
 	}
 }
 
+func TestPropType_ZeroValue(t *testing.T) {
+	testCases := []struct {
+		t    gosln.PropType
+		want any
+	}{
+		{-1, nil},
+		{0, nil},
+		{gosln.PTBool, false},
+		{gosln.PTInt, 0},
+		{gosln.PTInt8, int8(0)},
+		{gosln.PTInt16, int16(0)},
+		{gosln.PTInt32, int32(0)},
+		{gosln.PTInt64, int64(0)},
+		{gosln.PTUint, uint(0)},
+		{gosln.PTUint8, uint8(0)},
+		{gosln.PTUint16, uint16(0)},
+		{gosln.PTUint32, uint32(0)},
+		{gosln.PTUint64, uint64(0)},
+		{gosln.PTUintptr, uintptr(0)},
+		{gosln.PTFloat32, float32(0)},
+		{gosln.PTFloat64, float64(0)},
+		{gosln.PTComplex64, complex64(0)},
+		{gosln.PTComplex128, complex128(0)},
+		{gosln.PTBytes, []byte(nil)},
+		{gosln.PTString, ""},
+		{gosln.PTTime, time.Time{}},
+		{gosln.PTDate, gosln.Date{}},
+		{gosln.PTBoolSlice, []bool(nil)},
+		{gosln.PTIntSlice, []int(nil)},
+		{gosln.PTInt64Slice, []int64(nil)},
+		{gosln.PTFloat64Slice, []float64(nil)},
+		{gosln.PTStringSlice, []string(nil)},
+		{26, nil},
+		{27, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("i=%d", tc.t), func(t *testing.T) {
+			got := tc.t.ZeroValue()
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v; want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropType_IsSliceAndElemType(t *testing.T) {
+	for pt := gosln.PropType(-1); pt <= 27; pt++ {
+		t.Run(fmt.Sprintf("i=%d", pt), func(t *testing.T) {
+			goType := pt.GoType()
+			wantSlice := goType != nil && goType.Kind() == reflect.Slice && pt != gosln.PTBytes
+			if got := pt.IsSlice(); got != wantSlice {
+				t.Errorf("got IsSlice %t; want %t", got, wantSlice)
+			}
+			var wantElem gosln.PropType
+			if wantSlice {
+				wantElem = gosln.PropTypeOf(reflect.Zero(goType.Elem()).Interface())
+			}
+			if got := pt.ElemType(); got != wantElem {
+				t.Errorf("got ElemType %v; want %v", got, wantElem)
+			}
+		})
+	}
+}
+
+func TestPropType_MarshalJSON(t *testing.T) {
+	testCases := []struct {
+		t    gosln.PropType
+		want string
+	}{
+		{-1, `""`},
+		{0, `""`},
+		{gosln.PTBool, `"bool"`},
+		{gosln.PTInt8, `"int8"`},
+		{gosln.PTBytes, `"[]byte"`},
+		{gosln.PTTime, `"time.Time"`},
+		{gosln.PTDate, `"gosln.Date"`},
+		{gosln.PTStringSlice, `"[]string"`},
+		{26, `""`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("i=%d", tc.t), func(t *testing.T) {
+			got, err := json.Marshal(tc.t)
+			if err != nil {
+				t.Fatal(err)
+			} else if string(got) != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPropType_UnmarshalJSON(t *testing.T) {
+	for pt := gosln.PTBool; pt <= gosln.PTStringSlice; pt++ {
+		t.Run(fmt.Sprintf("i=%d", pt), func(t *testing.T) {
+			data, err := json.Marshal(pt)
+			if err != nil {
+				t.Fatal("marshal -", err)
+			}
+			got := gosln.PTString
+			if pt == gosln.PTString {
+				got = gosln.PTBool
+			}
+			err = json.Unmarshal(data, &got)
+			if err != nil {
+				t.Error("unmarshal -", err)
+			} else if got != pt {
+				t.Errorf("got %v; want %v", got, pt)
+			}
+		})
+	}
+
+	testCases := []struct {
+		data    string
+		want    gosln.PropType
+		wantErr bool
+	}{
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`"gosln.Date"`, gosln.PTDate, false},
+		{`"Date"`, 0, true},
+		{`"PropType(0)"`, 0, true},
+		{`3`, 0, true},
+		{`["int"]`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("data=%s", tc.data), func(t *testing.T) {
+			got := gosln.PTInt
+			err := json.Unmarshal([]byte(tc.data), &got)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got no error; want error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Error(err)
+			} else if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("unknown name", func(t *testing.T) {
+		var pt gosln.PropType
+		err := json.Unmarshal([]byte(`"float"`), &pt)
+		var target *gosln.InvalidPropTypeError
+		if !errors.As(err, &target) {
+			t.Errorf("got error %v; want *InvalidPropTypeError", err)
+		}
+	})
+}
+
+func TestParsePropType(t *testing.T) {
+	for pt := gosln.PTBool; pt <= gosln.PTStringSlice; pt++ {
+		t.Run(fmt.Sprintf("s=%q", pt.String()), func(t *testing.T) {
+			got, err := gosln.ParsePropType(pt.String())
+			if err != nil {
+				t.Error(err)
+			} else if got != pt {
+				t.Errorf("got %v; want %v", got, pt)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "Bool", "int ", "byte", "rune", "Date", "time.Duration", "PropType(0)", "PropType(26)", "[]uint8", "[]any"} {
+		t.Run(fmt.Sprintf("s=%q", s), func(t *testing.T) {
+			got, err := gosln.ParsePropType(s)
+			var target *gosln.InvalidPropTypeError
+			if !errors.As(err, &target) {
+				t.Errorf("got error %v; want *InvalidPropTypeError", err)
+			}
+			if got != 0 {
+				t.Errorf("got %v; want 0", got)
+			}
+		})
+	}
+}
+
 func TestPropTypeMap_Set(t *testing.T) {
 	const (
 		NoError int8 = iota
@@ -188,3 +383,86 @@ func TestPropTypeMap_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestClonePropTypeMap(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	src := gosln.NewPropTypeMap(2)
+	src.Set(a, gosln.PTInt)
+	src.Set(b, gosln.PTDate)
+
+	r := gosln.ClonePropTypeMap(src)
+	if r.Len() != 2 {
+		t.Errorf("got Len %d; want 2", r.Len())
+	}
+	if pt, present := r.Get(a); !present || pt != gosln.PTInt {
+		t.Errorf("got a %v (present: %t); want %v (present: true)", pt, present, gosln.PTInt)
+	}
+	if pt, present := r.Get(b); !present || pt != gosln.PTDate {
+		t.Errorf("got b %v (present: %t); want %v (present: true)", pt, present, gosln.PTDate)
+	}
+	r.Set(a, gosln.PTString)
+	r.Remove(b)
+	if pt, _ := src.Get(a); pt != gosln.PTInt {
+		t.Errorf("source modified through the clone, got a %v", pt)
+	}
+	if _, present := src.Get(b); !present {
+		t.Error("source modified through the clone, b is absent")
+	}
+
+	func() {
+		defer func() {
+			e := recover()
+			err, ok := e.(error)
+			var target *gosln.InvalidPropTypeError
+			if !ok || !errors.As(err, &target) {
+				t.Errorf("invalid type on clone - got panic %v; want *InvalidPropTypeError", e)
+			}
+		}()
+		r.Set(a, 0)
+	}()
+
+	if r = gosln.ClonePropTypeMap(nil); r != nil {
+		t.Errorf("nil source - got %v; want nil", r)
+	}
+	if r = gosln.ClonePropTypeMap(gosln.NewPropTypeMap(0)); r == nil || r.Len() != 0 {
+		t.Errorf("empty source - got %v; want an empty PropTypeMap", r)
+	}
+}
+
+func TestPropTypeMapToGoMapAndFromGoMap(t *testing.T) {
+	a, b := gosln.MustNewPropName("a"), gosln.MustNewPropName("b")
+	ptm := gosln.NewPropTypeMap(2)
+	ptm.Set(a, gosln.PTInt)
+	ptm.Set(b, gosln.PTBytes)
+
+	m := gosln.PropTypeMapToGoMap(ptm)
+	want := map[string]gosln.PropType{"a": gosln.PTInt, "b": gosln.PTBytes}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v; want %v", m, want)
+	}
+	if m = gosln.PropTypeMapToGoMap(nil); m != nil {
+		t.Errorf("nil PropTypeMap - got %v; want nil", m)
+	}
+
+	back, err := gosln.PropTypeMapFromGoMap(want)
+	if err != nil {
+		t.Fatal(err)
+	} else if m = gosln.PropTypeMapToGoMap(back); !reflect.DeepEqual(m, want) {
+		t.Errorf("got %v; want %v", m, want)
+	}
+	if back, err = gosln.PropTypeMapFromGoMap(nil); err != nil || back == nil || back.Len() != 0 {
+		t.Errorf("nil Go map - got %v, %v; want an empty PropTypeMap, nil", back, err)
+	}
+	for _, name := range []string{"", "1a", "A", "slnID"} {
+		_, err = gosln.PropTypeMapFromGoMap(map[string]gosln.PropType{name: gosln.PTInt})
+		var pnErr *gosln.InvalidPropNameError
+		if !errors.As(err, &pnErr) {
+			t.Errorf("invalid name %q - got error %v; want *InvalidPropNameError", name, err)
+		}
+	}
+	_, err = gosln.PropTypeMapFromGoMap(map[string]gosln.PropType{"a": 0})
+	var ptErr *gosln.InvalidPropTypeError
+	if !errors.As(err, &ptErr) {
+		t.Errorf("invalid type - got error %v; want *InvalidPropTypeError", err)
+	}
+}
@@ -0,0 +1,347 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"sort"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+)
+
+// Schema describes the structure of a Semantic Link Network,
+// consisting of the node types, the link types,
+// and the properties on the nodes and links of each type.
+//
+// A Schema can be built incrementally by observing
+// the semantic nodes and links with its methods AddNode and AddLink.
+type Schema struct {
+	Nodes map[Type]*TypeSchema // Schemas of the node types.
+	Links map[Type]*TypeSchema // Schemas of the link types.
+
+	// SeparateNodeAndLinkTypes is an optional rule
+	// requiring the node types and link types to be drawn from
+	// disjoint namespaces, that is, no type is used for both nodes and links.
+	//
+	// It is checked by the method CheckTypeNamespaces.
+	SeparateNodeAndLinkTypes bool
+}
+
+// NewSchema creates a new empty Schema.
+func NewSchema() *Schema {
+	return &Schema{
+		Nodes: make(map[Type]*TypeSchema),
+		Links: make(map[Type]*TypeSchema),
+	}
+}
+
+// AddNode records the type of the specified node and
+// the properties on it into the schema.
+//
+// It does nothing if node is nil or the node type is invalid.
+func (s *Schema) AddNode(node *Node) {
+	if node == nil || !node.Type.IsValid() {
+		return
+	}
+	if s.Nodes == nil {
+		s.Nodes = make(map[Type]*TypeSchema)
+	}
+	addToTypeSchemaMap(s.Nodes, node.Type, node.Props)
+}
+
+// AddLink records the type of the specified link and
+// the properties on it into the schema.
+//
+// It does nothing if link is nil or the link type is invalid.
+//
+// AddLink does not record the nodes to which the link connects.
+// To record them, use the method AddNode.
+func (s *Schema) AddLink(link *Link) {
+	if link == nil || !link.Type.IsValid() {
+		return
+	}
+	if s.Links == nil {
+		s.Links = make(map[Type]*TypeSchema)
+	}
+	addToTypeSchemaMap(s.Links, link.Type, link.Props)
+}
+
+// CheckTypeNamespaces checks the rule SeparateNodeAndLinkTypes.
+//
+// If the rule is enabled and any type is used for both nodes and links,
+// it returns an error that wraps *TypeNamespaceError
+// recording such types.
+// (To test the type of the error, use function errors.As.)
+// Otherwise, it returns nil.
+func (s *Schema) CheckTypeNamespaces() error {
+	if !s.SeparateNodeAndLinkTypes {
+		return nil
+	}
+	var types []Type
+	for t := range s.Nodes {
+		if _, ok := s.Links[t]; ok {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	sortTypes(types)
+	return errors.AutoWrap(NewTypeNamespaceError(types...))
+}
+
+// TypeSchema describes the properties on
+// the semantic nodes or links of a particular type.
+type TypeSchema struct {
+	// PropTypes maps the names of the properties observed
+	// on the nodes or links of the type to their property types.
+	//
+	// The property types are best-effort:
+	// if a property is observed with different types,
+	// its type in PropTypes is the most frequently observed one.
+	// If there is a tie, the smallest PropType value wins.
+	PropTypes PropTypeMap
+
+	// Mixed holds the names of the properties
+	// observed with more than one property type.
+	Mixed PropNameSet
+
+	// counts records the number of times that each property type
+	// has been observed for each property name.
+	counts map[PropName]map[PropType]int
+}
+
+// NewTypeSchema creates a new empty TypeSchema.
+func NewTypeSchema() *TypeSchema {
+	return &TypeSchema{
+		PropTypes: NewPropTypeMap(0),
+		Mixed:     NewPropNameSet(0),
+	}
+}
+
+// addProps records the names and types of the properties in props
+// into the TypeSchema.
+func (ts *TypeSchema) addProps(props PropMap) {
+	if props == nil || props.Len() == 0 {
+		return
+	}
+	if ts.PropTypes == nil {
+		ts.PropTypes = NewPropTypeMap(props.Len())
+	}
+	if ts.Mixed == nil {
+		ts.Mixed = NewPropNameSet(0)
+	}
+	if ts.counts == nil {
+		ts.counts = make(map[PropName]map[PropType]int, props.Len())
+	}
+	props.Range(func(x mapping.Entry[PropName, any]) (cont bool) {
+		pt := PropTypeOf(x.Value)
+		if !pt.IsValid() {
+			return true
+		}
+		c := ts.counts[x.Key]
+		if c == nil {
+			c = make(map[PropType]int, 1)
+			ts.counts[x.Key] = c
+		}
+		c[pt]++
+		if len(c) > 1 {
+			ts.Mixed.Add(x.Key)
+		}
+		best, bestCount := PropType(0), 0
+		for t, n := range c {
+			if n > bestCount || n == bestCount && t < best {
+				best, bestCount = t, n
+			}
+		}
+		ts.PropTypes.Set(x.Key, best)
+		return true
+	})
+}
+
+// addToTypeSchemaMap records the type t and the properties props
+// into the TypeSchema corresponding to t in the map m.
+//
+// The caller should guarantee that m is not nil and t is valid.
+func addToTypeSchemaMap(m map[Type]*TypeSchema, t Type, props PropMap) {
+	ts := m[t]
+	if ts == nil {
+		ts = NewTypeSchema()
+		m[t] = ts
+	}
+	ts.addProps(props)
+}
+
+// SchemaDiff records the differences between two schemas.
+type SchemaDiff struct {
+	AddedNodeTypes   []Type // Node types in the new schema but not in the old one.
+	RemovedNodeTypes []Type // Node types in the old schema but not in the new one.
+	AddedLinkTypes   []Type // Link types in the new schema but not in the old one.
+	RemovedLinkTypes []Type // Link types in the old schema but not in the new one.
+
+	// NodeTypes records the differences in properties
+	// for the node types in both schemas.
+	//
+	// Only the node types with differences are recorded.
+	NodeTypes map[Type]*TypeSchemaDiff
+
+	// LinkTypes records the differences in properties
+	// for the link types in both schemas.
+	//
+	// Only the link types with differences are recorded.
+	LinkTypes map[Type]*TypeSchemaDiff
+}
+
+// IsEmpty reports whether there is no difference recorded in sd.
+func (sd SchemaDiff) IsEmpty() bool {
+	return len(sd.AddedNodeTypes) == 0 && len(sd.RemovedNodeTypes) == 0 &&
+		len(sd.AddedLinkTypes) == 0 && len(sd.RemovedLinkTypes) == 0 &&
+		len(sd.NodeTypes) == 0 && len(sd.LinkTypes) == 0
+}
+
+// TypeSchemaDiff records the differences in properties
+// between two schemas of the same node or link type.
+type TypeSchemaDiff struct {
+	// AddedProps holds the properties in the new schema
+	// but not in the old one, with their types in the new schema.
+	AddedProps PropTypeMap
+
+	// RemovedProps holds the properties in the old schema
+	// but not in the new one, with their types in the old schema.
+	RemovedProps PropTypeMap
+
+	// RetypedProps holds the properties in both schemas
+	// whose types are different.
+	RetypedProps map[PropName]PropTypeChange
+}
+
+// PropTypeChange records the change of a property type.
+type PropTypeChange struct {
+	Old PropType // The property type in the old schema.
+	New PropType // The property type in the new schema.
+}
+
+// DiffSchemas compares the schemas oldSchema and newSchema,
+// and returns their differences.
+//
+// A nil schema is treated as an empty schema.
+//
+// The types in the slices of the returned SchemaDiff
+// are sorted in ascending order of their string values.
+func DiffSchemas(oldSchema, newSchema *Schema) SchemaDiff {
+	var oldNodes, oldLinks, newNodes, newLinks map[Type]*TypeSchema
+	if oldSchema != nil {
+		oldNodes, oldLinks = oldSchema.Nodes, oldSchema.Links
+	}
+	if newSchema != nil {
+		newNodes, newLinks = newSchema.Nodes, newSchema.Links
+	}
+	var sd SchemaDiff
+	sd.AddedNodeTypes, sd.RemovedNodeTypes, sd.NodeTypes = diffTypeSchemaMaps(
+		oldNodes, newNodes)
+	sd.AddedLinkTypes, sd.RemovedLinkTypes, sd.LinkTypes = diffTypeSchemaMaps(
+		oldLinks, newLinks)
+	return sd
+}
+
+// diffTypeSchemaMaps compares the type schemas in oldMap and newMap.
+//
+// It returns the types only in newMap (added),
+// the types only in oldMap (removed),
+// and the differences in properties for the types in both maps (diff).
+func diffTypeSchemaMaps(oldMap, newMap map[Type]*TypeSchema) (
+	added, removed []Type, diff map[Type]*TypeSchemaDiff) {
+	for t, oldTS := range oldMap {
+		newTS, ok := newMap[t]
+		if !ok {
+			removed = append(removed, t)
+			continue
+		}
+		tsd := diffTypeSchemas(oldTS, newTS)
+		if tsd != nil {
+			if diff == nil {
+				diff = make(map[Type]*TypeSchemaDiff)
+			}
+			diff[t] = tsd
+		}
+	}
+	for t := range newMap {
+		if _, ok := oldMap[t]; !ok {
+			added = append(added, t)
+		}
+	}
+	sortTypes(added)
+	sortTypes(removed)
+	return
+}
+
+// diffTypeSchemas compares the type schemas oldTS and newTS.
+//
+// It returns nil if there is no difference.
+func diffTypeSchemas(oldTS, newTS *TypeSchema) *TypeSchemaDiff {
+	var oldProps, newProps PropTypeMap
+	if oldTS != nil {
+		oldProps = oldTS.PropTypes
+	}
+	if newTS != nil {
+		newProps = newTS.PropTypes
+	}
+	tsd := &TypeSchemaDiff{
+		AddedProps:   NewPropTypeMap(0),
+		RemovedProps: NewPropTypeMap(0),
+		RetypedProps: make(map[PropName]PropTypeChange),
+	}
+	if oldProps != nil {
+		oldProps.Range(func(x mapping.Entry[PropName, PropType]) (cont bool) {
+			var newType PropType
+			var present bool
+			if newProps != nil {
+				newType, present = newProps.Get(x.Key)
+			}
+			if !present {
+				tsd.RemovedProps.Set(x.Key, x.Value)
+			} else if newType != x.Value {
+				tsd.RetypedProps[x.Key] = PropTypeChange{Old: x.Value, New: newType}
+			}
+			return true
+		})
+	}
+	if newProps != nil {
+		newProps.Range(func(x mapping.Entry[PropName, PropType]) (cont bool) {
+			if oldProps == nil {
+				tsd.AddedProps.Set(x.Key, x.Value)
+			} else if _, present := oldProps.Get(x.Key); !present {
+				tsd.AddedProps.Set(x.Key, x.Value)
+			}
+			return true
+		})
+	}
+	if tsd.AddedProps.Len() == 0 && tsd.RemovedProps.Len() == 0 &&
+		len(tsd.RetypedProps) == 0 {
+		return nil
+	}
+	return tsd
+}
+
+// sortTypes sorts types in ascending order of their string values.
+func sortTypes(types []Type) {
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].t < types[j].t
+	})
+}
@@ -0,0 +1,224 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestSchema_AddNodeAndAddLink(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	event := gosln.MustNewType("Event")
+	knows := gosln.MustNewType("Knows")
+	name := gosln.MustNewPropName("name")
+	age := gosln.MustNewPropName("age")
+	when := gosln.MustNewPropName("when")
+	since := gosln.MustNewPropName("since")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+
+	newProps := func(kv ...any) gosln.PropMap {
+		pm := gosln.NewPropMap(len(kv) / 2)
+		for i := 0; i < len(kv); i += 2 {
+			pm.Set(kv[i].(gosln.PropName), kv[i+1])
+		}
+		return pm
+	}
+	newNode := func(t gosln.Type, kv ...any) *gosln.Node {
+		return &gosln.Node{NL: gosln.NL{Type: t, Props: newProps(kv...)}}
+	}
+
+	s := gosln.NewSchema()
+	s.AddNode(newNode(person, name, "Alice", age, 30))
+	s.AddNode(newNode(person, name, "Bob", age, 25))
+	s.AddNode(newNode(person, name, "Carol"))
+	s.AddNode(newNode(event, when, date))
+	s.AddNode(newNode(event, when, date))
+	s.AddNode(newNode(event, when, time.Now()))
+	s.AddNode(nil)
+	s.AddLink(&gosln.Link{NL: gosln.NL{Type: knows, Props: newProps(since, 2020)}})
+	s.AddLink(nil)
+
+	if n := len(s.Nodes); n != 2 {
+		t.Errorf("got %d node types; want 2", n)
+	}
+	if n := len(s.Links); n != 1 {
+		t.Errorf("got %d link types; want 1", n)
+	}
+
+	testCases := []struct {
+		name      string
+		ts        *gosln.TypeSchema
+		propName  gosln.PropName
+		wantType  gosln.PropType
+		wantMixed bool
+	}{
+		{"Person.name", s.Nodes[person], name, gosln.PTString, false},
+		{"Person.age", s.Nodes[person], age, gosln.PTInt, false},
+		{"Event.when", s.Nodes[event], when, gosln.PTDate, true},
+		{"Knows.since", s.Links[knows], since, gosln.PTInt, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.ts == nil {
+				t.Fatal("type schema is nil")
+			}
+			pt, present := tc.ts.PropTypes.Get(tc.propName)
+			if !present {
+				t.Fatal("property is absent")
+			}
+			if pt != tc.wantType {
+				t.Errorf("got type %v; want %v", pt, tc.wantType)
+			}
+			if mixed := tc.ts.Mixed.ContainsItem(tc.propName); mixed != tc.wantMixed {
+				t.Errorf("got mixed %t; want %t", mixed, tc.wantMixed)
+			}
+		})
+	}
+
+	if n := s.Nodes[person].PropTypes.Len(); n != 2 {
+		t.Errorf("got %d properties on Person; want 2", n)
+	}
+	if n := s.Nodes[person].Mixed.Len(); n != 0 {
+		t.Errorf("got %d mixed properties on Person; want 0", n)
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	event := gosln.MustNewType("Event")
+	knows := gosln.MustNewType("Knows")
+	name := gosln.MustNewPropName("name")
+	age := gosln.MustNewPropName("age")
+	email := gosln.MustNewPropName("email")
+	newTypeSchema := func(kv ...any) *gosln.TypeSchema {
+		ts := gosln.NewTypeSchema()
+		for i := 0; i < len(kv); i += 2 {
+			ts.PropTypes.Set(kv[i].(gosln.PropName), kv[i+1].(gosln.PropType))
+		}
+		return ts
+	}
+
+	oldSchema := gosln.NewSchema()
+	oldSchema.Nodes[person] = newTypeSchema(name, gosln.PTString, age, gosln.PTInt, email, gosln.PTString)
+	oldSchema.Links[knows] = newTypeSchema()
+	newSchema := gosln.NewSchema()
+	newSchema.Nodes[person] = newTypeSchema(name, gosln.PTString, age, gosln.PTInt64)
+	newSchema.Nodes[event] = newTypeSchema()
+	newSchema.Links[knows] = newTypeSchema()
+
+	sd := gosln.DiffSchemas(oldSchema, newSchema)
+	if sd.IsEmpty() {
+		t.Fatal("got empty diff")
+	}
+	if len(sd.AddedNodeTypes) != 1 || sd.AddedNodeTypes[0] != event {
+		t.Errorf("got AddedNodeTypes %v; want [%v]", sd.AddedNodeTypes, event)
+	}
+	if len(sd.RemovedNodeTypes) != 0 || len(sd.AddedLinkTypes) != 0 || len(sd.RemovedLinkTypes) != 0 {
+		t.Errorf("got RemovedNodeTypes %v, AddedLinkTypes %v, RemovedLinkTypes %v; want all empty",
+			sd.RemovedNodeTypes, sd.AddedLinkTypes, sd.RemovedLinkTypes)
+	}
+	if len(sd.LinkTypes) != 0 {
+		t.Errorf("got %d link types with differences; want 0", len(sd.LinkTypes))
+	}
+	if len(sd.NodeTypes) != 1 {
+		t.Errorf("got %d node types with differences; want 1", len(sd.NodeTypes))
+	}
+	tsd := sd.NodeTypes[person]
+	if tsd == nil {
+		t.Fatal("no differences for Person")
+	}
+	if n := tsd.AddedProps.Len(); n != 0 {
+		t.Errorf("got %d added properties; want 0", n)
+	}
+	if pt, present := tsd.RemovedProps.Get(email); !present || pt != gosln.PTString || tsd.RemovedProps.Len() != 1 {
+		t.Errorf("got %d removed properties, email %v (present: %t); want 1, %v (present: true)",
+			tsd.RemovedProps.Len(), pt, present, gosln.PTString)
+	}
+	wantChange := gosln.PropTypeChange{Old: gosln.PTInt, New: gosln.PTInt64}
+	if c, ok := tsd.RetypedProps[age]; !ok || c != wantChange || len(tsd.RetypedProps) != 1 {
+		t.Errorf("got retyped properties %v; want {%v: %v}", tsd.RetypedProps, age, wantChange)
+	}
+
+	if sd = gosln.DiffSchemas(newSchema, newSchema); !sd.IsEmpty() {
+		t.Errorf("diff a schema with itself - got %+v; want empty", sd)
+	}
+	sd = gosln.DiffSchemas(nil, newSchema)
+	if len(sd.AddedNodeTypes) != 2 || sd.AddedNodeTypes[0] != event || sd.AddedNodeTypes[1] != person {
+		t.Errorf("diff from nil - got AddedNodeTypes %v; want [%v %v]", sd.AddedNodeTypes, event, person)
+	}
+}
+
+func TestSchema_CheckTypeNamespaces(t *testing.T) {
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	tag := gosln.MustNewType("Tag")
+
+	testCases := []struct {
+		name      string
+		separate  bool
+		nodeTypes []gosln.Type
+		linkTypes []gosln.Type
+		wantTypes []gosln.Type // nil for no error
+	}{
+		{"disjoint", true, []gosln.Type{person, tag}, []gosln.Type{knows}, nil},
+		{"overlapping", true, []gosln.Type{person, tag}, []gosln.Type{knows, tag}, []gosln.Type{tag}},
+		{"all overlapping", true, []gosln.Type{person, tag}, []gosln.Type{person, tag}, []gosln.Type{person, tag}},
+		{"rule disabled", false, []gosln.Type{person, tag}, []gosln.Type{knows, tag}, nil},
+		{"empty", true, nil, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := gosln.NewSchema()
+			s.SeparateNodeAndLinkTypes = tc.separate
+			for _, typ := range tc.nodeTypes {
+				s.Nodes[typ] = gosln.NewTypeSchema()
+			}
+			for _, typ := range tc.linkTypes {
+				s.Links[typ] = gosln.NewTypeSchema()
+			}
+			err := s.CheckTypeNamespaces()
+			if tc.wantTypes == nil {
+				if err != nil {
+					t.Errorf("got error %v; want nil", err)
+				}
+				return
+			}
+			var e *gosln.TypeNamespaceError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v; want *TypeNamespaceError", err)
+			}
+			got := e.Types()
+			if len(got) != len(tc.wantTypes) {
+				t.Fatalf("got types %v; want %v", got, tc.wantTypes)
+			}
+			for i := range got {
+				if got[i] != tc.wantTypes[i] {
+					t.Errorf("got types %v; want %v", got, tc.wantTypes)
+					break
+				}
+			}
+		})
+	}
+}
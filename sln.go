@@ -20,27 +20,39 @@ package gosln
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
+	"github.com/donyori/gogo/errors"
 	"github.com/donyori/gogo/inout"
 )
 
-// SLN contains basic CRUD (create, read, update, and delete)
-// operations on the Semantic Link Network.
+// ReadOnlySLN contains the read operations on the Semantic Link Network.
 //
 // It is safe for concurrency.
 //
-// For each CRUD operation, it supports the client in using
+// For each read operation, it supports the client in using
 // context.Context to set a deadline or a cancellation signal.
 // If the deadline and cancellation are not required,
 // the client should pass a context.Background() instead of nil.
+// If the context is done before the operation starts,
+// the operation reports an error wrapping the error of the context
+// (i.e., ctx.Err()), unless it reports an error on the arguments first.
+// (To test whether an error is caused by the context,
+// use function errors.Is with context.Canceled
+// or context.DeadlineExceeded.)
+// Whether an operation in progress is interrupted when the context is done
+// depends on the implementation.
 //
-// Its method Close marks the SLN as unusable and releases the resource.
-// Close waits for the in-flight CURD operations rather than interrupting them.
-// The CURD operations after Close report ErrSLNClosed.
+// Its method Close marks the ReadOnlySLN as unusable and
+// releases the resource.
+// The read operations after Close report ErrSLNClosed.
 // (To test whether an error is ErrSLNClosed, use function errors.Is.)
-// The successive calls to Close do nothing
-// but block until the SLN is closed or any error occurs during closing.
-type SLN interface {
+//
+// SLN embeds ReadOnlySLN.
+// In addition, a point-in-time read-only view of an SLN
+// can be obtained by the method Snapshot of SLN.
+type ReadOnlySLN interface {
 	inout.Closer
 
 	// NumNodeType returns the number of node types and any error encountered.
@@ -53,6 +65,16 @@ type SLN interface {
 	// the specified conditions and any error encountered.
 	NumNode(ctx context.Context, cond NodeMatchCond) (n int, err error)
 
+	// ExistsAtLeast reports whether there are at least k nodes
+	// that satisfy the specified conditions, and any error encountered.
+	//
+	// Unlike NumNode, it stops counting once k matches are found.
+	// In particular, ExistsAtLeast with k = 1 reports
+	// whether any node satisfies the conditions.
+	//
+	// If k is non-positive, it returns true.
+	ExistsAtLeast(ctx context.Context, cond NodeMatchCond, k int) (ok bool, err error)
+
 	// NumLink returns the number of links that satisfy
 	// the specified conditions and any error encountered.
 	NumLink(ctx context.Context, cond LinkMatchCond) (n int, err error)
@@ -63,10 +85,30 @@ type SLN interface {
 	// GetLinkTypes returns all link types in this SLN.
 	GetLinkTypes(ctx context.Context) (types []Type, err error)
 
+	// InferSchema walks through all nodes and links in this SLN
+	// and returns the schema that consists of all node and link types
+	// and the properties observed on the nodes and links of each type.
+	//
+	// The property types in the schema are best-effort.
+	// The properties observed with more than one type are flagged.
+	// See TypeSchema for details.
+	InferSchema(ctx context.Context) (schema *Schema, err error)
+
+	// GetCommonPropertyNames returns the names of the properties
+	// present on every node of type t and any error encountered.
+	//
+	// The soft-removed nodes are not considered.
+	// If there is no node of type t, it returns an empty PropNameSet.
+	//
+	// GetCommonPropertyNames reports a *InvalidTypeError if t is invalid.
+	// (To test whether err is *InvalidTypeError, use function errors.As.)
+	GetCommonPropertyNames(ctx context.Context, t Type) (names PropNameSet, err error)
+
 	// GetNodeByID returns the node with the specified ID
 	// and any error encountered.
 	//
-	// GetNodeByID reports a *NodeNotExistError if the node does not exist.
+	// GetNodeByID reports a *NodeNotExistError if the node does not exist
+	// or has been soft-removed.
 	// (To test whether err is *NodeNotExistError, use function errors.As.)
 	//
 	// propTypes specify the types of properties on the node.
@@ -77,6 +119,22 @@ type SLN interface {
 	// (To test whether err is *PropTypeError, use function errors.As.)
 	GetNodeByID(ctx context.Context, id ID, propTypes PropTypeMap) (node *Node, err error)
 
+	// GetNodesByIDs returns the nodes with the specified IDs,
+	// keyed by their IDs, and any error encountered.
+	//
+	// The IDs of the nodes that do not exist or have been soft-removed
+	// are silently omitted from the result,
+	// as are the invalid and duplicate IDs.
+	// The client can find them by checking the keys of the result.
+	//
+	// propTypes specify the types of properties on the nodes.
+	// The properties not in propTypes are discarded.
+	//
+	// GetNodesByIDs reports a *PropTypeError if any property
+	// does not match its specified type.
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	GetNodesByIDs(ctx context.Context, ids []ID, propTypes PropTypeMap) (nodes map[ID]*Node, err error)
+
 	// GetLinkByID returns the link with the specified ID
 	// and any error encountered.
 	//
@@ -113,6 +171,247 @@ type SLN interface {
 	// (To test whether err is *PropTypeError, use function errors.As.)
 	GetAllLinks(ctx context.Context, propTypes PropTypeMap, cond LinkMatchCond) (links []*Link, err error)
 
+	// GetNodesPage is like GetAllNodes,
+	// but returns only the nodes in the specified page,
+	// together with the total number of nodes that satisfy the conditions.
+	//
+	// The nodes are sorted as specified by page before paging.
+	// See Page for details.
+	//
+	// If the offset of page is beyond the number of nodes,
+	// it returns an empty slice and nil error.
+	GetNodesPage(ctx context.Context, propTypes PropTypeMap, cond NodeMatchCond, page Page) (
+		nodes []*Node, total int, err error)
+
+	// GetLinksPage is like GetAllLinks,
+	// but returns only the links in the specified page,
+	// together with the total number of links that satisfy the conditions.
+	//
+	// The links are sorted as specified by page before paging.
+	// See Page for details.
+	//
+	// If the offset of page is beyond the number of links,
+	// it returns an empty slice and nil error.
+	GetLinksPage(ctx context.Context, propTypes PropTypeMap, cond LinkMatchCond, page Page) (
+		links []*Link, total int, err error)
+
+	// GetNeighborhood returns the node with the specified ID,
+	// together with its links and neighbors (i.e., its one-hop neighborhood),
+	// and any error encountered.
+	//
+	// opts specify the direction and conditions of the links
+	// and the types of properties on the returned nodes and links.
+	// See NeighborhoodOptions for details.
+	//
+	// GetNeighborhood reports a *NodeNotExistError
+	// if the center node does not exist.
+	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	//
+	// GetNeighborhood reports a *PropTypeError if any property
+	// does not match its specified type.
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	GetNeighborhood(ctx context.Context, id ID, opts NeighborhoodOptions) (neighborhood *Neighborhood, err error)
+
+	// GetLinksByNode returns the links that start from or point to
+	// the node with the specified ID in the direction dir
+	// and satisfy the specified conditions, and any error encountered.
+	//
+	// If dir is invalid (such as zero-value), DirBoth is used.
+	// A link that starts from and points to the node is returned once.
+	// The links whose other end has been soft-removed are skipped,
+	// as in the method GetNeighborhood.
+	//
+	// propTypes specify the types of properties on the link.
+	// The properties not in propTypes are discarded.
+	//
+	// GetLinksByNode reports a *NodeNotExistError if the node does not exist
+	// or has been soft-removed.
+	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	//
+	// GetLinksByNode reports a *PropTypeError if any property
+	// does not match its specified type.
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	GetLinksByNode(ctx context.Context, id ID, dir Direction, propTypes PropTypeMap, cond LinkMatchCond) (
+		links []*Link, err error)
+
+	// GetNodeWithGroupedNeighbors returns the node with the specified ID,
+	// together with its neighbors grouped by the types of
+	// the links connecting them, and any error encountered.
+	//
+	// linkTypes specify the link types to be resolved.
+	// Every type in linkTypes is a key of the field Neighbors
+	// of the returned GroupedNode, even if there is no such neighbor.
+	// If linkTypes is nil, all link types are resolved,
+	// and only the link types with neighbors are the keys.
+	//
+	// opts specify the direction and conditions of the links
+	// and the types of properties on the returned nodes,
+	// as in the method GetNeighborhood.
+	// Its field LinkPropTypes is ignored.
+	//
+	// GetNodeWithGroupedNeighbors reports a *NodeNotExistError
+	// if the center node does not exist.
+	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	//
+	// GetNodeWithGroupedNeighbors reports a *PropTypeError if any property
+	// does not match its specified type.
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	GetNodeWithGroupedNeighbors(ctx context.Context, id ID, linkTypes TypeSet, opts NeighborhoodOptions) (
+		groupedNode *GroupedNode, err error)
+
+	// DistinctPropValues returns the distinct values of the property
+	// with the specified name on the nodes of type t, converted to pt,
+	// and any error encountered.
+	//
+	// The values are deduplicated after conversion,
+	// using the same comparison rules as the method Match of PropMatchClause.
+	// The nodes without the property and the soft-removed nodes are skipped.
+	// The order of the values is unspecified.
+	//
+	// DistinctPropValues reports a *InvalidTypeError if t is invalid.
+	// (To test whether err is *InvalidTypeError, use function errors.As.)
+	//
+	// DistinctPropValues reports a *InvalidPropTypeError if pt is invalid.
+	// (To test whether err is *InvalidPropTypeError, use function errors.As.)
+	//
+	// DistinctPropValues reports a *PropTypeError if any value
+	// cannot be converted to pt.
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	DistinctPropValues(ctx context.Context, t Type, name PropName, pt PropType) (values []any, err error)
+
+	// DegreeDistribution returns the degree distribution of all nodes
+	// in this SLN and any error encountered.
+	//
+	// The degree distribution is a map from the degree
+	// to the number of nodes with that degree.
+	// The degree of a node is the number of links
+	// that satisfy cond and start from (if direction is DirOutgoing),
+	// point to (if direction is DirIncoming),
+	// or start from or point to (if direction is DirBoth) the node.
+	// A link that starts from and points to the same node
+	// contributes 2 to the degree of that node if direction is DirBoth.
+	// The links whose other end has been soft-removed are not counted,
+	// as in the method GetNeighborhood.
+	//
+	// The nodes without such links are counted with the degree 0.
+	//
+	// If direction is invalid (such as zero-value), DirBoth is used.
+	DegreeDistribution(ctx context.Context, direction Direction, cond LinkMatchCond) (dist map[int]int, err error)
+
+	// AggregateNumeric computes the minimum, maximum, and sum of the values
+	// of the property with the specified name on the nodes of type t,
+	// and returns them together with the number of values aggregated
+	// and any error encountered.
+	//
+	// The nodes without the property are skipped
+	// and excluded from the count.
+	// If no node has the property, min, max, and sum are 0.
+	//
+	// AggregateNumeric reports a *PropTypeError if any value of the property
+	// is not a real number (i.e., PropTypeOf(value).IsRealNumber() is false).
+	// (To test whether err is *PropTypeError, use function errors.As.)
+	AggregateNumeric(ctx context.Context, t Type, name PropName) (min, max, sum float64, count int, err error)
+}
+
+// SLN contains basic CRUD (create, read, update, and delete)
+// operations on the Semantic Link Network.
+//
+// It is safe for concurrency.
+//
+// For each CRUD operation, it supports the client in using
+// context.Context to set a deadline or a cancellation signal.
+// If the deadline and cancellation are not required,
+// the client should pass a context.Background() instead of nil.
+// If the context is done before the operation starts,
+// the operation takes no effect and reports an error wrapping
+// the error of the context (i.e., ctx.Err()),
+// unless it reports an error on the arguments first.
+// (To test whether an error is caused by the context,
+// use function errors.Is with context.Canceled
+// or context.DeadlineExceeded.)
+// Whether an operation in progress is interrupted when the context is done
+// depends on the implementation,
+// but an interrupted operation either takes full effect or no effect.
+// The package slntest provides a conformance test for this behavior.
+//
+// Nodes can be removed softly by the method SoftRemoveNodeByID.
+// A soft-removed node is marked as deleted rather than physically removed.
+// It is excluded from the query results unless the query conditions
+// explicitly include deleted nodes
+// (see the method SetIncludeDeleted of NodeMatchClause),
+// and can be restored by the method RestoreNodeByID.
+// The implementations should record the mark with a reserved property name
+// beginning with "sln" (e.g., "slnDeleted"),
+// which never collides with the client's property names.
+//
+// Its method Close marks the SLN as unusable and releases the resource.
+// Close waits for the in-flight CURD operations rather than interrupting them.
+// The CURD operations after Close report ErrSLNClosed.
+// (To test whether an error is ErrSLNClosed, use function errors.Is.)
+// The successive calls to Close do nothing
+// but block until the SLN is closed or any error occurs during closing.
+type SLN interface {
+	ReadOnlySLN
+
+	// Snapshot returns a consistent point-in-time read-only view
+	// of this SLN and any error encountered.
+	//
+	// The subsequent modifications to this SLN do not affect the snapshot,
+	// so long reads (e.g., exports and analytics) on the snapshot
+	// observe a stable graph.
+	//
+	// The field SLN of the nodes and links returned by the snapshot is nil.
+	//
+	// Taking a snapshot may copy the entire network,
+	// costing memory and time proportional to the size of the network
+	// (see the documentation of the implementation for details).
+	// The client should close the snapshot by its method Close
+	// as soon as it is no longer needed, to release the resource.
+	// Closing the snapshot does not close this SLN, and vice versa.
+	Snapshot(ctx context.Context) (snapshot ReadOnlySLN, err error)
+
+	// RegisterObserver registers obs to be notified of
+	// the changes to nodes and links in this SLN.
+	// See Observer for details.
+	//
+	// It returns a function to unregister obs.
+	// After the function returns, obs receives no new notifications,
+	// but may still be processing a notification already dispatched.
+	//
+	// If obs is nil, RegisterObserver does nothing
+	// and returns a no-op function.
+	RegisterObserver(obs Observer) (unregister func())
+
+	// BeginTx begins a transaction on this SLN and returns it
+	// with any error encountered.
+	//
+	// The client should call Commit or Rollback of the transaction
+	// to end it. See Tx for details.
+	BeginTx(ctx context.Context) (tx Tx, err error)
+
+	// RegisterLinkConstraint restricts the links of type linkType
+	// to start from the nodes of the types in fromTypes
+	// and point to the nodes of the types in toTypes.
+	//
+	// If fromTypes (or toTypes) is nil or empty,
+	// the links of type linkType can start from (or point to)
+	// the nodes of any type.
+	// Registering a constraint for linkType replaces the previous one.
+	// To remove the constraint, register it with
+	// both fromTypes and toTypes nil.
+	//
+	// The constraint applies to the links created after registration,
+	// including those created by the transactions.
+	// The existing links are not checked.
+	//
+	// RegisterLinkConstraint does not modify fromTypes and toTypes,
+	// and the subsequent modifications to them do not affect the constraint.
+	//
+	// RegisterLinkConstraint reports a *InvalidTypeError
+	// if linkType is invalid.
+	// (To test whether err is *InvalidTypeError, use function errors.As.)
+	RegisterLinkConstraint(ctx context.Context, linkType Type, fromTypes, toTypes TypeSet) error
+
 	// CreateNode creates a new node with the specified node type t.
 	//
 	// props are initial properties on the new node.
@@ -121,6 +420,27 @@ type SLN interface {
 	// (To test whether err is *InvalidTypeError, use function errors.As.)
 	CreateNode(ctx context.Context, t Type, props PropMap) (node *Node, err error)
 
+	// UpsertNode creates a new node of type t with the specified properties,
+	// or updates an existing node if there is a node of type t
+	// whose property keyName equals that in props.
+	//
+	// When updating, the properties in props are set (added and replaced)
+	// on the existing node, and the other properties on it are kept.
+	// The soft-removed nodes are not considered.
+	// If more than one node matches, the one with the smallest ID is updated.
+	//
+	// It returns the node created or updated,
+	// whether the node is created, and any error encountered.
+	//
+	// UpsertNode reports a *InvalidTypeError if t is invalid.
+	// (To test whether err is *InvalidTypeError, use function errors.As.)
+	//
+	// UpsertNode reports a *InvalidPropNameError
+	// if keyName is absent from props.
+	// (To test whether err is *InvalidPropNameError, use function errors.As.)
+	UpsertNode(ctx context.Context, t Type, keyName PropName, props PropMap) (
+		node *Node, created bool, err error)
+
 	// CreateLink creates a new link with the specified link type t,
 	// starting from the node with ID "from" and
 	// pointing to the node with ID "to".
@@ -130,8 +450,14 @@ type SLN interface {
 	// CreateLink reports a *InvalidTypeError if t is invalid.
 	// (To test whether err is *InvalidTypeError, use function errors.As.)
 	//
-	// CreateLink reports a *NodeNotExistError if from or to does not exist.
+	// CreateLink reports a *NodeNotExistError if from or to does not exist
+	// or has been soft-removed.
 	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	//
+	// CreateLink reports a *LinkConstraintError if the types of
+	// the nodes from and to violate the constraint on t
+	// registered by the method RegisterLinkConstraint.
+	// (To test whether err is *LinkConstraintError, use function errors.As.)
 	CreateLink(ctx context.Context, t Type, from, to ID, props PropMap) (link *Link, err error)
 
 	// RemoveNodeByID removes the node with the specified ID
@@ -140,11 +466,71 @@ type SLN interface {
 	// It returns nil error if there is no such node or id is invalid.
 	RemoveNodeByID(ctx context.Context, id ID) error
 
+	// RemoveNodesByCond removes all nodes that satisfy the specified conditions
+	// and all links associated with them.
+	//
+	// It returns the number of nodes removed and any error encountered.
+	//
+	// RemoveNodesByCond reports an error if cond is nil,
+	// to avoid removing all nodes accidentally.
+	// (Note that a nil NodeMatchCond matches any node.)
+	RemoveNodesByCond(ctx context.Context, cond NodeMatchCond) (removed int, err error)
+
+	// SoftRemoveNodeByID marks the node with the specified ID as deleted
+	// rather than physically removing it.
+	//
+	// The soft-removed node is excluded from the query results
+	// unless the query conditions explicitly include deleted nodes.
+	// The links associated with the node are kept.
+	//
+	// It returns nil error if there is no such node or id is invalid.
+	SoftRemoveNodeByID(ctx context.Context, id ID) error
+
+	// RestoreNodeByID restores the node with the specified ID
+	// that has been soft-removed.
+	//
+	// It does nothing if the node has not been soft-removed.
+	//
+	// RestoreNodeByID reports a *NodeNotExistError if the node does not exist.
+	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	RestoreNodeByID(ctx context.Context, id ID) error
+
 	// RemoveLinkByID removes the link with the specified ID.
 	//
 	// It returns nil error if there is no such link or id is invalid.
 	RemoveLinkByID(ctx context.Context, id ID) error
 
+	// RemoveLinksByCond removes all links that satisfy
+	// the specified conditions.
+	//
+	// It returns the number of links removed and any error encountered.
+	//
+	// RemoveLinksByCond reports an error if cond is nil,
+	// to avoid removing all links accidentally.
+	// (Note that a nil LinkMatchCond matches any link.)
+	RemoveLinksByCond(ctx context.Context, cond LinkMatchCond) (removed int, err error)
+
+	// RenameType renames the node and link type oldType to newType
+	// across the whole SLN atomically.
+	//
+	// Since IDs embed the type, every node and link of oldType
+	// (including the soft-removed nodes) is reassigned a new ID of newType,
+	// and the links associated with the affected nodes are updated
+	// to connect to the new IDs.
+	// After renaming, no node or link is of oldType.
+	//
+	// It returns the mapping from the old IDs to the new IDs
+	// and any error encountered.
+	// If there is no node or link of oldType,
+	// it returns an empty mapping and nil error.
+	//
+	// RenameType reports a *InvalidTypeError if oldType or newType is invalid.
+	// (To test whether err is *InvalidTypeError, use function errors.As.)
+	//
+	// RenameType reports an error if newType is already used
+	// by any node or link, to avoid merging types accidentally.
+	RenameType(ctx context.Context, oldType, newType Type) (idMap map[ID]ID, err error)
+
 	// SetNodeProperties sets the properties on the node
 	// that has the specified ID to the specified properties.
 	//
@@ -167,6 +553,43 @@ type SLN interface {
 	// It returns the node updated and any error encountered.
 	MutateNodeProperties(ctx context.Context, id ID, pma PropMutateArg) (node *Node, err error)
 
+	// UpdateNode updates the properties on the node
+	// that has the specified ID by an atomic read-modify-write.
+	//
+	// It fetches the node, calls fn with the current properties on the node
+	// converted according to propTypes, and then mutates the properties
+	// by the PropMutateArg returned by fn,
+	// as the method MutateNodeProperties does.
+	// The properties not in propTypes are discarded from
+	// the properties passed to fn and the returned node,
+	// but they are kept on the node.
+	// No other modification to the node takes effect
+	// between the fetching and the mutation.
+	// If fn returns a nil or empty PropMutateArg,
+	// the node is not modified.
+	//
+	// fn may be called more than once
+	// (e.g., if the implementation retries on conflict),
+	// so it should have no side effects.
+	// fn should not call the mutating methods of this SLN.
+	// If fn returns an error, UpdateNode returns the error
+	// without modifying the node.
+	//
+	// It returns the node after updating with the properties
+	// converted according to propTypes, and any error encountered.
+	//
+	// UpdateNode reports a *NodeNotExistError if the node does not exist
+	// or has been soft-removed.
+	// (To test whether err is *NodeNotExistError, use function errors.As.)
+	//
+	// UpdateNode reports an error if fn is nil.
+	UpdateNode(
+		ctx context.Context,
+		id ID,
+		propTypes PropTypeMap,
+		fn func(current PropMap) (PropMutateArg, error),
+	) (node *Node, err error)
+
 	// MutateLinkProperties mutates the properties on the link
 	// that has the specified ID.
 	//
@@ -185,6 +608,7 @@ type NL struct {
 // Node records the information of a semantic node.
 type Node struct {
 	NL
+	Deleted bool // Whether this node has been soft-removed.
 }
 
 // Link records the information of a semantic link.
@@ -193,3 +617,239 @@ type Link struct {
 	From *Node // The node from which this link starts.
 	To   *Node // The node to which this link points.
 }
+
+// Detach returns a deep copy of the node
+// whose field SLN is set to nil.
+//
+// The properties on the returned node are independent of
+// those on the original node.
+//
+// If n is nil, Detach returns nil.
+func (n *Node) Detach() *Node {
+	if n == nil {
+		return nil
+	}
+	return &Node{NL: n.NL.detach(), Deleted: n.Deleted}
+}
+
+// ExpectType checks whether the type of the node is t.
+//
+// It returns nil if the type matches.
+// Otherwise, it returns an error that wraps *UnexpectedTypeError
+// recording the node ID, the actual type, and the expected type.
+// (To test the type of the error, use function errors.As.)
+//
+// If n is nil, it reports an error.
+func (n *Node) ExpectType(t Type) error {
+	if n == nil {
+		return errors.AutoNew("node is nil")
+	}
+	return n.NL.expectType(t)
+}
+
+// Equal reports whether n and other have the same ID, type,
+// properties (compared by function EqualPropMaps),
+// and soft-removal state (the field Deleted).
+//
+// The field SLN is ignored.
+// Two nil nodes are equal, and a nil node is not equal to a non-nil node.
+func (n *Node) Equal(other *Node) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	return n.NL.equal(other.NL) && n.Deleted == other.Deleted
+}
+
+// String returns a string representation of the node
+// for debugging and logging, in the form of
+//
+//	<ID>{<name1>=<value1>, <name2>=<value2>, ...}
+//
+// where the properties are sorted by name.
+// The property values are formatted by function FormatPropValue,
+// except that the byte strings are quoted
+// and truncated if they are longer than 64 bytes.
+//
+// If n is nil, String returns "<nil>".
+func (n *Node) String() string {
+	if n == nil {
+		return "<nil>"
+	}
+	var b strings.Builder
+	b.WriteString(n.ID.String())
+	b.WriteByte('{')
+	if n.Props != nil {
+		for i, name := range sortedPropMapNames[any](n.Props, nil) {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			v, _ := n.Props.Get(name)
+			b.WriteString(name.String())
+			b.WriteByte('=')
+			b.WriteString(formatPropValueForDebug(v))
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Detach returns a deep copy of the link
+// whose field SLN and the fields SLN of its From and To nodes
+// are set to nil.
+//
+// The properties on the returned link and its From and To nodes
+// are independent of those on the original link and nodes.
+// If the From and To nodes of the original link are the same,
+// those of the returned link are also the same.
+//
+// If l is nil, Detach returns nil.
+func (l *Link) Detach() *Link {
+	if l == nil {
+		return nil
+	}
+	link := &Link{
+		NL:   l.NL.detach(),
+		From: l.From.Detach(),
+	}
+	if l.To == l.From {
+		link.To = link.From
+	} else {
+		link.To = l.To.Detach()
+	}
+	return link
+}
+
+// ExpectType checks whether the type of the link is t.
+//
+// It returns nil if the type matches.
+// Otherwise, it returns an error that wraps *UnexpectedTypeError
+// recording the link ID, the actual type, and the expected type.
+// (To test the type of the error, use function errors.As.)
+//
+// If l is nil, it reports an error.
+func (l *Link) ExpectType(t Type) error {
+	if l == nil {
+		return errors.AutoNew("link is nil")
+	}
+	return l.NL.expectType(t)
+}
+
+// String returns a string representation of the link
+// for debugging and logging, in the form of
+//
+//	(<From ID>)-[<ID>]->(<To ID>)
+//
+// where a nil From or To node is represented as "<nil>".
+// The properties are not included.
+//
+// If l is nil, String returns "<nil>".
+func (l *Link) String() string {
+	if l == nil {
+		return "<nil>"
+	}
+	from, to := "<nil>", "<nil>"
+	if l.From != nil {
+		from = l.From.ID.String()
+	}
+	if l.To != nil {
+		to = l.To.ID.String()
+	}
+	return "(" + from + ")-[" + l.ID.String() + "]->(" + to + ")"
+}
+
+// IsOutgoingFrom reports whether the link starts from the node
+// with the specified ID, i.e., the link is an outgoing link
+// of that node (see DirOutgoing).
+//
+// It returns false if l or its From node is nil.
+func (l *Link) IsOutgoingFrom(id ID) bool {
+	return l != nil && l.From != nil && l.From.ID == id
+}
+
+// IsIncomingTo reports whether the link points to the node
+// with the specified ID, i.e., the link is an incoming link
+// of that node (see DirIncoming).
+//
+// It returns false if l or its To node is nil.
+func (l *Link) IsIncomingTo(id ID) bool {
+	return l != nil && l.To != nil && l.To.ID == id
+}
+
+// Equal reports whether l and other have the same ID, type,
+// properties (compared by function EqualPropMaps),
+// and From and To nodes (compared by their IDs).
+//
+// The field SLN and the other fields of the From and To nodes are ignored.
+// A nil From or To node is only equal to a nil node.
+// Two nil links are equal, and a nil link is not equal to a non-nil link.
+func (l *Link) Equal(other *Link) bool {
+	if l == nil || other == nil {
+		return l == other
+	}
+	return l.NL.equal(other.NL) &&
+		sameNodeID(l.From, other.From) &&
+		sameNodeID(l.To, other.To)
+}
+
+// detach returns a copy of nl whose field SLN is set to nil
+// and field Props is a copy of the original properties.
+func (nl NL) detach() NL {
+	return NL{
+		ID:    nl.ID,
+		Type:  nl.Type,
+		Props: ClonePropMap(nl.Props),
+	}
+}
+
+// equal reports whether nl and other have the same ID, type,
+// and properties, ignoring the field SLN.
+func (nl NL) equal(other NL) bool {
+	return nl.ID == other.ID && nl.Type == other.Type &&
+		EqualPropMaps(nl.Props, other.Props)
+}
+
+// maxDebugByteStringLen is the maximum length of the byte strings
+// formatted by function formatPropValueForDebug without truncation.
+const maxDebugByteStringLen = 64
+
+// formatPropValueForDebug returns a readable representation of
+// the property value v for the method String of Node.
+//
+// Strings are quoted, []byte values are formatted as
+// []byte("...") with the bytes quoted,
+// both truncated to maxDebugByteStringLen bytes followed by "..."
+// if they are longer.
+// The other values are formatted by the function FormatPropValue.
+func formatPropValueForDebug(v any) string {
+	var s, prefix, suffix string
+	switch x := v.(type) {
+	case string:
+		s = x
+	case []byte:
+		s, prefix, suffix = string(x), "[]byte(", ")"
+	default:
+		return FormatPropValue(v)
+	}
+	if len(s) > maxDebugByteStringLen {
+		return prefix + strconv.Quote(s[:maxDebugByteStringLen]) + "..." + suffix
+	}
+	return prefix + strconv.Quote(s) + suffix
+}
+
+// sameNodeID reports whether a and b are both nil,
+// or both non-nil with the same ID.
+func sameNodeID(a, b *Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ID == b.ID
+}
+
+// expectType returns an error wrapping *UnexpectedTypeError
+// if the type of nl is not t, and nil otherwise.
+func (nl NL) expectType(t Type) error {
+	if nl.Type != t {
+		return errors.AutoWrapSkip(NewUnexpectedTypeError(nl.ID, nl.Type, t), 1)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+func TestLink_Detach(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	data := gosln.MustNewPropName("data")
+	var sln gosln.SLN = &struct{ gosln.SLN }{} // a non-nil placeholder
+	newNL := func(t gosln.Type, i int64) gosln.NL {
+		props := gosln.NewPropMap(1)
+		props.Set(data, []byte{byte(i)})
+		return gosln.NL{SLN: sln, ID: gosln.NewID(t, date, i), Type: t, Props: props}
+	}
+	from := &gosln.Node{NL: newNL(person, 1)}
+	to := &gosln.Node{NL: newNL(person, 2)}
+	link := &gosln.Link{NL: newNL(knows, 3), From: from, To: to}
+
+	got := link.Detach()
+	if got == link || got.From == from || got.To == to {
+		t.Fatal("got the original link or nodes; want copies")
+	}
+	for _, pair := range [][2]*gosln.NL{
+		{&got.NL, &link.NL},
+		{&got.From.NL, &from.NL},
+		{&got.To.NL, &to.NL},
+	} {
+		d, o := pair[0], pair[1]
+		if d.SLN != nil {
+			t.Errorf("%v - got non-nil SLN", d.ID)
+		}
+		if d.ID != o.ID || d.Type != o.Type {
+			t.Errorf("got ID %v, Type %v; want %v, %v", d.ID, d.Type, o.ID, o.Type)
+		}
+		want, err := gosln.PropMapGet[[]byte](o.Props, data)
+		if err != nil {
+			t.Fatal("get original property -", err)
+		}
+		v, err := gosln.PropMapGet[[]byte](d.Props, data)
+		if err != nil {
+			t.Errorf("%v - get property - %v", d.ID, err)
+			continue
+		} else if !bytes.Equal(v, want) {
+			t.Errorf("%v - got property %v; want %v", d.ID, v, want)
+		}
+		orig := want[0]
+		v[0]++
+		d.Props.Set(gosln.MustNewPropName("extra"), true)
+		if o.Props.Len() != 1 || want[0] != orig {
+			t.Errorf("%v - modification on the copy affects the original", d.ID)
+		}
+	}
+
+	if (*gosln.Link)(nil).Detach() != nil {
+		t.Error("detach nil link - got non-nil")
+	}
+}
+
+func TestNode_Detach_NilProps(t *testing.T) {
+	node := &gosln.Node{NL: gosln.NL{
+		SLN:  &struct{ gosln.SLN }{},
+		ID:   gosln.NewID(gosln.MustNewType("Person"), gosln.NowDate(), 0),
+		Type: gosln.MustNewType("Person"),
+	}}
+	got := node.Detach()
+	if got.SLN != nil || got.Props != nil || got.ID != node.ID || got.Type != node.Type {
+		t.Errorf("got %+v; want ID %v, Type %v, nil SLN and nil Props", got.NL, node.ID, node.Type)
+	}
+	if (*gosln.Node)(nil).Detach() != nil {
+		t.Error("detach nil node - got non-nil")
+	}
+}
+
+func TestNodeAndLink_ExpectType(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	event := gosln.MustNewType("Event")
+	knows := gosln.MustNewType("Knows")
+	node := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 1), Type: person}}
+	link := &gosln.Link{NL: gosln.NL{ID: gosln.NewID(knows, date, 1), Type: knows}}
+	var nilNode *gosln.Node
+	var nilLink *gosln.Link
+
+	testCases := []struct {
+		name     string
+		f        func(t gosln.Type) error
+		t        gosln.Type
+		wantErr  bool
+		wantType bool // whether the error wraps *gosln.UnexpectedTypeError
+	}{
+		{"node-match", node.ExpectType, person, false, false},
+		{"node-mismatch", node.ExpectType, event, true, true},
+		{"node-nil", nilNode.ExpectType, person, true, false},
+		{"link-match", link.ExpectType, knows, false, false},
+		{"link-mismatch", link.ExpectType, person, true, true},
+		{"link-nil", nilLink.ExpectType, knows, true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.f(tc.t)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got error %v; want error %t", err, tc.wantErr)
+			}
+			var e *gosln.UnexpectedTypeError
+			if errors.As(err, &e) != tc.wantType {
+				t.Fatalf("got error %v; want *UnexpectedTypeError %t", err, tc.wantType)
+			}
+			if tc.wantType && (e.Want() != tc.t || e.Got() == tc.t) {
+				t.Errorf("got Got %v, Want %v; want Want %v", e.Got(), e.Want(), tc.t)
+			}
+		})
+	}
+}
+
+func TestNodeAndLink_Equal(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	data := gosln.MustNewPropName("data")
+	var sln gosln.SLN = &struct{ gosln.SLN }{} // a non-nil placeholder
+	newNL := func(t gosln.Type, i int64, b byte) gosln.NL {
+		props := gosln.NewPropMap(1)
+		props.Set(data, []byte{b})
+		return gosln.NL{ID: gosln.NewID(t, date, i), Type: t, Props: props}
+	}
+	a, b := &gosln.Node{NL: newNL(person, 1, 1)}, &gosln.Node{NL: newNL(person, 2, 2)}
+	aCopy := &gosln.Node{NL: newNL(person, 1, 1)}
+	aCopy.SLN = sln
+	aOtherProps := &gosln.Node{NL: newNL(person, 1, 9)}
+	aDeleted := &gosln.Node{NL: newNL(person, 1, 1), Deleted: true}
+	aNoProps := &gosln.Node{NL: gosln.NL{ID: a.ID, Type: person}}
+	aEmptyProps := &gosln.Node{NL: gosln.NL{ID: a.ID, Type: person, Props: gosln.NewPropMap(0)}}
+	var nilNode *gosln.Node
+
+	nodeCases := []struct {
+		name string
+		x, y *gosln.Node
+		want bool
+	}{
+		{"same", a, a, true},
+		{"copy", a, aCopy, true},
+		{"different ID", a, b, false},
+		{"different props", a, aOtherProps, false},
+		{"different Deleted", a, aDeleted, false},
+		{"nil and empty props", aNoProps, aEmptyProps, true},
+		{"nil-nil", nilNode, nil, true},
+		{"nil-non-nil", nilNode, a, false},
+		{"non-nil-nil", a, nil, false},
+	}
+	for _, tc := range nodeCases {
+		t.Run("node-"+tc.name, func(t *testing.T) {
+			if got := tc.x.Equal(tc.y); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+
+	link := &gosln.Link{NL: newNL(knows, 3, 3), From: a, To: b}
+	linkCopy := &gosln.Link{NL: newNL(knows, 3, 3), From: aOtherProps, To: &gosln.Node{NL: newNL(person, 2, 8)}}
+	linkCopy.SLN = sln
+	reversed := &gosln.Link{NL: newNL(knows, 3, 3), From: b, To: a}
+	noFrom := &gosln.Link{NL: newNL(knows, 3, 3), To: b}
+	otherProps := &gosln.Link{NL: newNL(knows, 3, 4), From: a, To: b}
+	var nilLink *gosln.Link
+
+	linkCases := []struct {
+		name string
+		x, y *gosln.Link
+		want bool
+	}{
+		{"same", link, link, true},
+		{"copy with different endpoint props", link, linkCopy, true},
+		{"reversed", link, reversed, false},
+		{"nil From", link, noFrom, false},
+		{"both nil From", noFrom, &gosln.Link{NL: newNL(knows, 3, 3), To: b}, true},
+		{"different props", link, otherProps, false},
+		{"nil-nil", nilLink, nil, true},
+		{"nil-non-nil", nilLink, link, false},
+		{"non-nil-nil", link, nil, false},
+	}
+	for _, tc := range linkCases {
+		t.Run("link-"+tc.name, func(t *testing.T) {
+			if got := tc.x.Equal(tc.y); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNodeAndLink_String(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	props := gosln.NewPropMap(5)
+	props.Set(gosln.MustNewPropName("name"), `Bob "B"`)
+	props.Set(gosln.MustNewPropName("age"), 30)
+	props.Set(gosln.MustNewPropName("data"), []byte{'a', 0})
+	props.Set(gosln.MustNewPropName("long"), strings.Repeat("x", 100))
+	props.Set(gosln.MustNewPropName("tags"), []string{"a", "b"})
+	a := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 1), Type: person, Props: props}}
+	b := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 2), Type: person}}
+	link := &gosln.Link{NL: gosln.NL{ID: gosln.NewID(knows, date, 3), Type: knows}, From: a, To: b}
+	var nilNode *gosln.Node
+	var nilLink *gosln.Link
+
+	testCases := []struct {
+		name string
+		s    fmt.Stringer
+		want string
+	}{
+		{"node", a, a.ID.String() + `{age=30, data=[]byte("a\x00"), long="` +
+			strings.Repeat("x", 64) + `"..., name="Bob \"B\"", tags=["a", "b"]}`},
+		{"node without props", b, b.ID.String() + "{}"},
+		{"nil node", nilNode, "<nil>"},
+		{"link", link, "(" + a.ID.String() + ")-[" + link.ID.String() + "]->(" + b.ID.String() + ")"},
+		{"link without To", &gosln.Link{NL: link.NL, From: a},
+			"(" + a.ID.String() + ")-[" + link.ID.String() + "]->(<nil>)"},
+		{"nil link", nilLink, "<nil>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.s.String(); got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLink_IsOutgoingFromAndIsIncomingTo(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	a := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 1), Type: person}}
+	b := &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, 2), Type: person}}
+	c := gosln.NewID(person, date, 3)
+	linkNL := gosln.NL{ID: gosln.NewID(knows, date, 4), Type: knows}
+	link := &gosln.Link{NL: linkNL, From: a, To: b}
+	loop := &gosln.Link{NL: linkNL, From: a, To: a}
+	noEnds := &gosln.Link{NL: linkNL}
+	var nilLink *gosln.Link
+
+	testCases := []struct {
+		name         string
+		link         *gosln.Link
+		id           gosln.ID
+		wantOutgoing bool
+		wantIncoming bool
+	}{
+		{"from", link, a.ID, true, false},
+		{"to", link, b.ID, false, true},
+		{"other", link, c, false, false},
+		{"self-loop", loop, a.ID, true, true},
+		{"no endpoints", noEnds, gosln.ID{}, false, false},
+		{"nil link", nilLink, a.ID, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.link.IsOutgoingFrom(tc.id); got != tc.wantOutgoing {
+				t.Errorf("got IsOutgoingFrom %t; want %t", got, tc.wantOutgoing)
+			}
+			if got := tc.link.IsIncomingTo(tc.id); got != tc.wantIncoming {
+				t.Errorf("got IsIncomingTo %t; want %t", got, tc.wantIncoming)
+			}
+		})
+	}
+}
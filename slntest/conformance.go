@@ -0,0 +1,473 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package slntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/donyori/gosln"
+)
+
+// Types and property names used by RunSLNConformance.
+var (
+	city    = gosln.MustNewType("City")
+	livesIn = gosln.MustNewType("LivesIn")
+
+	ageProp    = gosln.MustNewPropName("age")
+	sinceProp  = gosln.MustNewPropName("since")
+	ratioProp  = gosln.MustNewPropName("ratio")
+	bytesProp  = gosln.MustNewPropName("bytes")
+	stringProp = gosln.MustNewPropName("string")
+)
+
+// RunSLNConformance tests whether the SLNs returned by factory
+// follow the documentation of gosln.SLN.
+//
+// It exercises creating, reading, updating, and removing nodes and links,
+// the removal of links along with their nodes,
+// filtering nodes and links by match conditions,
+// the conversion of properties to the types specified by propTypes,
+// and the handling of context.Context (as TestSLNConformance does).
+//
+// factory is called once for each subtest.
+// It should return a new empty SLN and a function to clean up the SLN.
+// The cleanup function is called after the subtest finishes.
+// If the cleanup function is nil, the SLN is closed instead.
+func RunSLNConformance(t *testing.T, factory func(t *testing.T) (gosln.SLN, func())) {
+	t.Helper()
+	for _, x := range []struct {
+		name string
+		f    func(t *testing.T, sln gosln.SLN)
+	}{
+		{"CRUD", testCRUD},
+		{"CascadeRemoval", testCascadeRemoval},
+		{"MatchCond", testMatchCond},
+		{"PropTypeCoercion", testPropTypeCoercion},
+		{"Canceled", testCanceled},
+		{"DeadlineExceeded", testDeadlineExceeded},
+	} {
+		t.Run(x.name, func(t *testing.T) {
+			sln, cleanup := factory(t)
+			if cleanup == nil {
+				cleanup = func() {
+					_ = sln.Close()
+				}
+			}
+			t.Cleanup(cleanup)
+			x.f(t, sln)
+		})
+	}
+}
+
+// newProps returns a new PropMap holding the specified properties.
+//
+// kv consists of pairs of property names and values.
+func newProps(kv ...any) gosln.PropMap {
+	pm := gosln.NewPropMap(len(kv) / 2)
+	for i := 1; i < len(kv); i += 2 {
+		pm.Set(kv[i-1].(gosln.PropName), kv[i])
+	}
+	return pm
+}
+
+// personTypes returns the types of properties on the person nodes.
+func personTypes() gosln.PropTypeMap {
+	ptm := gosln.NewPropTypeMap(2)
+	ptm.Set(nameProp, gosln.PTString)
+	ptm.Set(ageProp, gosln.PTInt64)
+	return ptm
+}
+
+// knowsTypes returns the types of properties on the knows links.
+func knowsTypes() gosln.PropTypeMap {
+	ptm := gosln.NewPropTypeMap(1)
+	ptm.Set(sinceProp, gosln.PTInt64)
+	return ptm
+}
+
+// mustCreateNode creates a node on sln and stops the test on error.
+func mustCreateNode(t *testing.T, sln gosln.SLN, nodeType gosln.Type, props gosln.PropMap) *gosln.Node {
+	t.Helper()
+	node, err := sln.CreateNode(context.Background(), nodeType, props)
+	if err != nil {
+		t.Fatalf("create node of type %v - %v", nodeType, err)
+	}
+	return node
+}
+
+// mustCreateLink creates a link on sln and stops the test on error.
+func mustCreateLink(t *testing.T, sln gosln.SLN, linkType gosln.Type, from, to gosln.ID, props gosln.PropMap) *gosln.Link {
+	t.Helper()
+	link, err := sln.CreateLink(context.Background(), linkType, from, to, props)
+	if err != nil {
+		t.Fatalf("create link of type %v from %v to %v - %v", linkType, from, to, err)
+	}
+	return link
+}
+
+// testCRUD tests creating, reading, updating, and removing
+// nodes and links on sln.
+//
+// sln should be empty.
+func testCRUD(t *testing.T, sln gosln.SLN) {
+	ctx := context.Background()
+	alice := mustCreateNode(t, sln, person, newProps(nameProp, "Alice", ageProp, int64(30)))
+	bob := mustCreateNode(t, sln, person, newProps(nameProp, "Bob"))
+	if alice.Type != person || !alice.ID.IsValid() || alice.ID.Type() != person {
+		t.Errorf("got node %v; want a valid node of type %v", alice, person)
+	}
+	link := mustCreateLink(t, sln, knows, alice.ID, bob.ID, newProps(sinceProp, int64(2020)))
+	if link.Type != knows || link.From == nil || link.From.ID != alice.ID ||
+		link.To == nil || link.To.ID != bob.ID {
+		t.Errorf("got link %v; want a link of type %v from %v to %v", link, knows, alice.ID, bob.ID)
+	}
+
+	node, err := sln.GetNodeByID(ctx, alice.ID, personTypes())
+	if err != nil {
+		t.Fatal("get node -", err)
+	} else if !gosln.EqualPropMaps(node.Props, alice.Props) {
+		t.Errorf("got properties %v; want %v", node.Props, alice.Props)
+	}
+	gotLink, err := sln.GetLinkByID(ctx, link.ID, knowsTypes())
+	if err != nil {
+		t.Fatal("get link -", err)
+	} else if !gosln.EqualPropMaps(gotLink.Props, link.Props) {
+		t.Errorf("got properties %v; want %v", gotLink.Props, link.Props)
+	}
+
+	_, err = sln.SetNodeProperties(ctx, bob.ID, newProps(nameProp, "Bob", ageProp, int64(25)))
+	if err != nil {
+		t.Fatal("set node properties -", err)
+	}
+	checkNodeProps(t, sln, bob.ID, newProps(nameProp, "Bob", ageProp, int64(25)))
+	pma := gosln.NewPropMutateArg(1, 1)
+	pma.ToBeSet().Set(ageProp, int64(31))
+	pma.ToBeRemoved().Add(nameProp)
+	if _, err = sln.MutateNodeProperties(ctx, alice.ID, pma); err != nil {
+		t.Fatal("mutate node properties -", err)
+	}
+	checkNodeProps(t, sln, alice.ID, newProps(ageProp, int64(31)))
+	if _, err = sln.SetLinkProperties(ctx, link.ID, newProps(sinceProp, int64(2021))); err != nil {
+		t.Fatal("set link properties -", err)
+	}
+	pma = gosln.NewPropMutateArg(0, 1)
+	pma.ToBeRemoved().Add(sinceProp)
+	if _, err = sln.MutateLinkProperties(ctx, link.ID, pma); err != nil {
+		t.Fatal("mutate link properties -", err)
+	} else if gotLink, err = sln.GetLinkByID(ctx, link.ID, knowsTypes()); err != nil {
+		t.Fatal("get link -", err)
+	} else if gotLink.Props.Len() != 0 {
+		t.Errorf("got link properties %v; want empty", gotLink.Props)
+	}
+
+	var ite *gosln.InvalidTypeError
+	if _, err = sln.CreateNode(ctx, gosln.Type{}, nil); !errors.As(err, &ite) {
+		t.Errorf("create node of invalid type - got %v; want *InvalidTypeError", err)
+	}
+	if err = sln.RemoveLinkByID(ctx, link.ID); err != nil {
+		t.Fatal("remove link -", err)
+	}
+	var lne *gosln.LinkNotExistError
+	if _, err = sln.GetLinkByID(ctx, link.ID, nil); !errors.As(err, &lne) {
+		t.Errorf("get removed link - got %v; want *LinkNotExistError", err)
+	}
+	if err = sln.RemoveNodeByID(ctx, bob.ID); err != nil {
+		t.Fatal("remove node -", err)
+	}
+	var nne *gosln.NodeNotExistError
+	if _, err = sln.GetNodeByID(ctx, bob.ID, nil); !errors.As(err, &nne) {
+		t.Errorf("get removed node - got %v; want *NodeNotExistError", err)
+	}
+	if _, err = sln.CreateLink(ctx, knows, alice.ID, bob.ID, nil); !errors.As(err, &nne) {
+		t.Errorf("create link to removed node - got %v; want *NodeNotExistError", err)
+	}
+	if err = sln.RemoveNodeByID(ctx, bob.ID); err != nil {
+		t.Error("remove removed node -", err)
+	}
+	if n, err := sln.NumNode(ctx, nil); err != nil {
+		t.Error("count nodes -", err)
+	} else if n != 1 {
+		t.Errorf("got %d nodes; want 1", n)
+	}
+}
+
+// checkNodeProps checks whether the properties on the node
+// with the specified ID are equal to want.
+func checkNodeProps(t *testing.T, sln gosln.SLN, id gosln.ID, want gosln.PropMap) {
+	t.Helper()
+	node, err := sln.GetNodeByID(context.Background(), id, personTypes())
+	if err != nil {
+		t.Error("get node -", err)
+	} else if !gosln.EqualPropMaps(node.Props, want) {
+		t.Errorf("got properties %v; want %v", node.Props, want)
+	}
+}
+
+// testCascadeRemoval tests whether removing nodes from sln
+// also removes the associated links.
+//
+// sln should be empty.
+func testCascadeRemoval(t *testing.T, sln gosln.SLN) {
+	ctx := context.Background()
+	a := mustCreateNode(t, sln, person, newProps(nameProp, "Alice"))
+	b := mustCreateNode(t, sln, person, newProps(nameProp, "Bob"))
+	c := mustCreateNode(t, sln, person, newProps(nameProp, "Carol"))
+	mustCreateLink(t, sln, knows, a.ID, b.ID, nil)
+	mustCreateLink(t, sln, knows, b.ID, c.ID, nil)
+	ca := mustCreateLink(t, sln, knows, c.ID, a.ID, nil)
+
+	if err := sln.RemoveNodeByID(ctx, b.ID); err != nil {
+		t.Fatal("remove node -", err)
+	}
+	links, err := sln.GetAllLinks(ctx, nil, nil)
+	if err != nil {
+		t.Fatal("get all links -", err)
+	} else if len(links) != 1 || links[0].ID != ca.ID {
+		t.Errorf("got links %v; want only %v", links, ca.ID)
+	}
+
+	pmc := gosln.NewPropMatchClause(1, 0, 0)
+	pmc.Equal().Set(nameProp, "Carol")
+	nmc := gosln.NewNodeMatchClause()
+	nmc.SetPropMatchClause(pmc)
+	if removed, err := sln.RemoveNodesByCond(ctx, gosln.NodeMatchCond{nmc}); err != nil {
+		t.Fatal("remove nodes by condition -", err)
+	} else if removed != 1 {
+		t.Errorf("removed %d nodes; want 1", removed)
+	}
+	if n, err := sln.NumLink(ctx, nil); err != nil {
+		t.Error("count links -", err)
+	} else if n != 0 {
+		t.Errorf("got %d links; want 0", n)
+	}
+	if n, err := sln.NumNode(ctx, nil); err != nil {
+		t.Error("count nodes -", err)
+	} else if n != 1 {
+		t.Errorf("got %d nodes; want 1", n)
+	}
+}
+
+// testMatchCond tests filtering nodes and links on sln
+// by match conditions.
+//
+// sln should be empty.
+func testMatchCond(t *testing.T, sln gosln.SLN) {
+	ctx := context.Background()
+	alice := mustCreateNode(t, sln, person, newProps(nameProp, "Alice", ageProp, int64(30)))
+	bob := mustCreateNode(t, sln, person, newProps(nameProp, "Bob", ageProp, int64(25)))
+	carol := mustCreateNode(t, sln, person, newProps(nameProp, "Carol"))
+	paris := mustCreateNode(t, sln, city, newProps(nameProp, "Paris"))
+	ab := mustCreateLink(t, sln, knows, alice.ID, bob.ID, nil)
+	mustCreateLink(t, sln, knows, bob.ID, carol.ID, nil)
+	ap := mustCreateLink(t, sln, livesIn, alice.ID, paris.ID, nil)
+
+	newNodeCond := func(nodeType gosln.Type, f func(pmc gosln.PropMatchClause)) gosln.NodeMatchCond {
+		nmc := gosln.NewNodeMatchClause()
+		nmc.SetType(nodeType)
+		if f != nil {
+			pmc := gosln.NewPropMatchClause(1, 1, 1)
+			f(pmc)
+			nmc.SetPropMatchClause(pmc)
+		}
+		return gosln.NodeMatchCond{nmc}
+	}
+	nodeCases := []struct {
+		name string
+		cond gosln.NodeMatchCond
+		want []gosln.ID
+	}{
+		{"nil", nil, []gosln.ID{alice.ID, bob.ID, carol.ID, paris.ID}},
+		{"empty", gosln.NodeMatchCond{}, nil},
+		{"type", newNodeCond(person, nil), []gosln.ID{alice.ID, bob.ID, carol.ID}},
+		{"equal", newNodeCond(gosln.Type{}, func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(nameProp, "Paris")
+		}), []gosln.ID{paris.ID}},
+		{"present", newNodeCond(person, func(pmc gosln.PropMatchClause) {
+			pmc.Present().Add(ageProp)
+		}), []gosln.ID{alice.ID, bob.ID}},
+		{"absent", newNodeCond(person, func(pmc gosln.PropMatchClause) {
+			pmc.Absent().Add(ageProp)
+		}), []gosln.ID{carol.ID}},
+		{"equal-mismatch", newNodeCond(city, func(pmc gosln.PropMatchClause) {
+			pmc.Equal().Set(nameProp, "Alice")
+		}), nil},
+	}
+	for _, tc := range nodeCases {
+		nodes, err := sln.GetAllNodes(ctx, nil, tc.cond)
+		if err != nil {
+			t.Errorf("cond=%s, get all nodes - %v", tc.name, err)
+			continue
+		}
+		got := make([]gosln.ID, len(nodes))
+		for i := range nodes {
+			got[i] = nodes[i].ID
+		}
+		if !sameIDs(got, tc.want) {
+			t.Errorf("cond=%s, got nodes %v; want %v", tc.name, got, tc.want)
+		}
+	}
+
+	toCity := gosln.NewNodeMatchClause()
+	toCity.SetType(city)
+	toCityLMC := gosln.NewLinkMatchClause()
+	toCityLMC.SetToNodeMatchClause(toCity)
+	knowsLMC := gosln.NewLinkMatchClause()
+	knowsLMC.SetType(knows)
+	fromAlice := gosln.NewNodeMatchClause()
+	fromAlice.SetID(alice.ID)
+	fromAliceLMC := gosln.NewLinkMatchClause()
+	fromAliceLMC.SetType(knows)
+	fromAliceLMC.SetFromNodeMatchClause(fromAlice)
+	linkCases := []struct {
+		name string
+		cond gosln.LinkMatchCond
+		want int
+	}{
+		{"nil", nil, 3},
+		{"type", gosln.LinkMatchCond{knowsLMC}, 2},
+		{"to-city", gosln.LinkMatchCond{toCityLMC}, 1},
+		{"from-alice", gosln.LinkMatchCond{fromAliceLMC}, 1},
+	}
+	for _, tc := range linkCases {
+		if n, err := sln.NumLink(ctx, tc.cond); err != nil {
+			t.Errorf("cond=%s, count links - %v", tc.name, err)
+		} else if n != tc.want {
+			t.Errorf("cond=%s, got %d links; want %d", tc.name, n, tc.want)
+		}
+	}
+	if links, err := sln.GetAllLinks(ctx, nil, gosln.LinkMatchCond{toCityLMC}); err != nil {
+		t.Error("get links to city -", err)
+	} else if len(links) != 1 || links[0].ID != ap.ID {
+		t.Errorf("got links to city %v; want only %v", links, ap.ID)
+	}
+
+	if links, err := sln.GetLinksByNode(ctx, alice.ID, gosln.DirOutgoing, nil, gosln.LinkMatchCond{knowsLMC}); err != nil {
+		t.Error("get outgoing knows links of Alice -", err)
+	} else if len(links) != 1 || links[0].ID != ab.ID {
+		t.Errorf("got outgoing knows links of Alice %v; want only %v", links, ab.ID)
+	}
+	if links, err := sln.GetLinksByNode(ctx, bob.ID, gosln.DirBoth, nil, nil); err != nil {
+		t.Error("get links of Bob -", err)
+	} else if len(links) != 2 {
+		t.Errorf("got %d links of Bob; want 2", len(links))
+	}
+
+	if err := sln.SoftRemoveNodeByID(ctx, bob.ID); err != nil {
+		t.Fatal("soft-remove node -", err)
+	}
+	personCond := newNodeCond(person, nil)
+	if n, err := sln.NumNode(ctx, personCond); err != nil {
+		t.Error("count persons -", err)
+	} else if n != 2 {
+		t.Errorf("got %d persons after soft-removing one; want 2", n)
+	}
+	personCond[0].SetIncludeDeleted(true)
+	if nodes, err := sln.GetAllNodes(ctx, nil, personCond); err != nil {
+		t.Error("get persons including deleted -", err)
+	} else if len(nodes) != 3 {
+		t.Errorf("got %d persons including deleted; want 3", len(nodes))
+	} else {
+		for _, node := range nodes {
+			if node.Deleted != (node.ID == bob.ID) {
+				t.Errorf("node %v, got Deleted %t; want %t", node.ID, node.Deleted, node.ID == bob.ID)
+			}
+		}
+	}
+	if _, err := sln.GetLinkByID(ctx, ab.ID, nil); err != nil {
+		t.Error("get link to soft-removed node -", err)
+	}
+}
+
+// sameIDs reports whether a and b consist of the same IDs,
+// regardless of their order.
+func sameIDs(a, b []gosln.ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := gosln.NewIDSet()
+	for _, id := range a {
+		set.Add(id)
+	}
+	if set.Len() != len(a) {
+		return false
+	}
+	for _, id := range b {
+		if !set.ContainsItem(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// testPropTypeCoercion tests whether sln converts the properties
+// to the types specified by propTypes when reading them.
+//
+// sln should be empty.
+func testPropTypeCoercion(t *testing.T, sln gosln.SLN) {
+	ctx := context.Background()
+	node := mustCreateNode(t, sln, person, newProps(
+		ageProp, 7,
+		ratioProp, 2.5,
+		bytesProp, []byte("x"),
+		stringProp, "str",
+	))
+	testCases := []struct {
+		name  gosln.PropName
+		pt    gosln.PropType
+		want  any
+		wantE bool
+	}{
+		{ageProp, gosln.PTInt64, int64(7), false},
+		{ageProp, gosln.PTFloat64, float64(7), false},
+		{ageProp, gosln.PTInt8, int8(7), false},
+		{ratioProp, gosln.PTFloat32, float32(2.5), false},
+		{bytesProp, gosln.PTString, "x", false},
+		{stringProp, gosln.PTBytes, []byte("str"), false},
+		{ageProp, gosln.PTString, nil, true},
+		{stringProp, gosln.PTInt64, nil, true},
+	}
+	for _, tc := range testCases {
+		ptm := gosln.NewPropTypeMap(1)
+		ptm.Set(tc.name, tc.pt)
+		got, err := sln.GetNodeByID(ctx, node.ID, ptm)
+		if tc.wantE {
+			var pte *gosln.PropTypeError
+			if !errors.As(err, &pte) {
+				t.Errorf("name=%v, type=%v, got error %v; want *PropTypeError", tc.name, tc.pt, err)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("name=%v, type=%v, get node - %v", tc.name, tc.pt, err)
+			continue
+		}
+		want := gosln.NewPropMap(1)
+		want.Set(tc.name, tc.want)
+		if !gosln.EqualPropMaps(got.Props, want) {
+			t.Errorf("name=%v, type=%v, got properties %v; want %v", tc.name, tc.pt, got.Props, want)
+		}
+	}
+
+	if got, err := sln.GetNodeByID(ctx, node.ID, nil); err != nil {
+		t.Error("get node with nil propTypes -", err)
+	} else if got.Props.Len() != 0 {
+		t.Errorf("got properties %v with nil propTypes; want empty", got.Props)
+	}
+}
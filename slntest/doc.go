@@ -0,0 +1,28 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package slntest provides utilities for testing
+// the implementations of SLN.
+//
+// It checks the common behavior documented by the interfaces
+// gosln.ReadOnlySLN, gosln.SLN, and gosln.Tx,
+// so that every implementation can run the same tests.
+//
+// RunSLNConformance runs all the tests,
+// and TestSLNConformance runs only those on the handling of context.Context.
+package slntest
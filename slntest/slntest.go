@@ -0,0 +1,372 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package slntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+)
+
+// Types and property names used by the conformance tests.
+var (
+	person = gosln.MustNewType("Person")
+	knows  = gosln.MustNewType("Knows")
+
+	nameProp = gosln.MustNewPropName("name")
+)
+
+// mutator consists of the mutating operations
+// shared by gosln.SLN and gosln.Tx.
+type mutator interface {
+	CreateNode(ctx context.Context, t gosln.Type, props gosln.PropMap) (node *gosln.Node, err error)
+	UpsertNode(ctx context.Context, t gosln.Type, keyName gosln.PropName, props gosln.PropMap) (
+		node *gosln.Node, created bool, err error)
+	CreateLink(ctx context.Context, t gosln.Type, from, to gosln.ID, props gosln.PropMap) (
+		link *gosln.Link, err error)
+	RemoveNodeByID(ctx context.Context, id gosln.ID) error
+	RemoveNodesByCond(ctx context.Context, cond gosln.NodeMatchCond) (removed int, err error)
+	SoftRemoveNodeByID(ctx context.Context, id gosln.ID) error
+	RestoreNodeByID(ctx context.Context, id gosln.ID) error
+	RemoveLinkByID(ctx context.Context, id gosln.ID) error
+	RemoveLinksByCond(ctx context.Context, cond gosln.LinkMatchCond) (removed int, err error)
+	SetNodeProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (node *gosln.Node, err error)
+	SetLinkProperties(ctx context.Context, id gosln.ID, props gosln.PropMap) (link *gosln.Link, err error)
+	MutateNodeProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+		node *gosln.Node, err error)
+	MutateLinkProperties(ctx context.Context, id gosln.ID, pma gosln.PropMutateArg) (
+		link *gosln.Link, err error)
+}
+
+var (
+	_ mutator = gosln.SLN(nil)
+	_ mutator = gosln.Tx(nil)
+)
+
+// TestSLNConformance tests whether the SLNs created by newSLN
+// follow the documentation of gosln.SLN
+// on the handling of context.Context.
+//
+// newSLN should return a new empty SLN each time it is called.
+// TestSLNConformance closes the SLNs after testing.
+//
+// It calls each operation of the SLN, its snapshot, and its transaction
+// with a canceled context and a context whose deadline is exceeded,
+// and checks that the operation reports an error wrapping
+// context.Canceled or context.DeadlineExceeded, respectively,
+// and takes no effect.
+//
+// RunSLNConformance also runs these tests.
+func TestSLNConformance(t *testing.T, newSLN func() gosln.SLN) {
+	t.Helper()
+	for _, x := range []struct {
+		name string
+		f    func(t *testing.T, sln gosln.SLN)
+	}{
+		{"Canceled", testCanceled},
+		{"DeadlineExceeded", testDeadlineExceeded},
+	} {
+		t.Run(x.name, func(t *testing.T) {
+			sln := newSLN()
+			t.Cleanup(func() {
+				_ = sln.Close()
+			})
+			x.f(t, sln)
+		})
+	}
+}
+
+// testCanceled calls testDoneContext with a canceled context.
+func testCanceled(t *testing.T, sln gosln.SLN) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testDoneContext(t, sln, ctx, context.Canceled)
+}
+
+// testDeadlineExceeded calls testDoneContext
+// with a context whose deadline is exceeded.
+func testDeadlineExceeded(t *testing.T, sln gosln.SLN) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+	testDoneContext(t, sln, ctx, context.DeadlineExceeded)
+}
+
+// ctxCase is an operation called by testDoneContext.
+type ctxCase struct {
+	name string
+	call func(ctx context.Context) error
+}
+
+// testDoneContext calls the operations of sln, its snapshot,
+// and its transaction with ctx, which is done,
+// and checks whether they report an error wrapping target
+// and take no effect.
+//
+// sln should be empty.
+func testDoneContext(t *testing.T, sln gosln.SLN, ctx context.Context, target error) {
+	bg := context.Background()
+	props := gosln.NewPropMap(1)
+	props.Set(nameProp, "Alice")
+	node, err := sln.CreateNode(bg, person, props)
+	if err != nil {
+		t.Fatal("create node -", err)
+	}
+	link, err := sln.CreateLink(bg, knows, node.ID, node.ID, nil)
+	if err != nil {
+		t.Fatal("create link -", err)
+	}
+	snap, err := sln.Snapshot(bg)
+	if err != nil {
+		t.Fatal("take snapshot -", err)
+	}
+	t.Cleanup(func() {
+		_ = snap.Close()
+	})
+	tx, err := sln.BeginTx(bg)
+	if err != nil {
+		t.Fatal("begin transaction -", err)
+	}
+
+	pma := gosln.NewPropMutateArg(1, 0)
+	pma.ToBeSet().Set(nameProp, "Bob")
+	cases := readCases("", sln, node.ID, link.ID)
+	cases = append(cases, readCases("Snapshot.", snap, node.ID, link.ID)...)
+	cases = append(cases, mutatorCases("", sln, node.ID, link.ID, pma)...)
+	cases = append(cases, mutatorCases("Tx.", tx, node.ID, link.ID, pma)...)
+	cases = append(cases,
+		ctxCase{"Snapshot", func(ctx context.Context) error {
+			_, err := sln.Snapshot(ctx)
+			return err
+		}},
+		ctxCase{"BeginTx", func(ctx context.Context) error {
+			_, err := sln.BeginTx(ctx)
+			return err
+		}},
+		ctxCase{"RegisterLinkConstraint", func(ctx context.Context) error {
+			return sln.RegisterLinkConstraint(ctx, knows, nil, nil)
+		}},
+		ctxCase{"RenameType", func(ctx context.Context) error {
+			_, err := sln.RenameType(ctx, person, gosln.MustNewType("Human"))
+			return err
+		}},
+		ctxCase{"UpdateNode", func(ctx context.Context) error {
+			_, err := sln.UpdateNode(ctx, node.ID, nil,
+				func(gosln.PropMap) (gosln.PropMutateArg, error) {
+					return pma, nil
+				})
+			return err
+		}},
+	)
+	for _, c := range cases {
+		if err := c.call(ctx); !errors.Is(err, target) {
+			t.Errorf("%s - got error %v; want %v", c.name, err, target)
+		}
+	}
+
+	// The operations in the transaction also take no effect on commit.
+	if err = tx.Commit(); err != nil {
+		t.Fatal("commit transaction -", err)
+	}
+	nameTypes := gosln.NewPropTypeMap(1)
+	nameTypes.Set(nameProp, gosln.PTString)
+	nodes, err := sln.GetAllNodes(bg, nameTypes, nil)
+	if err != nil {
+		t.Fatal("get all nodes -", err)
+	} else if len(nodes) != 1 || !nodes[0].Equal(node) {
+		t.Errorf("got nodes %v; want [%v]", nodes, node)
+	}
+	links, err := sln.GetAllLinks(bg, nil, nil)
+	if err != nil {
+		t.Fatal("get all links -", err)
+	} else if len(links) != 1 || !links[0].Equal(link) {
+		t.Errorf("got links %v; want [%v]", links, link)
+	}
+}
+
+// readCases returns the cases calling the read operations of r.
+//
+// The names of the cases are prefixed with prefix.
+func readCases(prefix string, r gosln.ReadOnlySLN, nodeID, linkID gosln.ID) []ctxCase {
+	types := gosln.NewTypeSet(1)
+	types.Add(knows)
+	nodeCond := gosln.NodeMatchCond{gosln.NewNodeMatchClause()}
+	linkCond := gosln.LinkMatchCond{gosln.NewLinkMatchClause()}
+	var opts gosln.NeighborhoodOptions
+	cases := []ctxCase{
+		{"NumNodeType", func(ctx context.Context) error {
+			_, err := r.NumNodeType(ctx)
+			return err
+		}},
+		{"NumLinkType", func(ctx context.Context) error {
+			_, err := r.NumLinkType(ctx)
+			return err
+		}},
+		{"NumNode", func(ctx context.Context) error {
+			_, err := r.NumNode(ctx, nodeCond)
+			return err
+		}},
+		{"ExistsAtLeast", func(ctx context.Context) error {
+			_, err := r.ExistsAtLeast(ctx, nodeCond, 1)
+			return err
+		}},
+		{"NumLink", func(ctx context.Context) error {
+			_, err := r.NumLink(ctx, linkCond)
+			return err
+		}},
+		{"GetNodeTypes", func(ctx context.Context) error {
+			_, err := r.GetNodeTypes(ctx)
+			return err
+		}},
+		{"GetLinkTypes", func(ctx context.Context) error {
+			_, err := r.GetLinkTypes(ctx)
+			return err
+		}},
+		{"InferSchema", func(ctx context.Context) error {
+			_, err := r.InferSchema(ctx)
+			return err
+		}},
+		{"GetCommonPropertyNames", func(ctx context.Context) error {
+			_, err := r.GetCommonPropertyNames(ctx, person)
+			return err
+		}},
+		{"GetNodeByID", func(ctx context.Context) error {
+			_, err := r.GetNodeByID(ctx, nodeID, nil)
+			return err
+		}},
+		{"GetNodesByIDs", func(ctx context.Context) error {
+			_, err := r.GetNodesByIDs(ctx, []gosln.ID{nodeID}, nil)
+			return err
+		}},
+		{"GetLinkByID", func(ctx context.Context) error {
+			_, err := r.GetLinkByID(ctx, linkID, nil)
+			return err
+		}},
+		{"GetAllNodes", func(ctx context.Context) error {
+			_, err := r.GetAllNodes(ctx, nil, nodeCond)
+			return err
+		}},
+		{"GetAllLinks", func(ctx context.Context) error {
+			_, err := r.GetAllLinks(ctx, nil, linkCond)
+			return err
+		}},
+		{"GetNodesPage", func(ctx context.Context) error {
+			_, _, err := r.GetNodesPage(ctx, nil, nodeCond, gosln.Page{Limit: 1})
+			return err
+		}},
+		{"GetLinksPage", func(ctx context.Context) error {
+			_, _, err := r.GetLinksPage(ctx, nil, linkCond, gosln.Page{Limit: 1})
+			return err
+		}},
+		{"GetNeighborhood", func(ctx context.Context) error {
+			_, err := r.GetNeighborhood(ctx, nodeID, opts)
+			return err
+		}},
+		{"GetLinksByNode", func(ctx context.Context) error {
+			_, err := r.GetLinksByNode(ctx, nodeID, gosln.DirBoth, nil, linkCond)
+			return err
+		}},
+		{"GetNodeWithGroupedNeighbors", func(ctx context.Context) error {
+			_, err := r.GetNodeWithGroupedNeighbors(ctx, nodeID, types, opts)
+			return err
+		}},
+		{"DistinctPropValues", func(ctx context.Context) error {
+			_, err := r.DistinctPropValues(ctx, person, nameProp, gosln.PTString)
+			return err
+		}},
+		{"DegreeDistribution", func(ctx context.Context) error {
+			_, err := r.DegreeDistribution(ctx, gosln.DirBoth, linkCond)
+			return err
+		}},
+		{"AggregateNumeric", func(ctx context.Context) error {
+			_, _, _, _, err := r.AggregateNumeric(ctx, person, nameProp)
+			return err
+		}},
+	}
+	for i := range cases {
+		cases[i].name = prefix + cases[i].name
+	}
+	return cases
+}
+
+// mutatorCases returns the cases calling the mutating operations of m.
+//
+// The names of the cases are prefixed with prefix.
+func mutatorCases(prefix string, m mutator, nodeID, linkID gosln.ID, pma gosln.PropMutateArg) []ctxCase {
+	props := gosln.NewPropMap(1)
+	props.Set(nameProp, "Bob")
+	nodeCond := gosln.NodeMatchCond{gosln.NewNodeMatchClause()}
+	linkCond := gosln.LinkMatchCond{gosln.NewLinkMatchClause()}
+	cases := []ctxCase{
+		{"CreateNode", func(ctx context.Context) error {
+			_, err := m.CreateNode(ctx, person, props)
+			return err
+		}},
+		{"UpsertNode", func(ctx context.Context) error {
+			_, _, err := m.UpsertNode(ctx, person, nameProp, props)
+			return err
+		}},
+		{"CreateLink", func(ctx context.Context) error {
+			_, err := m.CreateLink(ctx, knows, nodeID, nodeID, props)
+			return err
+		}},
+		{"RemoveNodeByID", func(ctx context.Context) error {
+			return m.RemoveNodeByID(ctx, nodeID)
+		}},
+		{"RemoveNodesByCond", func(ctx context.Context) error {
+			_, err := m.RemoveNodesByCond(ctx, nodeCond)
+			return err
+		}},
+		{"SoftRemoveNodeByID", func(ctx context.Context) error {
+			return m.SoftRemoveNodeByID(ctx, nodeID)
+		}},
+		{"RestoreNodeByID", func(ctx context.Context) error {
+			return m.RestoreNodeByID(ctx, nodeID)
+		}},
+		{"RemoveLinkByID", func(ctx context.Context) error {
+			return m.RemoveLinkByID(ctx, linkID)
+		}},
+		{"RemoveLinksByCond", func(ctx context.Context) error {
+			_, err := m.RemoveLinksByCond(ctx, linkCond)
+			return err
+		}},
+		{"SetNodeProperties", func(ctx context.Context) error {
+			_, err := m.SetNodeProperties(ctx, nodeID, props)
+			return err
+		}},
+		{"SetLinkProperties", func(ctx context.Context) error {
+			_, err := m.SetLinkProperties(ctx, linkID, props)
+			return err
+		}},
+		{"MutateNodeProperties", func(ctx context.Context) error {
+			_, err := m.MutateNodeProperties(ctx, nodeID, pma)
+			return err
+		}},
+		{"MutateLinkProperties", func(ctx context.Context) error {
+			_, err := m.MutateLinkProperties(ctx, linkID, pma)
+			return err
+		}},
+	}
+	for i := range cases {
+		cases[i].name = prefix + cases[i].name
+	}
+	return cases
+}
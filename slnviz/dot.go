@@ -0,0 +1,137 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package slnviz
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/donyori/gogo/container/mapping"
+	"github.com/donyori/gogo/errors"
+
+	"github.com/donyori/gosln"
+)
+
+// WriteDOT writes the specified nodes and links to w
+// as a directed graph in the DOT language of Graphviz
+// (https://graphviz.org/doc/info/lang.html).
+//
+// The DOT node IDs are the node IDs.
+// Each node is labeled by its type,
+// followed by its properties sorted by name, one per line,
+// in the form "<name>: <value>", where the value is rendered
+// via function gosln.FormatPropValue.
+// Each link is written as an edge labeled by its type.
+// The nodes and links are written in the order in which they are given.
+// The endpoints of the links need not be in nodes,
+// in which case Graphviz draws them with their IDs only.
+//
+// The quotation marks, backslashes, and line breaks
+// in the IDs, types, and properties are escaped.
+//
+// The nil nodes and links are skipped.
+// WriteDOT reports an error if w is nil
+// or any link has a nil From or To node.
+func WriteDOT(w io.Writer, nodes []*gosln.Node, links []*gosln.Link) error {
+	if w == nil {
+		return errors.AutoNew("writer is nil")
+	}
+	for _, link := range links {
+		if link != nil && (link.From == nil || link.To == nil) {
+			return errors.AutoNew("link " + link.ID.String() + " has a nil endpoint")
+		}
+	}
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString("digraph SLN {\n")
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		_, _ = bw.WriteString("\t")
+		writeDOTString(bw, node.ID.String())
+		_, _ = bw.WriteString(" [label=")
+		writeDOTString(bw, nodeLabel(node))
+		_, _ = bw.WriteString("];\n")
+	}
+	for _, link := range links {
+		if link == nil {
+			continue
+		}
+		_, _ = bw.WriteString("\t")
+		writeDOTString(bw, link.From.ID.String())
+		_, _ = bw.WriteString(" -> ")
+		writeDOTString(bw, link.To.ID.String())
+		_, _ = bw.WriteString(" [label=")
+		writeDOTString(bw, link.Type.String())
+		_, _ = bw.WriteString("];\n")
+	}
+	_, _ = bw.WriteString("}\n")
+	// The errors of bufio.Writer are sticky,
+	// so checking the error of Flush is sufficient.
+	return errors.AutoWrap(bw.Flush())
+}
+
+// nodeLabel returns the label of the node in DOT, before escaping.
+func nodeLabel(node *gosln.Node) string {
+	var b strings.Builder
+	b.WriteString(node.Type.String())
+	if node.Props == nil || node.Props.Len() == 0 {
+		return b.String()
+	}
+	entries := make([]mapping.Entry[gosln.PropName, any], 0, node.Props.Len())
+	node.Props.Range(func(x mapping.Entry[gosln.PropName, any]) (cont bool) {
+		entries = append(entries, x)
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key.Compare(entries[j].Key) < 0
+	})
+	for _, x := range entries {
+		b.WriteByte('\n')
+		b.WriteString(x.Key.String())
+		b.WriteString(": ")
+		b.WriteString(gosln.FormatPropValue(x.Value))
+	}
+	return b.String()
+}
+
+// writeDOTString writes s to w as a quoted string in DOT.
+//
+// It escapes the quotation marks and backslashes,
+// writes the line feeds as "\n",
+// which Graphviz renders as centered line breaks in labels,
+// and drops the carriage returns.
+func writeDOTString(w *bufio.Writer, s string) {
+	_ = w.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			_ = w.WriteByte('\\')
+			_ = w.WriteByte(c)
+		case '\n':
+			_, _ = w.WriteString(`\n`)
+		case '\r':
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+	_ = w.WriteByte('"')
+}
@@ -0,0 +1,70 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package slnviz_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donyori/gosln"
+	"github.com/donyori/gosln/slnviz"
+)
+
+func TestWriteDOT(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	person := gosln.MustNewType("Person")
+	knows := gosln.MustNewType("Knows")
+	newNode := func(i int64, props map[string]any) *gosln.Node {
+		pm, err := gosln.PropMapFromGoMap(props)
+		if err != nil {
+			t.Fatal("create property map -", err)
+		}
+		return &gosln.Node{NL: gosln.NL{ID: gosln.NewID(person, date, i), Type: person, Props: pm}}
+	}
+	a := newNode(1, map[string]any{"name": `Alice "A\B"`, "age": 30})
+	b := newNode(2, map[string]any{"note": "line1\nline2"})
+	link := &gosln.Link{
+		NL:   gosln.NL{ID: gosln.NewID(knows, date, 3), Type: knows},
+		From: a,
+		To:   b,
+	}
+
+	var sb strings.Builder
+	err := slnviz.WriteDOT(&sb, []*gosln.Node{a, nil, b}, []*gosln.Link{link, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "digraph SLN {\n" +
+		"\t\"" + a.ID.String() + "\" [label=\"Person\\nage: 30\\nname: Alice \\\"A\\\\B\\\"\"];\n" +
+		"\t\"" + b.ID.String() + "\" [label=\"Person\\nnote: line1\\nline2\"];\n" +
+		"\t\"" + a.ID.String() + "\" -> \"" + b.ID.String() + "\" [label=\"Knows\"];\n" +
+		"}\n"
+	if got := sb.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	link.To = nil
+	if err = slnviz.WriteDOT(&sb, nil, []*gosln.Link{link}); err == nil {
+		t.Error("link with nil endpoint - got nil error")
+	}
+	if err = slnviz.WriteDOT(nil, nil, nil); err == nil {
+		t.Error("nil writer - got nil error")
+	}
+}
@@ -0,0 +1,110 @@
+// gosln.  An implementation of Semantic Link Network (SLN) in Go (Golang).
+// Copyright (C) 2023  Yuan Gao
+//
+// This file is part of gosln.
+//
+// gosln is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published
+// by the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gosln
+
+import (
+	"context"
+
+	"github.com/donyori/gogo/errors"
+)
+
+// ErrTxDone is an error indicating that the transaction
+// has already been committed or rolled back.
+//
+// The client should use errors.Is to test whether an error is ErrTxDone.
+var ErrTxDone = errors.AutoNewCustom(
+	"transaction has already been committed or rolled back",
+	errors.PrependFullPkgName,
+	0,
+)
+
+// Tx is a transaction on the Semantic Link Network,
+// obtained by the method BeginTx of SLN.
+//
+// It contains the mutating operations of SLN.
+// The operations in a transaction take effect atomically on Commit,
+// and are discarded on Rollback.
+// Until then, they are invisible to the SLN.
+//
+// After Commit or Rollback, the operations
+// (including Commit and Rollback) report ErrTxDone.
+// (To test whether an error is ErrTxDone, use function errors.Is.)
+//
+// The field SLN of the nodes and links returned by the operations
+// is the SLN that begins the transaction.
+type Tx interface {
+	// Commit applies the operations in the transaction to the SLN.
+	Commit() error
+
+	// Rollback discards the operations in the transaction.
+	Rollback() error
+
+	// CreateNode is like the method CreateNode of SLN,
+	// but takes effect on Commit.
+	CreateNode(ctx context.Context, t Type, props PropMap) (node *Node, err error)
+
+	// UpsertNode is like the method UpsertNode of SLN,
+	// but takes effect on Commit.
+	UpsertNode(ctx context.Context, t Type, keyName PropName, props PropMap) (
+		node *Node, created bool, err error)
+
+	// CreateLink is like the method CreateLink of SLN,
+	// but takes effect on Commit.
+	CreateLink(ctx context.Context, t Type, from, to ID, props PropMap) (link *Link, err error)
+
+	// RemoveNodeByID is like the method RemoveNodeByID of SLN,
+	// but takes effect on Commit.
+	RemoveNodeByID(ctx context.Context, id ID) error
+
+	// RemoveNodesByCond is like the method RemoveNodesByCond of SLN,
+	// but takes effect on Commit.
+	RemoveNodesByCond(ctx context.Context, cond NodeMatchCond) (removed int, err error)
+
+	// SoftRemoveNodeByID is like the method SoftRemoveNodeByID of SLN,
+	// but takes effect on Commit.
+	SoftRemoveNodeByID(ctx context.Context, id ID) error
+
+	// RestoreNodeByID is like the method RestoreNodeByID of SLN,
+	// but takes effect on Commit.
+	RestoreNodeByID(ctx context.Context, id ID) error
+
+	// RemoveLinkByID is like the method RemoveLinkByID of SLN,
+	// but takes effect on Commit.
+	RemoveLinkByID(ctx context.Context, id ID) error
+
+	// RemoveLinksByCond is like the method RemoveLinksByCond of SLN,
+	// but takes effect on Commit.
+	RemoveLinksByCond(ctx context.Context, cond LinkMatchCond) (removed int, err error)
+
+	// SetNodeProperties is like the method SetNodeProperties of SLN,
+	// but takes effect on Commit.
+	SetNodeProperties(ctx context.Context, id ID, props PropMap) (node *Node, err error)
+
+	// SetLinkProperties is like the method SetLinkProperties of SLN,
+	// but takes effect on Commit.
+	SetLinkProperties(ctx context.Context, id ID, props PropMap) (link *Link, err error)
+
+	// MutateNodeProperties is like the method MutateNodeProperties of SLN,
+	// but takes effect on Commit.
+	MutateNodeProperties(ctx context.Context, id ID, pma PropMutateArg) (node *Node, err error)
+
+	// MutateLinkProperties is like the method MutateLinkProperties of SLN,
+	// but takes effect on Commit.
+	MutateLinkProperties(ctx context.Context, id ID, pma PropMutateArg) (link *Link, err error)
+}
@@ -20,6 +20,9 @@ package gosln
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/donyori/gogo/container"
@@ -100,6 +103,48 @@ func (t Type) IsValid() bool {
 	return t.t != ""
 }
 
+// Compare returns an integer comparing t and other
+// by their string values lexicographically.
+// The result is 0 if t == other, -1 if t < other, and +1 if t > other.
+//
+// In particular, an invalid type is less than any valid type.
+func (t Type) Compare(other Type) int {
+	return strings.Compare(t.t, other.t)
+}
+
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes the type as a JSON string of its value.
+// In particular, an invalid type is encoded as "".
+func (t Type) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.t)), nil
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON string of a valid type.
+// In particular, null and "" are decoded as the zero-value Type
+// (i.e., unspecified).
+//
+// It reports an error if data is not a JSON string or null.
+// It reports a *InvalidTypeError if the type is invalid.
+// (To test whether the error is *InvalidTypeError, use function errors.As.)
+func (t *Type) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONString(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	} else if s == "" {
+		*t = Type{}
+		return nil
+	}
+	typ, err := NewType(s)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	*t = typ
+	return nil
+}
+
 // ID is the unique identifier of the semantic node and link.
 //
 // A valid ID is the concatenation of its corresponding type,
@@ -158,6 +203,65 @@ func (id ID) String() string {
 	return id.t + "#" + id.s
 }
 
+// ParseID parses an ID from the string produced by its method String.
+//
+// s must consist of a valid type, a number sign ('#'),
+// and a suffix in the form generated by function NewID.
+//
+// ParseID reports a *InvalidTypeError if the type is invalid.
+// (To test whether err is *InvalidTypeError, use function errors.As.)
+// It reports an error if s is not in the above form.
+func ParseID(s string) (id ID, err error) {
+	i := strings.IndexByte(s, '#')
+	if i < 0 {
+		return ID{}, errors.AutoNew("ID " + strconv.Quote(s) +
+			` is malformed; want the form "<Type>#<UniqueSuffix>"`)
+	}
+	t, suffix := s[:i], s[i+1:]
+	if !IsValidTypeString(t) {
+		return ID{}, errors.AutoWrap(NewInvalidTypeError(t))
+	}
+	_, _, ok := decodeIDSuffix(suffix)
+	if !ok {
+		return ID{}, errors.AutoNew("ID " + strconv.Quote(s) +
+			" is malformed; its suffix is not in the form generated by NewID")
+	}
+	return ID{t: t, s: suffix}, nil
+}
+
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes id as a JSON string in the form produced by its method String.
+// In particular, an invalid ID is encoded as "".
+func (id ID) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(id.String())), nil
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON string in the form produced by the method String of ID,
+// and parses it with function ParseID.
+// In particular, null and "" are decoded as the zero-value ID
+// (i.e., unspecified).
+//
+// It reports an error if data is not a JSON string or null,
+// or the string is not a valid ID.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	s, err := unmarshalJSONString(data)
+	if err != nil {
+		return errors.AutoWrap(err)
+	} else if s == "" {
+		*id = ID{}
+		return nil
+	}
+	x, err := ParseID(s)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	*id = x
+	return nil
+}
+
 // IsValid reports whether id is valid.
 func (id ID) IsValid() bool {
 	// Its constructor should guarantee that id is valid if it is not zero.
@@ -172,6 +276,164 @@ func (id ID) Type() Type {
 	return MustNewType(id.t)
 }
 
+// Compare compares this ID (denoted by x) and the specified ID (denoted by y).
+//
+// The IDs are ordered first by their types (in ascending order of
+// the type strings), then by the dates decoded from them,
+// and then by the serial numbers decoded from them,
+// so that the order is chronological within a type.
+// The invalid IDs precede the valid ones.
+// For the IDs of the same type, those whose suffixes are
+// not in the form generated by function NewID follow the others
+// and are ordered by their suffixes lexicographically.
+//
+// It returns -1 if x precedes y,
+// +1 if x follows y, and 0 if x and y are the same.
+func (id ID) Compare(other ID) int {
+	if r := strings.Compare(id.t, other.t); r != 0 || id.s == other.s {
+		return r
+	}
+	xDate, xSerial, xOK := decodeIDSuffix(id.s)
+	yDate, ySerial, yOK := decodeIDSuffix(other.s)
+	switch {
+	case xOK && yOK:
+		if r := xDate.Compare(yDate); r != 0 {
+			return r
+		} else if xSerial < ySerial {
+			return -1
+		} else if xSerial > ySerial {
+			return 1
+		}
+		return 0
+	case xOK:
+		return -1
+	case yOK:
+		return 1
+	}
+	return strings.Compare(id.s, other.s)
+}
+
+// SortIDs sorts ids in ascending order determined by the method Compare of ID.
+func SortIDs(ids []ID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Compare(ids[j]) < 0
+	})
+}
+
+// IDComponents consists of the components of an ID
+// generated by function NewID.
+type IDComponents struct {
+	Type   Type  // The type corresponding to the ID.
+	Date   Date  // The date specified when generating the ID.
+	Serial int64 // The serial number specified when generating the ID.
+}
+
+// Components decodes the type, date, and serial number
+// from id, and returns them as IDComponents.
+//
+// It returns ok = false if id is invalid
+// or its suffix is not in the form generated by function NewID.
+func (id ID) Components() (c IDComponents, ok bool) {
+	if id.t == "" {
+		return
+	}
+	date, serial, ok := decodeIDSuffix(id.s)
+	if !ok {
+		return
+	}
+	return IDComponents{
+		Type:   MustNewType(id.t),
+		Date:   date,
+		Serial: serial,
+	}, true
+}
+
+// Date decodes the date specified when generating id by function NewID.
+//
+// It returns ok = false if id is invalid
+// or its suffix is not in the form generated by function NewID.
+func (id ID) Date() (date Date, ok bool) {
+	if id.t == "" {
+		return
+	}
+	date, _, ok = decodeIDSuffix(id.s)
+	if !ok {
+		return Date{}, false
+	}
+	return
+}
+
+// Serial decodes the serial number specified when
+// generating id by function NewID.
+//
+// It returns ok = false if id is invalid
+// or its suffix is not in the form generated by function NewID.
+func (id ID) Serial() (serial int64, ok bool) {
+	if id.t == "" {
+		return
+	}
+	_, serial, ok = decodeIDSuffix(id.s)
+	if !ok {
+		return 0, false
+	}
+	return
+}
+
+// decodeIDSuffix decodes the date and serial number
+// from the suffix of ID generated by function NewID.
+//
+// It reports whether the suffix is in the form generated by NewID.
+func decodeIDSuffix(suffix string) (date Date, serial int64, ok bool) {
+	// The suffix is <Date> "-" <Serial>,
+	// where <Date> is <Year> "-" <YearDay> with a possibly negative <Year>
+	// and a 3-digit <YearDay>,
+	// and <Serial> may contain '-'.
+	i := 0
+	if i < len(suffix) && suffix[i] == '-' {
+		i++
+	}
+	for i < len(suffix) && suffix[i] >= '0' && suffix[i] <= '9' {
+		i++
+	}
+	i += 4 // skip "-" and <YearDay>
+	if i >= len(suffix)-1 || suffix[i] != '-' {
+		return
+	}
+	date, ok = parseDateString(suffix[:i])
+	if !ok {
+		return
+	}
+	serial, ok = decodeSerial(suffix[i+1:])
+	return
+}
+
+// decodeSerial decodes the serial number
+// encoded by function NewID with encode64Table.
+//
+// It reports whether s is a valid encoded serial number
+// that does not overflow int64.
+func decodeSerial(s string) (i int64, ok bool) {
+	if s == "" {
+		return
+	}
+	for k := len(s) - 1; k >= 0; k-- {
+		d := int64(strings.IndexByte(encode64Table, s[k]))
+		if d < 0 {
+			return 0, false
+		}
+		if k < len(s)-1 {
+			// i = d + 64 * (i + 1), checking for overflow.
+			if i > (math.MaxInt64-d)/64-1 {
+				return 0, false
+			}
+			i = d + 64*(i+1)
+		} else {
+			i = d
+		}
+	}
+	return i, true
+}
+
 // TypeSet is a set of node or link types, all of which are valid Type.
 //
 // If an invalid Type is about to be put into this set,
@@ -192,6 +454,10 @@ func (id ID) Type() Type {
 //	}
 type TypeSet interface {
 	set.Set[Type]
+
+	// ToSortedSlice returns a new slice of the types in the set,
+	// sorted in ascending order of their string values.
+	ToSortedSlice() []Type
 }
 
 // NewTypeSet creates a new TypeSet.
@@ -211,9 +477,29 @@ func NewTypeSet(capacity int) TypeSet {
 		func(x Type) error {
 			return NewInvalidTypeError(x.String())
 		},
+		nil,
 	)
 }
 
+// FilteredTypeSet returns a new TypeSet containing
+// the types in ts that satisfy keep.
+//
+// It does not modify ts.
+// If keep is nil, all types in ts are kept.
+// If ts is nil, it returns an empty TypeSet.
+func FilteredTypeSet(ts TypeSet, keep func(t Type) bool) TypeSet {
+	r := NewTypeSet(0)
+	if ts != nil {
+		ts.Range(func(x Type) (cont bool) {
+			if keep == nil || keep(x) {
+				r.Add(x)
+			}
+			return true
+		})
+	}
+	return r
+}
+
 // IDSet is a set of IDs, where the IDs are valid.
 //
 // If an invalid ID is about to be put into this set,
@@ -253,11 +539,125 @@ type IDSet interface {
 	// ContainsType reports whether there is an ID
 	// corresponding to the type t in the set.
 	ContainsType(t Type) bool
+
+	// RemoveType removes all IDs corresponding to the type t from the set,
+	// and returns the number of IDs removed.
+	//
+	// It is much faster than removing the IDs one by one.
+	RemoveType(t Type) int
+
+	// ToSlice returns a new slice of the IDs in the set.
+	//
+	// The order of the IDs in the slice is random.
+	ToSlice() []ID
+
+	// ToSortedSlice returns a new slice of the IDs in the set,
+	// sorted in ascending order determined by the method Compare of ID.
+	ToSortedSlice() []ID
+
+	// Equal reports whether the set and s contain exactly the same IDs.
+	//
+	// A nil s is treated as an empty set.
+	// In particular, two empty sets are equal.
+	Equal(s set.Set[ID]) bool
+
+	// Clone returns a copy of the set.
+	//
+	// The copy is independent of the set:
+	// modifying one does not affect the other.
+	Clone() IDSet
+}
+
+// FilteredIDSet returns a new IDSet containing
+// the IDs in ids that satisfy keep.
+//
+// It does not modify ids.
+// If keep is nil, all IDs in ids are kept.
+// If ids is nil, it returns an empty IDSet.
+func FilteredIDSet(ids IDSet, keep func(id ID) bool) IDSet {
+	if ids == nil {
+		return NewIDSet()
+	}
+	r := NewIDSetCap(ids.NumType())
+	ids.Range(func(x ID) (cont bool) {
+		if keep == nil || keep(x) {
+			r.Add(x)
+		}
+		return true
+	})
+	return r
+}
+
+// UnionIDSet returns a new IDSet containing
+// the IDs in a, b, or both.
+//
+// It does not modify a and b.
+// A nil set is treated as an empty set.
+//
+// UnionIDSet panics with a *InvalidIDError if any ID in a or b is invalid.
+func UnionIDSet(a, b set.Set[ID]) IDSet {
+	r := NewIDSet()
+	for _, s := range []set.Set[ID]{a, b} {
+		if s != nil {
+			s.Range(func(x ID) (cont bool) {
+				r.Add(x)
+				return true
+			})
+		}
+	}
+	return r
+}
+
+// IntersectIDSet returns a new IDSet containing
+// the IDs in both a and b.
+//
+// It does not modify a and b.
+// A nil set is treated as an empty set.
+//
+// IntersectIDSet panics with a *InvalidIDError
+// if any ID in both a and b is invalid.
+func IntersectIDSet(a, b set.Set[ID]) IDSet {
+	r := NewIDSet()
+	if a == nil || b == nil {
+		return r
+	} else if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	a.Range(func(x ID) (cont bool) {
+		if b.ContainsItem(x) {
+			r.Add(x)
+		}
+		return true
+	})
+	return r
+}
+
+// DifferenceIDSet returns a new IDSet containing
+// the IDs in a but not in b.
+//
+// It does not modify a and b.
+// A nil set is treated as an empty set.
+//
+// DifferenceIDSet panics with a *InvalidIDError
+// if any ID in a but not in b is invalid.
+func DifferenceIDSet(a, b set.Set[ID]) IDSet {
+	r := NewIDSet()
+	if a == nil {
+		return r
+	}
+	a.Range(func(x ID) (cont bool) {
+		if b == nil || !b.ContainsItem(x) {
+			r.Add(x)
+		}
+		return true
+	})
+	return r
 }
 
 // idSetImpl is an implementation of interface IDSet.
 type idSetImpl struct {
 	m map[string]map[string]struct{}
+	n int // The number of IDs in the set.
 }
 
 // NewIDSet creates a new IDSet.
@@ -268,12 +668,24 @@ func NewIDSet() IDSet {
 	return &idSetImpl{m: make(map[string]map[string]struct{})}
 }
 
-func (ids *idSetImpl) Len() int {
-	var n int
-	for _, sub := range ids.m {
-		n += len(sub)
+// NewIDSetCap creates a new IDSet with the specified type capacity.
+//
+// The method Range of the set accesses IDs in random order.
+// The access order in two calls to Range may be different.
+//
+// typeCapacity asks to allocate enough space to hold
+// the IDs of the specified number of types,
+// which avoids rehashing when adding the IDs of many types.
+// If typeCapacity is nonpositive, it is ignored.
+func NewIDSetCap(typeCapacity int) IDSet {
+	if typeCapacity < 0 {
+		typeCapacity = 0
 	}
-	return n
+	return &idSetImpl{m: make(map[string]map[string]struct{}, typeCapacity)}
+}
+
+func (ids *idSetImpl) Len() int {
+	return ids.n
 }
 
 // Range accesses the IDs in the set.
@@ -297,6 +709,7 @@ func (ids *idSetImpl) Filter(filter func(x ID) (keep bool)) {
 		for suffix := range sub {
 			if !filter(ID{t: t, s: suffix}) {
 				delete(sub, suffix)
+				ids.n--
 				if len(sub) == 0 {
 					delete(ids.m, t)
 				}
@@ -314,6 +727,9 @@ func (ids *idSetImpl) ContainsItem(x ID) bool {
 	return ok
 }
 
+// ContainsSet reports whether every ID in s is in the set.
+//
+// It returns true if s is nil or empty.
 func (ids *idSetImpl) ContainsSet(s set.Set[ID]) bool {
 	if s == nil {
 		return true
@@ -321,37 +737,32 @@ func (ids *idSetImpl) ContainsSet(s set.Set[ID]) bool {
 	n := s.Len()
 	if n == 0 {
 		return true
-	} else if n > len(ids.m) {
+	} else if n > ids.n {
 		return false
 	}
-	var ok bool
+	all := true
 	s.Range(func(x ID) (cont bool) {
-		sub := ids.m[x.t]
-		if sub != nil {
-			_, ok = sub[x.s]
-		} else {
-			ok = false
-		}
-		return ok
+		all = ids.ContainsItem(x)
+		return all
 	})
-	return ok
+	return all
 }
 
+// ContainsAny reports whether at least one ID in c is in the set.
+//
+// It returns false if c is nil or empty.
 func (ids *idSetImpl) ContainsAny(c container.Container[ID]) bool {
-	if c == nil || c.Len() == 0 {
+	if c == nil || c.Len() == 0 || ids.n == 0 {
 		return false
 	}
-	var ok bool
+	var found bool
 	c.Range(func(x ID) (cont bool) {
-		sub := ids.m[x.t]
-		if sub != nil {
-			_, ok = sub[x.s]
-		} else {
-			ok = false
+		if ids.ContainsItem(x) {
+			found = true
 		}
-		return !ok
+		return !found
 	})
-	return ok
+	return found
 }
 
 func (ids *idSetImpl) Add(id ...ID) {
@@ -361,24 +772,13 @@ func (ids *idSetImpl) Add(id ...ID) {
 		}
 	}
 	for _, x := range id {
-		sub := ids.m[x.t]
-		if sub == nil {
-			sub = make(map[string]struct{})
-			ids.m[x.t] = sub
-		}
-		sub[x.s] = struct{}{}
+		ids.add(x)
 	}
 }
 
 func (ids *idSetImpl) Remove(id ...ID) {
 	for _, x := range id {
-		sub := ids.m[x.t]
-		if sub != nil {
-			delete(sub, x.s)
-			if len(sub) == 0 {
-				delete(ids.m, x.t)
-			}
-		}
+		ids.remove(x)
 	}
 }
 
@@ -388,25 +788,21 @@ func (ids *idSetImpl) Union(s set.Set[ID]) {
 	}
 	ids.validateAllIDsInSet(s)
 	s.Range(func(x ID) (cont bool) {
-		sub := ids.m[x.t]
-		if sub == nil {
-			sub = make(map[string]struct{})
-			ids.m[x.t] = sub
-		}
-		sub[x.s] = struct{}{}
+		ids.add(x)
 		return true
 	})
 }
 
 func (ids *idSetImpl) Intersect(s set.Set[ID]) {
 	if s == nil || s.Len() == 0 {
-		ids.m = make(map[string]map[string]struct{})
+		ids.Clear()
 		return
 	}
 	for t, sub := range ids.m {
 		for suffix := range sub {
 			if !s.ContainsItem(ID{t: t, s: suffix}) {
 				delete(sub, suffix)
+				ids.n--
 				if len(sub) == 0 {
 					delete(ids.m, t)
 				}
@@ -420,13 +816,7 @@ func (ids *idSetImpl) Subtract(s set.Set[ID]) {
 		return
 	}
 	s.Range(func(x ID) (cont bool) {
-		sub := ids.m[x.t]
-		if sub != nil {
-			delete(sub, x.s)
-			if len(sub) == 0 {
-				delete(ids.m, x.t)
-			}
-		}
+		ids.remove(x)
 		return true
 	})
 }
@@ -437,18 +827,8 @@ func (ids *idSetImpl) DisjunctiveUnion(s set.Set[ID]) {
 	}
 	ids.validateAllIDsInSet(s)
 	s.Range(func(x ID) (cont bool) {
-		sub := ids.m[x.t]
-		if sub == nil {
-			sub = make(map[string]struct{})
-			ids.m[x.t] = sub
-		}
-		if _, ok := sub[x.s]; ok {
-			delete(sub, x.s)
-			if len(sub) == 0 {
-				delete(ids.m, x.t)
-			}
-		} else {
-			sub[x.s] = struct{}{}
+		if !ids.remove(x) {
+			ids.add(x)
 		}
 		return true
 	})
@@ -456,6 +836,7 @@ func (ids *idSetImpl) DisjunctiveUnion(s set.Set[ID]) {
 
 func (ids *idSetImpl) Clear() {
 	ids.m = make(map[string]map[string]struct{})
+	ids.n = 0
 }
 
 func (ids *idSetImpl) LenType(t Type) int {
@@ -478,6 +859,95 @@ func (ids *idSetImpl) ContainsType(t Type) bool {
 	return len(ids.m[t.t]) > 0
 }
 
+func (ids *idSetImpl) RemoveType(t Type) int {
+	n := len(ids.m[t.t])
+	if n > 0 {
+		delete(ids.m, t.t)
+		ids.n -= n
+	}
+	return n
+}
+
+func (ids *idSetImpl) ToSlice() []ID {
+	slice := make([]ID, 0, ids.n)
+	for t, sub := range ids.m {
+		for suffix := range sub {
+			slice = append(slice, ID{t: t, s: suffix})
+		}
+	}
+	return slice
+}
+
+func (ids *idSetImpl) ToSortedSlice() []ID {
+	slice := ids.ToSlice()
+	SortIDs(slice)
+	return slice
+}
+
+func (ids *idSetImpl) Equal(s set.Set[ID]) bool {
+	if s == nil {
+		return ids.n == 0
+	} else if s.Len() != ids.n {
+		return false
+	}
+	// As both sets have the same length,
+	// the set contains s if and only if s contains the set.
+	ok := true
+	s.Range(func(x ID) (cont bool) {
+		ok = ids.ContainsItem(x)
+		return ok
+	})
+	return ok
+}
+
+func (ids *idSetImpl) Clone() IDSet {
+	m := make(map[string]map[string]struct{}, len(ids.m))
+	for t, sub := range ids.m {
+		c := make(map[string]struct{}, len(sub))
+		for suffix := range sub {
+			c[suffix] = struct{}{}
+		}
+		m[t] = c
+	}
+	return &idSetImpl{m: m, n: ids.n}
+}
+
+// add puts x into the set.
+//
+// It reports whether x was absent before the call.
+//
+// The caller should guarantee that x is valid.
+func (ids *idSetImpl) add(x ID) bool {
+	sub := ids.m[x.t]
+	if sub == nil {
+		sub = make(map[string]struct{})
+		ids.m[x.t] = sub
+	} else if _, ok := sub[x.s]; ok {
+		return false
+	}
+	sub[x.s] = struct{}{}
+	ids.n++
+	return true
+}
+
+// remove deletes x from the set.
+//
+// It reports whether x was present before the call.
+func (ids *idSetImpl) remove(x ID) bool {
+	sub := ids.m[x.t]
+	if sub == nil {
+		return false
+	} else if _, ok := sub[x.s]; !ok {
+		return false
+	}
+	delete(sub, x.s)
+	ids.n--
+	if len(sub) == 0 {
+		delete(ids.m, x.t)
+	}
+	return true
+}
+
 // validateAllIDsInSet checks whether all IDs in s are valid.
 //
 // If any ID is invalid, it panics with a *InvalidIDError.
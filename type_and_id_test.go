@@ -19,11 +19,16 @@
 package gosln_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/donyori/gogo/container/set"
+
 	"github.com/donyori/gosln"
 )
 
@@ -71,6 +76,52 @@ func TestIsValidTypeString(t *testing.T) {
 	}
 }
 
+func TestType_Compare(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	testCases := []struct {
+		x, y gosln.Type
+		want int
+	}{
+		{typeA, typeA, 0},
+		{typeA, typeB, -1},
+		{typeB, typeA, 1},
+		{gosln.Type{}, typeA, -1},
+		{typeA, gosln.Type{}, 1},
+		{gosln.Type{}, gosln.Type{}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("x=%+q&y=%+q", tc.x, tc.y), func(t *testing.T) {
+			if got := tc.x.Compare(tc.y); got != tc.want {
+				t.Errorf("got %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTypeSet_ToSortedSlice(t *testing.T) {
+	want := []gosln.Type{
+		gosln.MustNewType("Event"),
+		gosln.MustNewType("Person"),
+		gosln.MustNewType("Place"),
+		gosln.MustNewType("Tag"),
+	}
+	s := gosln.NewTypeSet(len(want))
+	if slice := s.ToSortedSlice(); slice == nil || len(slice) != 0 {
+		t.Errorf("empty set - got %v; want an empty non-nil slice", slice)
+	}
+	s.Add(want[2], want[0], want[3], want[1])
+	got := s.ToSortedSlice()
+	if len(got) != len(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v; want %v", got, want)
+		}
+	}
+}
+
 func TestNewID(t *testing.T) {
 	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
 	typ1 := gosln.MustNewType("TestType_1")
@@ -123,6 +174,7 @@ func TestNewID(t *testing.T) {
 		{typ1, 266304, "TestType_1#2023-071-0000", false},
 		{typ2, 0, "TestType_2#2023-071-0", false},
 		{typ2, 1, "TestType_2#2023-071-1", false},
+		{typ2, math.MaxInt64, "TestType_2#2023-071-_---------6", false},
 		{gosln.Type{}, -1, "", true},
 		{typ1, -1, "", true},
 		{typ2, -1, "", true},
@@ -150,6 +202,524 @@ func TestNewID(t *testing.T) {
 			if typ := id.Type(); typ != tc.t {
 				t.Errorf("got Type %v; want %v", typ, tc.t)
 			}
+			c, ok := id.Components()
+			if wantOK := tc.wantStr != ""; ok != wantOK {
+				t.Errorf("got Components ok %t; want %t", ok, wantOK)
+			} else if ok && (c.Type != tc.t || c.Date != date || c.Serial != tc.i) {
+				t.Errorf("got Components %+v; want {Type:%v Date:%v Serial:%d}",
+					c, tc.t, date, tc.i)
+			}
+			if d, ok := id.Date(); ok != (tc.wantStr != "") || ok && d != date {
+				t.Errorf("got Date %v (ok: %t); want %v (ok: %t)", d, ok, date, tc.wantStr != "")
+			}
+			if serial, ok := id.Serial(); ok != (tc.wantStr != "") || ok && serial != tc.i {
+				t.Errorf("got Serial %d (ok: %t); want %d (ok: %t)", serial, ok, tc.i, tc.wantStr != "")
+			}
+		})
+	}
+}
+
+func TestIDSet_Len(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	types := []gosln.Type{
+		gosln.MustNewType("TestType_1"),
+		gosln.MustNewType("TestType_2"),
+		gosln.MustNewType("TestType_3"),
+	}
+	const NumID int64 = 20
+	randomIDs := func(rnd *rand.Rand, n int) []gosln.ID {
+		ids := make([]gosln.ID, n)
+		for i := range ids {
+			ids[i] = gosln.NewID(types[rnd.Intn(len(types))], date, rnd.Int63n(NumID))
+		}
+		return ids
+	}
+	randomSet := func(rnd *rand.Rand) gosln.IDSet {
+		s := gosln.NewIDSet()
+		s.Add(randomIDs(rnd, rnd.Intn(int(NumID)))...)
+		return s
+	}
+	recount := func(s gosln.IDSet) int {
+		var n int
+		s.Range(func(x gosln.ID) (cont bool) {
+			n++
+			return true
+		})
+		return n
+	}
+
+	ops := []struct {
+		name string
+		f    func(rnd *rand.Rand, s gosln.IDSet)
+	}{
+		{"Add", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.Add(randomIDs(rnd, rnd.Intn(5))...)
+		}},
+		{"Remove", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.Remove(randomIDs(rnd, rnd.Intn(5))...)
+		}},
+		{"Filter", func(rnd *rand.Rand, s gosln.IDSet) {
+			t := types[rnd.Intn(len(types))]
+			s.Filter(func(x gosln.ID) (keep bool) {
+				return x.Type() != t
+			})
+		}},
+		{"RemoveType", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.RemoveType(types[rnd.Intn(len(types))])
+		}},
+		{"Union", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.Union(randomSet(rnd))
+		}},
+		{"Intersect", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.Intersect(randomSet(rnd))
+		}},
+		{"Subtract", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.Subtract(randomSet(rnd))
+		}},
+		{"DisjunctiveUnion", func(rnd *rand.Rand, s gosln.IDSet) {
+			s.DisjunctiveUnion(randomSet(rnd))
+		}},
+		{"Clear", func(rnd *rand.Rand, s gosln.IDSet) {
+			if rnd.Intn(10) == 0 {
+				s.Clear()
+			}
+		}},
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	s := gosln.NewIDSet()
+	for i := 0; i < 1000; i++ {
+		op := ops[rnd.Intn(len(ops))]
+		op.f(rnd, s)
+		if n, want := s.Len(), recount(s); n != want {
+			t.Fatalf("step %d, after %s, got Len %d; want %d", i, op.name, n, want)
+		}
+	}
+}
+
+func TestFilteredIDSet(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	typ1, typ2 := gosln.MustNewType("TestType_1"), gosln.MustNewType("TestType_2")
+	ids := []gosln.ID{
+		gosln.NewID(typ1, date, 1),
+		gosln.NewID(typ1, date, 2),
+		gosln.NewID(typ2, date, 1),
+		gosln.NewID(typ2, date, 2),
+	}
+	src := gosln.NewIDSet()
+	src.Add(ids...)
+
+	testCases := []struct {
+		name string
+		keep func(id gosln.ID) bool
+		want []gosln.ID
+	}{
+		{"nil", nil, ids},
+		{"keep-none", func(id gosln.ID) bool { return false }, nil},
+		{"keep-typ1", func(id gosln.ID) bool { return id.Type() == typ1 }, ids[:2]},
+		{"keep-typ2", func(id gosln.ID) bool { return id.Type() == typ2 }, ids[2:]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gosln.FilteredIDSet(src, tc.keep)
+			if r.Len() != len(tc.want) {
+				t.Errorf("got Len %d; want %d", r.Len(), len(tc.want))
+			}
+			for _, id := range tc.want {
+				if !r.ContainsItem(id) {
+					t.Errorf("result does not contain %v", id)
+				}
+			}
+			if src.Len() != len(ids) {
+				t.Errorf("source modified, got Len %d; want %d", src.Len(), len(ids))
+			}
+			r.Clear()
+			if !src.ContainsItem(ids[0]) {
+				t.Error("source shares storage with the result")
+			}
+		})
+	}
+
+	if r := gosln.FilteredIDSet(nil, nil); r == nil || r.Len() != 0 {
+		t.Errorf("nil source - got %v; want an empty set", r)
+	}
+}
+
+func TestSortIDs(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	d1 := gosln.DateOfYearMonthDay(2022, time.December, 31)
+	d2 := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	// want is in the expected order.
+	want := []gosln.ID{
+		{},
+		gosln.NewID(typeA, d1, 9),
+		gosln.NewID(typeA, d1, 10),  // "A" > "9" lexicographically
+		gosln.NewID(typeA, d1, 64),  // "00" < "A" lexicographically
+		gosln.NewID(typeA, d2, 0),   // later date
+		gosln.NewID(typeA, d2, 100), // "a0"
+		gosln.NewID(typeB, d1, 0),
+		gosln.NewID(typeB, d2, 1),
+	}
+	ids := make([]gosln.ID, len(want))
+	rnd := rand.New(rand.NewSource(1))
+	for i, k := range rnd.Perm(len(want)) {
+		ids[i] = want[k]
+	}
+	gosln.SortIDs(ids)
+	for i := range ids {
+		if ids[i] != want[i] {
+			t.Fatalf("got %v; want %v", ids, want)
+		}
+	}
+	for i := range want {
+		for j := range want {
+			var wantCmp int
+			if i < j {
+				wantCmp = -1
+			} else if i > j {
+				wantCmp = 1
+			}
+			if got := want[i].Compare(want[j]); got != wantCmp {
+				t.Errorf("%v.Compare(%v) got %d; want %d", want[i], want[j], got, wantCmp)
+			}
+		}
+	}
+}
+
+func TestParseID(t *testing.T) {
+	typ := gosln.MustNewType("TestType_1")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	testCases := []struct {
+		s       string
+		want    gosln.ID
+		wantErr bool
+	}{
+		{"TestType_1#2023-071-0", gosln.NewID(typ, date, 0), false},
+		{"TestType_1#2023-071-_0", gosln.NewID(typ, date, 127), false},
+		{"TestType_1#2023-071-_---------6", gosln.NewID(typ, date, math.MaxInt64), false},
+		{"TestType_1#-5-001-1", gosln.NewID(typ, gosln.DateOfYearMonthDay(-5, time.January, 1), 1), false},
+		{"", gosln.ID{}, true},
+		{"TestType_1", gosln.ID{}, true},
+		{"TestType_1#", gosln.ID{}, true},
+		{"#2023-071-0", gosln.ID{}, true},
+		{"testType#2023-071-0", gosln.ID{}, true},
+		{"TestType_1#2023-071", gosln.ID{}, true},
+		{"TestType_1#2023-071-", gosln.ID{}, true},
+		{"TestType_1#2023-366-0", gosln.ID{}, true},
+		{"TestType_1#2023-071-0#", gosln.ID{}, true},
+		{"TestType_1#2023-071-_---------7", gosln.ID{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("s=%+q", tc.s), func(t *testing.T) {
+			id, err := gosln.ParseID(tc.s)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("got %v, nil error; want an error", id)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if id != tc.want {
+				t.Errorf("got %v; want %v", id, tc.want)
+			}
+		})
+	}
+}
+
+func TestTypeAndID_JSON(t *testing.T) {
+	typ := gosln.MustNewType("TestType_1")
+	id := gosln.NewID(typ, gosln.DateOfYearMonthDay(2023, time.March, 12), 127)
+	type S struct {
+		T  gosln.Type
+		ID gosln.ID
+	}
+
+	data, err := json.Marshal(S{T: typ, ID: id})
+	if err != nil {
+		t.Fatal("marshal -", err)
+	} else if got, want := string(data), `{"T":"TestType_1","ID":"TestType_1#2023-071-_0"}`; got != want {
+		t.Errorf("marshal - got %s; want %s", got, want)
+	}
+	var s S
+	if err = json.Unmarshal(data, &s); err != nil {
+		t.Fatal("unmarshal -", err)
+	} else if s.T != typ || s.ID != id {
+		t.Errorf("unmarshal - got %+v; want {T:%v ID:%v}", s, typ, id)
+	}
+
+	data, err = json.Marshal(S{})
+	if err != nil {
+		t.Fatal("marshal zero value -", err)
+	} else if got, want := string(data), `{"T":"","ID":""}`; got != want {
+		t.Errorf("marshal zero value - got %s; want %s", got, want)
+	}
+	s = S{T: typ, ID: id}
+	if err = json.Unmarshal(data, &s); err != nil {
+		t.Fatal("unmarshal zero value -", err)
+	} else if s.T.IsValid() || s.ID.IsValid() {
+		t.Errorf("unmarshal zero value - got %+v; want zero value", s)
+	}
+
+	for _, data := range []string{
+		`{"T":"testType"}`,
+		`{"T":1}`,
+		`{"ID":"TestType_1"}`,
+		`{"ID":"TestType_1#2023-366-0"}`,
+	} {
+		if err = json.Unmarshal([]byte(data), &s); err == nil {
+			t.Errorf("unmarshal %s - got nil error", data)
+		}
+	}
+}
+
+func TestIDSet_ToSliceAndToSortedSlice(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	want := []gosln.ID{
+		gosln.NewID(typeA, date, 9),
+		gosln.NewID(typeA, date, 10),
+		gosln.NewID(typeA, date, 64),
+		gosln.NewID(typeB, date, 0),
+	}
+	s := gosln.NewIDSet()
+	if slice := s.ToSlice(); slice == nil || len(slice) != 0 {
+		t.Errorf("empty set - got %v; want an empty non-nil slice", slice)
+	}
+	s.Add(want[3], want[1], want[0], want[2])
+
+	slice := s.ToSlice()
+	if len(slice) != len(want) {
+		t.Errorf("ToSlice - got %v; want %d IDs", slice, len(want))
+	}
+	for _, id := range slice {
+		if !s.ContainsItem(id) {
+			t.Errorf("ToSlice - got %v, which is not in the set", id)
+		}
+	}
+
+	sorted := s.ToSortedSlice()
+	if len(sorted) != len(want) {
+		t.Fatalf("ToSortedSlice - got %v; want %v", sorted, want)
+	}
+	for i := range sorted {
+		if sorted[i] != want[i] {
+			t.Fatalf("ToSortedSlice - got %v; want %v", sorted, want)
+		}
+	}
+}
+
+func TestIDSet_Clone(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	a0, a1 := gosln.NewID(typeA, date, 0), gosln.NewID(typeA, date, 1)
+	b0 := gosln.NewID(typeB, date, 0)
+	s := gosln.NewIDSet()
+	s.Add(a0, b0)
+
+	c := s.Clone()
+	if c.Len() != 2 || !c.ContainsItem(a0) || !c.ContainsItem(b0) {
+		t.Fatalf("got clone %v; want [%v %v]", c.ToSortedSlice(), a0, b0)
+	}
+	c.Add(a1) // type A already exists; it must not touch the inner map of s
+	c.Remove(b0)
+	if s.Len() != 2 || s.LenType(typeA) != 1 || s.ContainsItem(a1) || !s.ContainsItem(b0) {
+		t.Errorf("original modified through the clone, got %v; want [%v %v]",
+			s.ToSortedSlice(), a0, b0)
+	}
+	s.Clear()
+	if c.Len() != 2 || !c.ContainsItem(a0) || !c.ContainsItem(a1) {
+		t.Errorf("clone modified through the original, got %v; want [%v %v]",
+			c.ToSortedSlice(), a0, a1)
+	}
+}
+
+func TestIDSet_Equal(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	a0, a1 := gosln.NewID(typeA, date, 0), gosln.NewID(typeA, date, 1)
+	b0 := gosln.NewID(typeB, date, 0)
+	newSet := func(id ...gosln.ID) gosln.IDSet {
+		s := gosln.NewIDSet()
+		s.Add(id...)
+		return s
+	}
+
+	testCases := []struct {
+		name string
+		a    gosln.IDSet
+		b    gosln.IDSet
+		want bool
+	}{
+		{"empty-empty", newSet(), newSet(), true},
+		{"empty-nil", newSet(), nil, true},
+		{"nonempty-nil", newSet(a0), nil, false},
+		{"same", newSet(a0, a1, b0), newSet(b0, a1, a0), true},
+		{"subset", newSet(a0, a1), newSet(a0, a1, b0), false},
+		{"superset", newSet(a0, a1, b0), newSet(a0, a1), false},
+		{"same length", newSet(a0, a1), newSet(a0, b0), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b set.Set[gosln.ID]
+			if tc.b != nil {
+				b = tc.b
+			}
+			if got := tc.a.Equal(b); got != tc.want {
+				t.Errorf("got %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIDSet_ContainsSetAndContainsAny(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	a0, a1, a2 := gosln.NewID(typeA, date, 0), gosln.NewID(typeA, date, 1), gosln.NewID(typeA, date, 2)
+	b0, b1 := gosln.NewID(typeB, date, 0), gosln.NewID(typeB, date, 1)
+	newSet := func(id ...gosln.ID) gosln.IDSet {
+		s := gosln.NewIDSet()
+		s.Add(id...)
+		return s
+	}
+
+	testCases := []struct {
+		name    string
+		s       gosln.IDSet
+		c       gosln.IDSet
+		wantSet bool
+		wantAny bool
+	}{
+		{"nil", newSet(a0), nil, true, false},
+		{"empty", newSet(a0), newSet(), true, false},
+		{"empty set-empty", newSet(), newSet(), true, false},
+		{"empty set-nonempty", newSet(), newSet(a0), false, false},
+		{"all absent", newSet(a0, a1), newSet(a2, b0, b1), false, false},
+		{"all absent same type", newSet(a0), newSet(a1, a2), false, false},
+		{"mixed, present first", newSet(a0, b0), newSet(a0, a1, b1), false, true},
+		{"mixed, present last", newSet(a0, b0), newSet(a1, b1, b0), false, true},
+		{"subset of one type", newSet(a0, a1, a2), newSet(a0, a1, a2), true, true},
+		{"subset", newSet(a0, a1, b0), newSet(a1, b0), true, true},
+		{"superset", newSet(a0), newSet(a0, a1), false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var c set.Set[gosln.ID]
+			if tc.c != nil {
+				c = tc.c
+			}
+			if got := tc.s.ContainsSet(c); got != tc.wantSet {
+				t.Errorf("got ContainsSet %t; want %t", got, tc.wantSet)
+			}
+			if got := tc.s.ContainsAny(c); got != tc.wantAny {
+				t.Errorf("got ContainsAny %t; want %t", got, tc.wantAny)
+			}
+		})
+	}
+}
+
+func TestNewIDSetCap(t *testing.T) {
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	types := []gosln.Type{gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")}
+	for _, typeCapacity := range []int{-1, 0, 1, 10} {
+		t.Run(fmt.Sprintf("typeCapacity=%d", typeCapacity), func(t *testing.T) {
+			s := gosln.NewIDSetCap(typeCapacity)
+			if n := s.Len(); n != 0 {
+				t.Fatalf("got Len %d; want 0", n)
+			}
+			for _, typ := range types {
+				for i := int64(0); i < 3; i++ {
+					s.Add(gosln.NewID(typ, date, i))
+				}
+			}
+			if n, numType := s.Len(), s.NumType(); n != 6 || numType != 2 {
+				t.Errorf("got Len %d, NumType %d; want 6, 2", n, numType)
+			}
+			if id := gosln.NewID(types[1], date, 2); !s.ContainsItem(id) {
+				t.Errorf("%v not in the set", id)
+			}
+		})
+	}
+}
+
+func TestIDSet_RemoveType(t *testing.T) {
+	typeA, typeB, typeC := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB"), gosln.MustNewType("TypeC")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	s := gosln.NewIDSet()
+	s.Add(gosln.NewID(typeA, date, 0), gosln.NewID(typeA, date, 1), gosln.NewID(typeB, date, 0))
+
+	if n := s.RemoveType(typeC); n != 0 {
+		t.Errorf("remove absent type - got %d; want 0", n)
+	}
+	if n := s.RemoveType(typeA); n != 2 {
+		t.Errorf("remove TypeA - got %d; want 2", n)
+	}
+	if s.ContainsType(typeA) || s.LenType(typeA) != 0 {
+		t.Error("TypeA still in the set after RemoveType")
+	}
+	if n, numType := s.Len(), s.NumType(); n != 1 || numType != 1 {
+		t.Errorf("got Len %d, NumType %d; want 1, 1", n, numType)
+	}
+	if n := s.RemoveType(typeA); n != 0 {
+		t.Errorf("remove TypeA again - got %d; want 0", n)
+	}
+}
+
+func TestUnionIntersectAndDifferenceIDSet(t *testing.T) {
+	typeA, typeB := gosln.MustNewType("TypeA"), gosln.MustNewType("TypeB")
+	date := gosln.DateOfYearMonthDay(2023, time.March, 12)
+	a0, a1, a2 := gosln.NewID(typeA, date, 0), gosln.NewID(typeA, date, 1), gosln.NewID(typeA, date, 2)
+	b0 := gosln.NewID(typeB, date, 0)
+	newSet := func(id ...gosln.ID) gosln.IDSet {
+		s := gosln.NewIDSet()
+		s.Add(id...)
+		return s
+	}
+
+	testCases := []struct {
+		name          string
+		a, b          gosln.IDSet
+		wantUnion     gosln.IDSet
+		wantIntersect gosln.IDSet
+		wantDiff      gosln.IDSet
+	}{
+		{"nil-nil", nil, nil, newSet(), newSet(), newSet()},
+		{"nonempty-nil", newSet(a0, b0), nil, newSet(a0, b0), newSet(), newSet(a0, b0)},
+		{"nil-nonempty", nil, newSet(a0), newSet(a0), newSet(), newSet()},
+		{"disjoint", newSet(a0), newSet(a1, b0), newSet(a0, a1, b0), newSet(), newSet(a0)},
+		{"overlapping", newSet(a0, a1, b0), newSet(a1, a2), newSet(a0, a1, a2, b0), newSet(a1), newSet(a0, b0)},
+		{"same", newSet(a0, b0), newSet(b0, a0), newSet(a0, b0), newSet(a0, b0), newSet()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var a, b set.Set[gosln.ID]
+			var aBefore, bBefore gosln.IDSet
+			if tc.a != nil {
+				a, aBefore = tc.a, tc.a.Clone()
+			}
+			if tc.b != nil {
+				b, bBefore = tc.b, tc.b.Clone()
+			}
+			if got := gosln.UnionIDSet(a, b); !got.Equal(tc.wantUnion) {
+				t.Errorf("got union %v; want %v", got.ToSortedSlice(), tc.wantUnion.ToSortedSlice())
+			}
+			if got := gosln.IntersectIDSet(a, b); !got.Equal(tc.wantIntersect) {
+				t.Errorf("got intersection %v; want %v", got.ToSortedSlice(), tc.wantIntersect.ToSortedSlice())
+			}
+			if got := gosln.DifferenceIDSet(a, b); !got.Equal(tc.wantDiff) {
+				t.Errorf("got difference %v; want %v", got.ToSortedSlice(), tc.wantDiff.ToSortedSlice())
+			}
+			if tc.a != nil && !tc.a.Equal(aBefore) {
+				t.Errorf("a modified, got %v; want %v", tc.a.ToSortedSlice(), aBefore.ToSortedSlice())
+			}
+			if tc.b != nil && !tc.b.Equal(bBefore) {
+				t.Errorf("b modified, got %v; want %v", tc.b.ToSortedSlice(), bBefore.ToSortedSlice())
+			}
 		})
 	}
 }
@@ -19,7 +19,9 @@
 package gosln
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/donyori/gogo/container"
 	"github.com/donyori/gogo/container/mapping"
@@ -53,6 +55,7 @@ type validSet[Item comparable] struct {
 	s          set.Set[Item]
 	validateFn func(x Item) bool
 	errFn      func(x Item) error
+	compareFn  func(a, b Item) int
 }
 
 func _[Item comparable]() {
@@ -76,10 +79,20 @@ func _[Item comparable]() {
 //	func(x Item) error {
 //		return fmt.Errorf("item %v is invalid", x)
 //	}
+//
+// compareFn is a function to compare two items,
+// returning a negative integer if a < b, zero if a == b,
+// and a positive integer if a > b.
+// It is used by the method ToSortedSlice.
+// If compareFn is nil and x has method "Compare(Item) int",
+// the comparison uses that method.
+// If compareFn is nil and x has no such method,
+// the method ToSortedSlice panics.
 func newValidSet[Item comparable](
 	capacity int,
 	validateFn func(x Item) bool,
 	errFn func(x Item) error,
+	compareFn func(a, b Item) int,
 ) *validSet[Item] {
 	if validateFn == nil {
 		var x Item
@@ -96,10 +109,19 @@ func newValidSet[Item comparable](
 			return fmt.Errorf("item %v is invalid", x)
 		}
 	}
+	if compareFn == nil {
+		var x Item
+		if _, ok := any(x).(interface{ Compare(Item) int }); ok {
+			compareFn = func(a, b Item) int {
+				return any(a).(interface{ Compare(Item) int }).Compare(b)
+			}
+		}
+	}
 	return &validSet[Item]{
 		s:          mapset.New[Item](capacity, nil),
 		validateFn: validateFn,
 		errFn:      errFn,
+		compareFn:  compareFn,
 	}
 }
 
@@ -174,6 +196,60 @@ func (vs *validSet[Item]) Clear() {
 	vs.s.Clear()
 }
 
+// ToSortedSlice returns a new slice of the items in the set,
+// sorted in ascending order determined by the compare function
+// specified in newValidSet.
+//
+// It panics if no compare function is available.
+func (vs *validSet[Item]) ToSortedSlice() []Item {
+	if vs.compareFn == nil {
+		panic(errors.AutoMsg("compare function is unavailable"))
+	}
+	slice := make([]Item, 0, vs.s.Len())
+	vs.s.Range(func(x Item) (cont bool) {
+		slice = append(slice, x)
+		return true
+	})
+	sort.Slice(slice, func(i, j int) bool {
+		return vs.compareFn(slice[i], slice[j]) < 0
+	})
+	return slice
+}
+
+// MarshalJSON implements the interface encoding/json.Marshaler.
+//
+// It encodes the set as a JSON array of its items,
+// sorted as in the method ToSortedSlice.
+//
+// It panics if no compare function is available.
+func (vs *validSet[Item]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vs.ToSortedSlice())
+}
+
+// UnmarshalJSON implements the interface encoding/json.Unmarshaler.
+//
+// It accepts a JSON array of valid items,
+// and replaces the items in the set with them.
+// In particular, null is decoded as an empty set.
+//
+// It reports the specified error if any item is invalid,
+// in which case the set is not modified.
+func (vs *validSet[Item]) UnmarshalJSON(data []byte) error {
+	var items []Item
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return errors.AutoWrap(err)
+	}
+	for _, x := range items {
+		if !vs.validateFn(x) {
+			return errors.AutoWrap(vs.errFn(x))
+		}
+	}
+	vs.s.Clear()
+	vs.s.Add(items...)
+	return nil
+}
+
 // validateAllItemsInSet checks whether all items in s are valid.
 //
 // If any item is invalid, it panics with the specified error.
@@ -214,7 +290,7 @@ type validMap[Key comparable, Value any] struct {
 	keyValidateFn   func(key Key) bool
 	keyErrFn        func(key Key) error
 	valueValidateFn func(value Value) bool
-	valueErrFn      func(value Value) error
+	valueErrFn      func(key Key, value Value) error
 }
 
 func _[Key comparable, Value any]() {
@@ -239,6 +315,8 @@ func _[Key comparable, Value any]() {
 //
 // keyErrFn and valueErrFn are functions returning errors
 // for an invalid key and value, respectively.
+// valueErrFn also receives the key with which the invalid value
+// is about to be associated.
 // If keyErrFn is nil, newValidMap uses the following function instead:
 //
 //	func(key Key) error {
@@ -248,7 +326,7 @@ func _[Key comparable, Value any]() {
 // Similarly, if valueErrFn is nil,
 // newValidMap uses the following function instead:
 //
-//	func(value Value) error {
+//	func(key Key, value Value) error {
 //		return fmt.Errorf("value %v is invalid", value)
 //	}
 func newValidMap[Key comparable, Value any](
@@ -256,7 +334,7 @@ func newValidMap[Key comparable, Value any](
 	keyValidateFn func(key Key) bool,
 	keyErrFn func(key Key) error,
 	valueValidateFn func(value Value) bool,
-	valueErrFn func(value Value) error,
+	valueErrFn func(key Key, value Value) error,
 ) *validMap[Key, Value] {
 	var m mapping.GoMap[Key, Value]
 	if capacity > 0 {
@@ -288,7 +366,7 @@ func newValidMap[Key comparable, Value any](
 		}
 	}
 	if valueErrFn == nil {
-		valueErrFn = func(value Value) error {
+		valueErrFn = func(key Key, value Value) error {
 			return fmt.Errorf("value %v is invalid", value)
 		}
 	}
@@ -366,6 +444,20 @@ func (vm *validMap[Key, Value]) Clear() {
 	vm.m.Clear()
 }
 
+// clone returns a copy of vm with the same validation and error functions.
+//
+// The key-value pairs are copied shallowly.
+func (vm *validMap[Key, Value]) clone() *validMap[Key, Value] {
+	c := *vm
+	if vm.m != nil {
+		c.m = make(mapping.GoMap[Key, Value], len(vm.m))
+		for k, v := range vm.m {
+			c.m[k] = v
+		}
+	}
+	return &c
+}
+
 // validateKeyAndValue checks whether key and value are valid.
 //
 // If not, it panics with the specified error.
@@ -373,7 +465,7 @@ func (vm *validMap[Key, Value]) validateKeyAndValue(key Key, value Value) {
 	if !vm.keyValidateFn(key) {
 		panic(errors.AutoWrapSkip(vm.keyErrFn(key), 1))
 	} else if !vm.valueValidateFn(value) {
-		panic(errors.AutoWrapSkip(vm.valueErrFn(value), 1))
+		panic(errors.AutoWrapSkip(vm.valueErrFn(key, value), 1))
 	}
 }
 
@@ -389,7 +481,7 @@ func (vm *validMap[Key, Value]) validateAllKeysAndValuesInMap(
 		if !vm.keyValidateFn(x.Key) {
 			panic(errors.AutoWrapSkip(vm.keyErrFn(x.Key), 2))
 		} else if !vm.valueValidateFn(x.Value) {
-			panic(errors.AutoWrapSkip(vm.valueErrFn(x.Value), 2))
+			panic(errors.AutoWrapSkip(vm.valueErrFn(x.Key, x.Value), 2))
 		}
 		return true
 	})